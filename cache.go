@@ -0,0 +1,546 @@
+package gollmx
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores serialized responses for a CachingClient. Implementations must
+// be safe for concurrent use. A ttl of 0 means the entry does not expire.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// =============================================================================
+// In-Memory LRU Cache
+// =============================================================================
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry
+// once it holds capacity entries
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Front is the most recently used
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // Zero if the entry does not expire
+}
+
+// NewLRUCache creates an LRU cache. A capacity <= 0 defaults to 1000 entries.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value for key if present and not expired
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entry if full
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete removes key from the cache
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
+
+// =============================================================================
+// Filesystem Cache
+// =============================================================================
+
+// FileCache is a Cache that stores one JSON file per entry under a directory.
+// Expired entries are removed when they are next read.
+type FileCache struct {
+	dir string
+}
+
+type fileEntry struct {
+	ExpiresAt time.Time       `json:"expires_at,omitempty"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// NewFileCache creates a filesystem cache rooted at dir, creating it if needed
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Get returns the value for key if present and not expired
+func (c *FileCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entry fileEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry is treated as a miss and overwritten on the next Set
+		return nil, false, nil
+	}
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		c.Delete(ctx, key)
+		return nil, false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set stores value under key. Values must be valid JSON.
+func (c *FileCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := fileEntry{Value: value}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename so readers never see partial entries
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Delete removes key from the cache
+func (c *FileCache) Delete(ctx context.Context, key string) error {
+	err := os.Remove(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path shards entries into subdirectories by key prefix
+func (c *FileCache) path(key string) string {
+	shard := "_"
+	if len(key) >= 2 {
+		shard = key[:2]
+	}
+	return filepath.Join(c.dir, shard, key+".json")
+}
+
+// =============================================================================
+// Caching Client Wrapper
+// =============================================================================
+
+// CacheConfig configures a CachingClient
+type CacheConfig struct {
+	TTL   time.Duration // Entry lifetime; 0 means entries do not expire
+	Force bool          // Cache chat requests even when sampling is non-deterministic
+}
+
+// CacheOption is a function that configures caching
+type CacheOption func(*CacheConfig)
+
+// WithCacheTTL sets how long cached responses are served
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.TTL = ttl
+	}
+}
+
+// WithCacheForce caches chat requests regardless of their temperature
+func WithCacheForce(force bool) CacheOption {
+	return func(c *CacheConfig) {
+		c.Force = force
+	}
+}
+
+// CachingClient wraps an LLM client and serves repeated requests from a Cache.
+//
+// Chat requests are only cached when they are deterministic, i.e. when
+// Temperature is explicitly set to 0, unless WithCacheForce is used.
+// Embeddings are always cached. Completions are not cached. Responses served
+// from the cache have CacheHit set. Cache errors are treated as misses.
+type CachingClient struct {
+	client LLM
+	cache  Cache
+	config CacheConfig
+}
+
+// WithCache wraps an LLM client with response caching. A nil cache uses a
+// new in-memory LRU cache.
+func WithCache(client LLM, cache Cache, opts ...CacheOption) *CachingClient {
+	if cache == nil {
+		cache = NewLRUCache(0)
+	}
+	c := &CachingClient{client: client, cache: cache}
+	for _, opt := range opts {
+		opt(&c.config)
+	}
+	return c
+}
+
+// ID returns the provider identifier
+func (c *CachingClient) ID() string {
+	return c.client.ID()
+}
+
+// Name returns the provider name
+func (c *CachingClient) Name() string {
+	return c.client.Name()
+}
+
+// Version returns the client version
+func (c *CachingClient) Version() string {
+	return c.client.Version()
+}
+
+// BaseURL returns the API base URL
+func (c *CachingClient) BaseURL() string {
+	return c.client.BaseURL()
+}
+
+// Models returns available models
+func (c *CachingClient) Models() []Model {
+	return c.client.Models()
+}
+
+// GetModel returns a specific model
+func (c *CachingClient) GetModel(id string) (*Model, error) {
+	return c.client.GetModel(id)
+}
+
+// Chat performs a chat completion, serving cached responses when possible
+func (c *CachingClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	key, ok := c.chatKey(req)
+	if !ok {
+		return c.client.Chat(ctx, req)
+	}
+
+	if resp, ok := c.getChat(ctx, key); ok {
+		return resp, nil
+	}
+
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, key, resp)
+	return resp, nil
+}
+
+// ChatStream performs a streaming chat completion. Cached responses are
+// replayed as a stream; fresh streams are cached once they complete.
+func (c *CachingClient) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	key, ok := c.chatKey(req)
+	if !ok {
+		return c.client.ChatStream(ctx, req)
+	}
+
+	if resp, ok := c.getChat(ctx, key); ok {
+		return replayStream(resp), nil
+	}
+
+	stream, err := c.client.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectStream(ctx, stream, func(resp *ChatResponse) {
+		c.set(ctx, key, resp)
+	}), nil
+}
+
+// collectStream forwards stream and calls done with the collected response
+// if the stream completes without error
+func collectStream(ctx context.Context, stream *StreamReader, done func(*ChatResponse)) *StreamReader {
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+
+		var chunks []StreamChunk
+		var streamErr error
+
+		for {
+			chunk, ok := stream.Next()
+			if !ok {
+				streamErr = stream.Err()
+				break
+			}
+			chunks = append(chunks, *chunk)
+
+			select {
+			case ch <- *chunk:
+			case <-ctx.Done():
+				streamErr = ctx.Err()
+				stream.Drain()
+			}
+			if streamErr != nil {
+				break
+			}
+		}
+
+		if streamErr != nil {
+			if ctx.Err() == nil {
+				ch <- StreamChunk{Error: streamErr}
+			}
+			return
+		}
+
+		replay := make(chan StreamChunk, len(chunks))
+		for _, chunk := range chunks {
+			replay <- chunk
+		}
+		close(replay)
+		if resp, err := NewStreamReader(replay).Collect(); err == nil {
+			done(resp)
+		}
+	}()
+	return NewStreamReader(ch)
+}
+
+// Complete performs a text completion. Completions are not cached.
+func (c *CachingClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	return c.client.Complete(ctx, req)
+}
+
+// Embed generates embeddings, serving cached responses when possible
+func (c *CachingClient) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	key, err := cacheKey(OperationEmbed, c.client.ID(), req)
+	if err != nil {
+		return c.client.Embed(ctx, req)
+	}
+
+	if data, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		var resp EmbedResponse
+		if json.Unmarshal(data, &resp) == nil {
+			resp.CacheHit = true
+			return &resp, nil
+		}
+	}
+
+	resp, err := c.client.Embed(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	stored := *resp
+	stored.Raw = nil
+	c.set(ctx, key, &stored)
+	return resp, nil
+}
+
+// HasFeature checks if a feature is supported
+func (c *CachingClient) HasFeature(feature Feature) bool {
+	return c.client.HasFeature(feature)
+}
+
+// Features returns all supported features
+func (c *CachingClient) Features() []Feature {
+	return c.client.Features()
+}
+
+// SetOption sets a provider-specific option
+func (c *CachingClient) SetOption(key string, value interface{}) error {
+	return c.client.SetOption(key, value)
+}
+
+// GetOption gets a provider-specific option
+func (c *CachingClient) GetOption(key string) (interface{}, bool) {
+	return c.client.GetOption(key)
+}
+
+// Unwrap returns the underlying LLM client
+func (c *CachingClient) Unwrap() LLM {
+	return c.client
+}
+
+// Cache returns the underlying cache
+func (c *CachingClient) Cache() Cache {
+	return c.cache
+}
+
+// chatKey returns the cache key for req, or false if req is not cacheable
+func (c *CachingClient) chatKey(req *ChatRequest) (string, bool) {
+	if !c.config.Force && (req.Temperature == nil || *req.Temperature != 0) {
+		return "", false
+	}
+
+	// Streaming and non-streaming requests share entries
+	normalized := *req
+	normalized.Stream = false
+
+	key, err := cacheKey(OperationChat, c.client.ID(), &normalized)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+func (c *CachingClient) getChat(ctx context.Context, key string) (*ChatResponse, bool) {
+	data, ok, err := c.cache.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+	var resp ChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	resp.CacheHit = true
+	return &resp, true
+}
+
+// set stores a response, dropping provider-specific data that may not
+// round-trip through JSON. Errors are ignored; the response is simply not
+// cached.
+func (c *CachingClient) set(ctx context.Context, key string, resp interface{}) {
+	if chat, ok := resp.(*ChatResponse); ok {
+		stored := *chat
+		stored.Raw = nil
+		resp = &stored
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	c.cache.Set(ctx, key, data, c.config.TTL)
+}
+
+// cacheKey hashes the operation, provider and request into a cache key
+func cacheKey(operation, provider string, req interface{}) (string, error) {
+	data, err := json.Marshal(struct {
+		Operation string      `json:"operation"`
+		Provider  string      `json:"provider"`
+		Request   interface{} `json:"request"`
+	}{operation, provider, req})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replayStream returns a stream that yields a cached response as a content
+// chunk followed by a final chunk with the finish reason and usage
+func replayStream(resp *ChatResponse) *StreamReader {
+	ch := make(chan StreamChunk, 2)
+
+	chunk := StreamChunk{
+		ID:        resp.ID,
+		Provider:  resp.Provider,
+		Model:     resp.Model,
+		Citations: resp.Citations,
+		Documents: resp.Documents,
+		CacheHit:  true,
+	}
+	final := chunk
+	final.Citations = nil
+	final.Documents = nil
+	final.Usage = resp.Usage
+
+	chunk.Content = resp.GetContent()
+	chunk.ToolCalls = resp.GetToolCalls()
+	if len(resp.Choices) > 0 {
+		final.FinishReason = resp.Choices[0].FinishReason
+	}
+	if final.FinishReason == "" {
+		final.FinishReason = "stop"
+	}
+
+	ch <- chunk
+	ch <- final
+	close(ch)
+	return NewStreamReader(ch)
+}
+
+// Ensure CachingClient implements LLM interface
+var _ LLM = (*CachingClient)(nil)
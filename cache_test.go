@@ -0,0 +1,269 @@
+package gollmx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cacheLLM counts calls and returns scripted responses
+type cacheLLM struct {
+	mockLLM
+	mu     sync.Mutex
+	calls  int
+	chat   *ChatResponse
+	chunks []StreamChunk
+	embed  *EmbedResponse
+}
+
+func (m *cacheLLM) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func (m *cacheLLM) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	return m.chat, nil
+}
+
+func (m *cacheLLM) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	ch := make(chan StreamChunk, len(m.chunks))
+	for _, chunk := range m.chunks {
+		ch <- chunk
+	}
+	close(ch)
+	return NewStreamReader(ch), nil
+}
+
+func (m *cacheLLM) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	return m.embed, nil
+}
+
+func deterministicRequest(content string) *ChatRequest {
+	temperature := 0.0
+	return &ChatRequest{
+		Model:       "model",
+		Messages:    []Message{{Role: RoleUser, Content: content}},
+		Temperature: &temperature,
+	}
+}
+
+func newCacheLLM() *cacheLLM {
+	return &cacheLLM{
+		mockLLM: mockLLM{id: "test"},
+		chat: &ChatResponse{
+			ID:      "resp-1",
+			Model:   "model",
+			Choices: []Choice{{Message: Message{Role: RoleAssistant, Content: "Hello"}, FinishReason: "stop"}},
+			Usage:   Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+		},
+	}
+}
+
+func TestCachingClientChat(t *testing.T) {
+	inner := newCacheLLM()
+	client := WithCache(inner, NewLRUCache(10))
+	ctx := context.Background()
+
+	first, err := client.Chat(ctx, deterministicRequest("Hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.CacheHit {
+		t.Error("expected the first response to be a miss")
+	}
+
+	second, err := client.Chat(ctx, deterministicRequest("Hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !second.CacheHit {
+		t.Error("expected the second response to be a cache hit")
+	}
+	if second.GetContent() != "Hello" || second.Usage != first.Usage {
+		t.Errorf("expected the cached response, got %+v", second)
+	}
+	if inner.count() != 1 {
+		t.Errorf("expected 1 upstream call, got %d", inner.count())
+	}
+
+	if _, err := client.Chat(ctx, deterministicRequest("Bye")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.count() != 2 {
+		t.Errorf("expected a different request to miss, got %d calls", inner.count())
+	}
+}
+
+func TestCachingClientSkipsNonDeterministic(t *testing.T) {
+	ctx := context.Background()
+	temperature := 0.7
+	req := &ChatRequest{Model: "model", Temperature: &temperature}
+
+	inner := newCacheLLM()
+	client := WithCache(inner, nil)
+	client.Chat(ctx, req)
+	resp, _ := client.Chat(ctx, req)
+	client.Chat(ctx, &ChatRequest{Model: "model"})
+	if inner.count() != 3 || resp.CacheHit {
+		t.Errorf("expected non-deterministic requests to bypass the cache, got %d calls", inner.count())
+	}
+
+	inner = newCacheLLM()
+	client = WithCache(inner, nil, WithCacheForce(true))
+	client.Chat(ctx, req)
+	resp, _ = client.Chat(ctx, req)
+	if inner.count() != 1 || !resp.CacheHit {
+		t.Errorf("expected forced caching, got %d calls", inner.count())
+	}
+}
+
+func TestCachingClientChatStream(t *testing.T) {
+	inner := newCacheLLM()
+	inner.chunks = []StreamChunk{
+		{ID: "s1", Model: "model", Content: "Hel"},
+		{ID: "s1", Model: "model", Content: "lo"},
+		{ID: "s1", Model: "model", FinishReason: "stop", Usage: Usage{PromptTokens: 2, CompletionTokens: 2, TotalTokens: 4}},
+	}
+	client := WithCache(inner, nil)
+	ctx := context.Background()
+
+	stream, err := client.ChatStream(ctx, deterministicRequest("Hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Collect(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Streamed responses also serve non-streaming requests
+	req := deterministicRequest("Hi")
+	req.Stream = true
+	stream, err = client.ChatStream(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.CacheHit {
+		t.Error("expected a replayed stream to be a cache hit")
+	}
+	if resp.GetContent() != "Hello" || resp.Choices[0].FinishReason != "stop" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("unexpected replayed response: %+v", resp)
+	}
+
+	chat, err := client.Chat(ctx, deterministicRequest("Hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !chat.CacheHit || chat.GetContent() != "Hello" {
+		t.Errorf("expected the streamed response to be cached for Chat, got %+v", chat)
+	}
+	if inner.count() != 1 {
+		t.Errorf("expected 1 upstream call, got %d", inner.count())
+	}
+}
+
+func TestCachingClientEmbed(t *testing.T) {
+	inner := newCacheLLM()
+	inner.embed = &EmbedResponse{Model: "embed", Embeddings: []Embedding{{Index: 0, Vector: []float64{0.1, 0.2}}}}
+	client := WithCache(inner, nil)
+	ctx := context.Background()
+
+	req := &EmbedRequest{Model: "embed", Input: []string{"a"}}
+	client.Embed(ctx, req)
+	resp, err := client.Embed(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.CacheHit || len(resp.Embeddings) != 1 || resp.Embeddings[0].Vector[1] != 0.2 {
+		t.Errorf("expected a cached embedding, got %+v", resp)
+	}
+	if inner.count() != 1 {
+		t.Errorf("expected 1 upstream call, got %d", inner.count())
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", []byte("1"), 0)
+	cache.Set(ctx, "b", []byte("2"), 0)
+	cache.Get(ctx, "a") // "b" is now least recently used
+	cache.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("expected 'b' to be evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Error("expected 'a' to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	cache := NewLRUCache(10)
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", []byte("1"), 10*time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Fatal("expected a hit before expiry")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Error("expected a miss after expiry")
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "abcdef", []byte(`{"x":1}`), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ab", "abcdef.json")); err != nil {
+		t.Errorf("expected a sharded entry file: %v", err)
+	}
+
+	// A new cache over the same directory sees the entry
+	reopened, _ := NewFileCache(dir)
+	value, ok, err := reopened.Get(ctx, "abcdef")
+	if err != nil || !ok || string(value) != `{"x":1}` {
+		t.Errorf("expected the stored value, got %q (ok=%v, err=%v)", value, ok, err)
+	}
+
+	cache.Set(ctx, "expired", []byte(`1`), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "expired"); ok {
+		t.Error("expected a miss after expiry")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ex", "expired.json")); !os.IsNotExist(err) {
+		t.Error("expected the expired entry to be removed")
+	}
+
+	if err := cache.Delete(ctx, "missing"); err != nil {
+		t.Errorf("expected deleting a missing key to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package gollmx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AllFeatures lists every Feature, in the column order of a
+// CapabilityMatrix
+var AllFeatures = []Feature{
+	FeatureChat,
+	FeatureCompletion,
+	FeatureEmbedding,
+	FeatureStreaming,
+	FeatureVision,
+	FeatureTools,
+	FeatureJSON,
+	FeatureSystemPrompt,
+	FeatureRerank,
+}
+
+// =============================================================================
+// Capability Checks
+// =============================================================================
+
+// ChatFeatures returns the model features a chat request needs: chat, plus
+// streaming, vision for image parts, tools, JSON for a JSON response format
+// and system prompts for system messages
+func ChatFeatures(req *ChatRequest, stream bool) []Feature {
+	features := []Feature{FeatureChat}
+	if stream {
+		features = append(features, FeatureStreaming)
+	}
+
+	vision, system := false, false
+	for _, m := range req.Messages {
+		if m.Role == RoleSystem {
+			system = true
+		}
+		if parts, ok := m.Content.([]ContentPart); ok {
+			for _, part := range parts {
+				if part.Type == "image_url" || part.Type == "image_base64" {
+					vision = true
+				}
+			}
+		}
+	}
+	if vision {
+		features = append(features, FeatureVision)
+	}
+	if len(req.Tools) > 0 {
+		features = append(features, FeatureTools)
+	}
+	if req.ResponseFormat != nil && strings.HasPrefix(req.ResponseFormat.Type, "json") {
+		features = append(features, FeatureJSON)
+	}
+	if system {
+		features = append(features, FeatureSystemPrompt)
+	}
+	return features
+}
+
+// CheckFeatures returns an ErrorTypeUnsupportedFeature error naming the
+// features in features that model lacks. A model without feature metadata
+// passes, since its capabilities are unknown.
+func CheckFeatures(model *Model, features ...Feature) error {
+	if model == nil || len(model.Features) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, f := range features {
+		if !model.SupportsFeature(f) {
+			missing = append(missing, string(f))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &APIError{
+		Type:     ErrorTypeUnsupportedFeature,
+		Provider: model.Provider,
+		Message:  fmt.Sprintf("model %s does not support %s", model.ID, strings.Join(missing, ", ")),
+		Param:    "model",
+	}
+}
+
+// CheckModel looks up model with client.GetModel and checks that it has
+// features. Chat models pass a completion check, since providers serve
+// completions with chat. Models the client has no metadata for pass.
+func CheckModel(client LLM, model string, features ...Feature) error {
+	return checkModel(client, lookupModel(client, model), features)
+}
+
+// lookupModel returns the client's metadata for model, or nil if it has
+// none. Tagged local models such as "llama3.2:3b" share the untagged
+// metadata.
+func lookupModel(client LLM, model string) *Model {
+	if m, err := client.GetModel(model); err == nil && m != nil {
+		return m
+	}
+	base, _, tagged := strings.Cut(model, ":")
+	if !tagged {
+		return nil
+	}
+	if m, err := client.GetModel(base); err == nil && m != nil {
+		return m
+	}
+	return nil
+}
+
+func checkModel(client LLM, m *Model, features []Feature) error {
+	if m == nil {
+		return nil
+	}
+
+	if m.SupportsFeature(FeatureChat) {
+		for i, f := range features {
+			if f == FeatureCompletion {
+				features = append(features[:i:i], features[i+1:]...)
+				break
+			}
+		}
+	}
+	if err := CheckFeatures(m, features...); err != nil {
+		err.(*APIError).Provider = client.ID()
+		return err
+	}
+	return nil
+}
+
+// CheckModel checks model like the package-level CheckModel when capability
+// checks are enabled with WithCapabilityChecks(true). A deprecated model is
+// reported to GetLogger the first time it is requested either way.
+// Providers call it before sending a request.
+func (c *Config) CheckModel(client LLM, model string, features ...Feature) error {
+	m := lookupModel(client, model)
+	if m != nil && m.Deprecated {
+		DefaultModelRegistry.warnOnce(context.Background(), ModelRef{Provider: client.ID(), Model: m.ID}, c.GetLogger())
+	}
+	if !c.CapabilityChecks {
+		return nil
+	}
+	return checkModel(client, m, features)
+}
+
+// =============================================================================
+// Capability Matrix
+// =============================================================================
+
+// CapabilityMatrix is a table of models by features, e.g. for an admin UI
+type CapabilityMatrix struct {
+	Features []Feature       `json:"features"` // Columns, in AllFeatures order
+	Rows     []CapabilityRow `json:"rows"`
+}
+
+// CapabilityRow is the features of one model
+type CapabilityRow struct {
+	Provider   string           `json:"provider"`
+	Model      string           `json:"model"`
+	Name       string           `json:"name,omitempty"`
+	Deprecated bool             `json:"deprecated,omitempty"`
+	Supports   map[Feature]bool `json:"supports"`
+}
+
+// NewCapabilityMatrix builds a matrix of models. Columns are AllFeatures
+// followed by any other features the models list.
+func NewCapabilityMatrix(models []Model) *CapabilityMatrix {
+	matrix := &CapabilityMatrix{
+		Features: append([]Feature(nil), AllFeatures...),
+		Rows:     make([]CapabilityRow, len(models)),
+	}
+
+	for i, m := range models {
+		row := CapabilityRow{
+			Provider:   m.Provider,
+			Model:      m.ID,
+			Name:       m.Name,
+			Deprecated: m.Deprecated,
+			Supports:   make(map[Feature]bool, len(matrix.Features)),
+		}
+		for _, f := range m.Features {
+			if !containsFeature(matrix.Features, f) {
+				matrix.Features = append(matrix.Features, f)
+			}
+		}
+		for _, f := range matrix.Features {
+			row.Supports[f] = m.SupportsFeature(f)
+		}
+		matrix.Rows[i] = row
+	}
+
+	// Rows built before a new column was added report it as unsupported
+	for _, row := range matrix.Rows {
+		for _, f := range matrix.Features {
+			if _, ok := row.Supports[f]; !ok {
+				row.Supports[f] = false
+			}
+		}
+	}
+	return matrix
+}
+
+// Capabilities returns the capability matrix of every model in
+// DefaultModelRegistry
+func Capabilities() *CapabilityMatrix {
+	return DefaultModelRegistry.CapabilityMatrix()
+}
+
+// CapabilityMatrix returns the capability matrix of the registered models
+func (r *ModelRegistry) CapabilityMatrix() *CapabilityMatrix {
+	return NewCapabilityMatrix(r.Models())
+}
+
+// Supports reports whether a model supports a feature. It returns false for
+// models not in the matrix.
+func (m *CapabilityMatrix) Supports(provider, model string, feature Feature) bool {
+	for _, row := range m.Rows {
+		if row.Provider == provider && row.Model == model {
+			return row.Supports[feature]
+		}
+	}
+	return false
+}
+
+// ModelsWith returns the rows of models supporting every given feature
+func (m *CapabilityMatrix) ModelsWith(features ...Feature) []CapabilityRow {
+	var rows []CapabilityRow
+	for _, row := range m.Rows {
+		ok := true
+		for _, f := range features {
+			ok = ok && row.Supports[f]
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func containsFeature(features []Feature, f Feature) bool {
+	for _, feature := range features {
+		if feature == f {
+			return true
+		}
+	}
+	return false
+}
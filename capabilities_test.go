@@ -0,0 +1,183 @@
+package gollmx
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// catalogLLM serves model metadata from a fixed list
+type catalogLLM struct {
+	mockLLM
+	models []Model
+}
+
+func (m *catalogLLM) GetModel(id string) (*Model, error) {
+	for _, model := range m.models {
+		if model.ID == id {
+			return &model, nil
+		}
+	}
+	return nil, NewAPIError(ErrorTypeModelNotFound, m.id, "model not found: "+id)
+}
+
+func newCatalogLLM() *catalogLLM {
+	return &catalogLLM{
+		mockLLM: mockLLM{id: "catalog"},
+		models: []Model{
+			{ID: "chat", Features: []Feature{FeatureChat, FeatureStreaming, FeatureSystemPrompt}},
+			{ID: "vision", Features: []Feature{FeatureChat, FeatureStreaming, FeatureVision, FeatureTools, FeatureJSON, FeatureSystemPrompt}},
+			{ID: "embed", Features: []Feature{FeatureEmbedding}},
+			{ID: "unknown"},
+		},
+	}
+}
+
+func TestChatFeatures(t *testing.T) {
+	req := &ChatRequest{
+		Messages: []Message{
+			{Role: RoleSystem, Content: "Be brief."},
+			{Role: RoleUser, Content: []ContentPart{TextContent("What is this?"), ImageURLContent("https://example.com/a.png", "")}},
+		},
+		Tools:          []Tool{{Type: "function", Function: Function{Name: "lookup"}}},
+		ResponseFormat: &ResponseFormat{Type: "json_schema"},
+	}
+
+	features := ChatFeatures(req, true)
+	expected := []Feature{FeatureChat, FeatureStreaming, FeatureVision, FeatureTools, FeatureJSON, FeatureSystemPrompt}
+	if len(features) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, features)
+	}
+	for i := range expected {
+		if features[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, features)
+			break
+		}
+	}
+
+	plain := ChatFeatures(&ChatRequest{Messages: []Message{{Role: RoleUser, Content: "Hi"}}}, false)
+	if len(plain) != 1 || plain[0] != FeatureChat {
+		t.Errorf("expected only chat, got %v", plain)
+	}
+}
+
+func TestCheckModel(t *testing.T) {
+	client := newCatalogLLM()
+	vision := ChatFeatures(&ChatRequest{
+		Messages: []Message{{Role: RoleUser, Content: []ContentPart{ImageURLContent("https://example.com/a.png", "")}}},
+	}, false)
+
+	err := CheckModel(client, "chat", vision...)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Type != ErrorTypeUnsupportedFeature {
+		t.Fatalf("expected unsupported_feature error, got %v", err)
+	}
+	if apiErr.Provider != "catalog" || !strings.Contains(apiErr.Message, "vision") {
+		t.Errorf("expected the provider and missing feature in the error, got %+v", apiErr)
+	}
+
+	tests := []struct {
+		model    string
+		features []Feature
+	}{
+		{"vision", vision},
+		{"vision:7b", vision},                  // Tagged models share metadata
+		{"chat", []Feature{FeatureCompletion}}, // Completions are served with chat
+		{"embed", []Feature{FeatureEmbedding}},
+		{"unknown", vision},    // No feature metadata
+		{"not-listed", vision}, // No metadata at all
+	}
+	for _, tt := range tests {
+		if err := CheckModel(client, tt.model, tt.features...); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.model, err)
+		}
+	}
+
+	if err := CheckModel(client, "embed", FeatureChat); err == nil {
+		t.Error("expected an embedding model to reject chat")
+	}
+
+	config := DefaultConfig()
+	config.Apply(WithCapabilityChecks(true))
+	if err := config.CheckModel(client, "embed", FeatureChat); err == nil {
+		t.Error("expected enabled checks to reject chat with an embedding model")
+	}
+	if err := DefaultConfig().CheckModel(client, "embed", FeatureChat); err != nil {
+		t.Errorf("expected checks to be disabled by default, got %v", err)
+	}
+}
+
+func TestConfigCheckModelWarnsDeprecated(t *testing.T) {
+	client := &catalogLLM{
+		mockLLM: mockLLM{id: "catalog-deprecated"},
+		models: []Model{
+			{ID: "old", Features: []Feature{FeatureChat}, Deprecated: true},
+			{ID: "new", Features: []Feature{FeatureChat}},
+		},
+	}
+	logger, buf := newTestLogger(slog.LevelInfo)
+	config := DefaultConfig()
+	config.Apply(WithLogger(logger))
+
+	for _, model := range []string{"old", "old", "old:latest", "new"} {
+		if err := config.CheckModel(client, model, FeatureChat); err != nil {
+			t.Errorf("%s: unexpected error: %v", model, err)
+		}
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected one deprecation warning, got %v", records)
+	}
+	if records[0]["level"] != "WARN" || records[0]["provider"] != "catalog-deprecated" || records[0]["model"] != "old" {
+		t.Errorf("unexpected record: %v", records[0])
+	}
+}
+
+func TestCapabilityMatrix(t *testing.T) {
+	matrix := NewCapabilityMatrix([]Model{
+		{ID: "a", Provider: "p", Features: []Feature{FeatureChat, FeatureVision}},
+		{ID: "b", Provider: "p", Features: []Feature{FeatureEmbedding, Feature("audio")}, Deprecated: true},
+		{ID: "c", Provider: "q", Features: []Feature{FeatureChat}},
+	})
+
+	if len(matrix.Features) != len(AllFeatures)+1 || matrix.Features[len(AllFeatures)] != "audio" {
+		t.Errorf("expected AllFeatures plus audio, got %v", matrix.Features)
+	}
+	if !matrix.Supports("p", "a", FeatureVision) || matrix.Supports("q", "c", FeatureVision) {
+		t.Error("expected vision for a only")
+	}
+	if matrix.Supports("p", "missing", FeatureChat) {
+		t.Error("expected false for a model not in the matrix")
+	}
+	if supported, ok := matrix.Rows[0].Supports["audio"]; !ok || supported {
+		t.Errorf("expected every row to have every column, got %v", matrix.Rows[0].Supports)
+	}
+	if !matrix.Rows[1].Deprecated {
+		t.Error("expected b to be marked deprecated")
+	}
+
+	chat := matrix.ModelsWith(FeatureChat)
+	if len(chat) != 2 || chat[0].Model != "a" || chat[1].Model != "c" {
+		t.Errorf("expected a and c, got %v", chat)
+	}
+
+	data, err := json.Marshal(matrix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"supports":{"audio":false,"chat":true`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestModelRegistryCapabilityMatrix(t *testing.T) {
+	matrix := newTestModelRegistry().CapabilityMatrix()
+	if len(matrix.Rows) != 8 {
+		t.Fatalf("expected 8 rows, got %d", len(matrix.Rows))
+	}
+	if !matrix.Supports("alpha", "alpha-embed", FeatureEmbedding) || matrix.Supports("alpha", "alpha-embed", FeatureChat) {
+		t.Error("expected alpha-embed to be embedding only")
+	}
+}
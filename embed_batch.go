@@ -0,0 +1,266 @@
+package gollmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmbedLimits are the per-request limits of a provider's embedding API.
+// Zero means no limit.
+type EmbedLimits struct {
+	MaxItems  int // Inputs per request
+	MaxTokens int // Total input tokens per request
+}
+
+// DefaultEmbedLimits returns the documented embedding limits of a provider
+func DefaultEmbedLimits(provider string) EmbedLimits {
+	switch provider {
+	case "openai":
+		return EmbedLimits{MaxItems: 2048, MaxTokens: 300000}
+	case "cohere":
+		return EmbedLimits{MaxItems: 96}
+	case "google":
+		return EmbedLimits{MaxItems: 100}
+	case "mistral":
+		return EmbedLimits{MaxTokens: 16384}
+	default:
+		return EmbedLimits{}
+	}
+}
+
+// EmbedBatchFailure describes a batch that could not be embedded
+type EmbedBatchFailure struct {
+	Start int // Index of the first input in the batch
+	End   int // Index after the last input in the batch
+	Err   error
+}
+
+// EmbedBatchError is returned by EmbedBatcher.Embed when some batches fail.
+// The accompanying response holds the embeddings of the other batches.
+type EmbedBatchError struct {
+	Failures []EmbedBatchFailure
+}
+
+func (e *EmbedBatchError) Error() string {
+	var parts []string
+	for _, f := range e.Failures {
+		parts = append(parts, fmt.Sprintf("inputs %d-%d: %v", f.Start, f.End-1, f.Err))
+	}
+	return fmt.Sprintf("%d embedding batch(es) failed: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the batch errors
+func (e *EmbedBatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// EmbedBatchOption is a function that configures an EmbedBatcher
+type EmbedBatchOption func(*EmbedBatcher)
+
+// WithEmbedLimits overrides the provider's default limits
+func WithEmbedLimits(limits EmbedLimits) EmbedBatchOption {
+	return func(b *EmbedBatcher) {
+		b.limits = limits
+	}
+}
+
+// WithEmbedConcurrency sets how many batches are embedded at once
+func WithEmbedConcurrency(n int) EmbedBatchOption {
+	return func(b *EmbedBatcher) {
+		b.concurrency = n
+	}
+}
+
+// WithEmbedRateLimiter acquires a token from limiter before each batch
+func WithEmbedRateLimiter(limiter *RateLimiter) EmbedBatchOption {
+	return func(b *EmbedBatcher) {
+		b.limiter = limiter
+	}
+}
+
+// WithEmbedTokenCounter sets the function used to count input tokens. The
+// default estimates four characters per token.
+func WithEmbedTokenCounter(count func(string) int) EmbedBatchOption {
+	return func(b *EmbedBatcher) {
+		b.countTokens = count
+	}
+}
+
+// EmbedBatcher splits large embedding requests into batches that fit a
+// provider's limits, embeds them concurrently and reassembles the results
+// in input order
+type EmbedBatcher struct {
+	client      LLM
+	limits      EmbedLimits
+	concurrency int
+	limiter     *RateLimiter
+	countTokens func(string) int
+}
+
+// NewEmbedBatcher creates a batcher using the default limits of client's
+// provider
+func NewEmbedBatcher(client LLM, opts ...EmbedBatchOption) *EmbedBatcher {
+	b := &EmbedBatcher{
+		client:      client,
+		limits:      DefaultEmbedLimits(client.ID()),
+		concurrency: 4,
+		countTokens: EstimateTokens,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.concurrency <= 0 {
+		b.concurrency = 1
+	}
+	return b
+}
+
+// EmbedBatch is a contiguous range of inputs sent in one embedding request
+type EmbedBatch struct {
+	Start int // Index of the first input in the batch
+	End   int // Index after the last input in the batch
+}
+
+// Embed embeds every input of req. If some batches fail, the embeddings of
+// the others are returned together with an *EmbedBatchError.
+func (b *EmbedBatcher) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	batches := SplitEmbedBatches(req.Input, b.limits, b.countTokens)
+	if len(batches) <= 1 {
+		if err := b.limiter.Acquire(ctx); err != nil {
+			return nil, err
+		}
+		return b.client.Embed(ctx, req)
+	}
+
+	responses := make([]*EmbedResponse, len(batches))
+	errs := make([]error, len(batches))
+
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch EmbedBatch) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			if err := b.limiter.Acquire(ctx); err != nil {
+				errs[i] = err
+				return
+			}
+
+			batchReq := *req
+			batchReq.Input = req.Input[batch.Start:batch.End]
+			responses[i], errs[i] = b.client.Embed(ctx, &batchReq)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	return b.assemble(batches, responses, errs)
+}
+
+// assemble merges batch responses in input order, offsetting each
+// embedding's index by the position of its batch
+func (b *EmbedBatcher) assemble(batches []EmbedBatch, responses []*EmbedResponse, errs []error) (*EmbedResponse, error) {
+	result := &EmbedResponse{Provider: b.client.ID()}
+	var batchErr EmbedBatchError
+
+	for i, batch := range batches {
+		resp := responses[i]
+		if errs[i] == nil && resp == nil {
+			errs[i] = errors.New("empty embedding response")
+		}
+		if errs[i] != nil {
+			batchErr.Failures = append(batchErr.Failures, EmbedBatchFailure{Start: batch.Start, End: batch.End, Err: errs[i]})
+			continue
+		}
+
+		if result.Model == "" {
+			result.Model = resp.Model
+		}
+		indexed := validIndices(resp.Embeddings, batch.End-batch.Start)
+		for j, emb := range resp.Embeddings {
+			if !indexed {
+				emb.Index = j
+			}
+			emb.Index += batch.Start
+			result.Embeddings = append(result.Embeddings, emb)
+		}
+		result.Usage.PromptTokens += resp.Usage.PromptTokens
+		result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		result.Usage.TotalTokens += resp.Usage.TotalTokens
+	}
+
+	sort.Slice(result.Embeddings, func(i, j int) bool {
+		return result.Embeddings[i].Index < result.Embeddings[j].Index
+	})
+
+	if len(batchErr.Failures) == len(batches) {
+		return nil, &batchErr
+	}
+	if len(batchErr.Failures) > 0 {
+		return result, &batchErr
+	}
+	return result, nil
+}
+
+// SplitEmbedBatches groups inputs into contiguous batches within limits.
+// Tokens are counted with count, or EstimateTokens if nil. An input that
+// exceeds the token limit on its own is placed in a batch alone.
+func SplitEmbedBatches(inputs []string, limits EmbedLimits, count func(string) int) []EmbedBatch {
+	if count == nil {
+		count = EstimateTokens
+	}
+
+	var batches []EmbedBatch
+	start, tokens := 0, 0
+
+	for i, input := range inputs {
+		n := 0
+		if limits.MaxTokens > 0 {
+			n = count(input)
+		}
+		full := limits.MaxItems > 0 && i-start >= limits.MaxItems
+		over := limits.MaxTokens > 0 && tokens+n > limits.MaxTokens
+		if i > start && (full || over) {
+			batches = append(batches, EmbedBatch{start, i})
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	if start < len(inputs) {
+		batches = append(batches, EmbedBatch{start, len(inputs)})
+	}
+	return batches
+}
+
+// validIndices reports whether every embedding has a distinct index below n.
+// Otherwise embeddings are assumed to be in input order.
+func validIndices(embeddings []Embedding, n int) bool {
+	seen := make(map[int]bool, len(embeddings))
+	for _, emb := range embeddings {
+		if emb.Index < 0 || emb.Index >= n || seen[emb.Index] {
+			return false
+		}
+		seen[emb.Index] = true
+	}
+	return true
+}
+
+// EstimateTokens approximates the token count of English text at four bytes
+// per token. Use a tokenizer for the embedding model when limits are tight.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,139 @@
+package gollmx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// batchLLM embeds each input as [its length], recording batches and failing
+// those that contain a "fail" input
+type batchLLM struct {
+	mockLLM
+	mu      sync.Mutex
+	batches [][]string
+	reverse bool // Return embeddings in reverse order with their indices
+}
+
+func (m *batchLLM) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	m.mu.Lock()
+	m.batches = append(m.batches, req.Input)
+	m.mu.Unlock()
+
+	resp := &EmbedResponse{Model: "embed", Usage: Usage{PromptTokens: len(req.Input), TotalTokens: len(req.Input)}}
+	for i, input := range req.Input {
+		if input == "fail" {
+			return nil, NewAPIError(ErrorTypeServer, m.id, "batch failed")
+		}
+		resp.Embeddings = append(resp.Embeddings, Embedding{Index: i, Vector: []float64{float64(len(input))}})
+	}
+	if m.reverse {
+		for i, j := 0, len(resp.Embeddings)-1; i < j; i, j = i+1, j-1 {
+			resp.Embeddings[i], resp.Embeddings[j] = resp.Embeddings[j], resp.Embeddings[i]
+		}
+	}
+	return resp, nil
+}
+
+// inputsOfLength returns inputs whose lengths are 1..n
+func inputsOfLength(n int) []string {
+	inputs := make([]string, n)
+	for i := range inputs {
+		inputs[i] = strings.Repeat("x", i+1)
+	}
+	return inputs
+}
+
+func TestEmbedBatcherItemLimit(t *testing.T) {
+	inner := &batchLLM{mockLLM: mockLLM{id: "cohere"}, reverse: true}
+	batcher := NewEmbedBatcher(inner)
+
+	resp, err := batcher.Embed(context.Background(), &EmbedRequest{Input: inputsOfLength(200)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inner.batches) != 3 {
+		t.Fatalf("expected 3 batches of at most 96, got %d", len(inner.batches))
+	}
+	for _, batch := range inner.batches {
+		if len(batch) > 96 {
+			t.Errorf("expected at most 96 inputs per batch, got %d", len(batch))
+		}
+	}
+
+	if len(resp.Embeddings) != 200 {
+		t.Fatalf("expected 200 embeddings, got %d", len(resp.Embeddings))
+	}
+	for i, emb := range resp.Embeddings {
+		if emb.Index != i || emb.Vector[0] != float64(i+1) {
+			t.Fatalf("expected embedding %d for input %d, got index %d vector %v", i, i, emb.Index, emb.Vector)
+		}
+	}
+	if resp.Usage.PromptTokens != 200 {
+		t.Errorf("expected usage to be summed, got %d", resp.Usage.PromptTokens)
+	}
+}
+
+func TestEmbedBatcherTokenLimit(t *testing.T) {
+	inner := &batchLLM{mockLLM: mockLLM{id: "test"}}
+	batcher := NewEmbedBatcher(inner,
+		WithEmbedLimits(EmbedLimits{MaxTokens: 10}),
+		WithEmbedTokenCounter(func(s string) int { return len(s) }),
+	)
+
+	// 3+4+3 fits; 20 exceeds the limit alone
+	inputs := []string{"aaa", "bbbb", "ccc", strings.Repeat("d", 20), "e"}
+	if _, err := batcher.Embed(context.Background(), &EmbedRequest{Input: inputs}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Batches run concurrently, so order them by their first input
+	sizes := make(map[string]int)
+	for _, batch := range inner.batches {
+		sizes[batch[0]] = len(batch)
+	}
+	if len(sizes) != 3 || sizes["aaa"] != 3 || sizes[inputs[3]] != 1 || sizes["e"] != 1 {
+		t.Errorf("expected batches of 3, 1 and 1 inputs, got %v", sizes)
+	}
+}
+
+func TestEmbedBatcherPartialFailure(t *testing.T) {
+	inner := &batchLLM{mockLLM: mockLLM{id: "test"}}
+	batcher := NewEmbedBatcher(inner, WithEmbedLimits(EmbedLimits{MaxItems: 2}))
+
+	inputs := []string{"a", "b", "fail", "d", "e"}
+	resp, err := batcher.Embed(context.Background(), &EmbedRequest{Input: inputs})
+
+	var batchErr *EmbedBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *EmbedBatchError, got %v", err)
+	}
+	if len(batchErr.Failures) != 1 || batchErr.Failures[0].Start != 2 || batchErr.Failures[0].End != 4 {
+		t.Errorf("expected inputs 2-3 to fail, got %+v", batchErr.Failures)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeServer {
+		t.Errorf("expected the batch's server error to be reachable, got %v", err)
+	}
+
+	if resp == nil || len(resp.Embeddings) != 3 {
+		t.Fatalf("expected 3 successful embeddings, got %+v", resp)
+	}
+	if resp.Embeddings[2].Index != 4 {
+		t.Errorf("expected the last embedding at index 4, got %d", resp.Embeddings[2].Index)
+	}
+}
+
+func TestEmbedBatcherSingleBatch(t *testing.T) {
+	inner := &batchLLM{mockLLM: mockLLM{id: "unknown"}}
+	batcher := NewEmbedBatcher(inner)
+
+	if _, err := batcher.Embed(context.Background(), &EmbedRequest{Input: inputsOfLength(500)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inner.batches) != 1 {
+		t.Errorf("expected providers without limits to get one request, got %d", len(inner.batches))
+	}
+}
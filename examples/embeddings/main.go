@@ -4,48 +4,14 @@ package main
 import (
 	"context"
 	"fmt"
-	"math"
 	"os"
-	"sort"
 	"time"
 
 	gollmx "github.com/onlyhyde/gollm-x"
 	_ "github.com/onlyhyde/gollm-x/providers" // Import all providers
+	"github.com/onlyhyde/gollm-x/vector"
 )
 
-// Document represents a text document with its embedding
-type Document struct {
-	ID        int
-	Text      string
-	Embedding []float64
-}
-
-// cosineSimilarity calculates the cosine similarity between two vectors
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return 0
-	}
-
-	var dotProduct, normA, normB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
-	}
-
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-
-	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
-}
-
-// SearchResult represents a search result with similarity score
-type SearchResult struct {
-	Document   Document
-	Similarity float64
-}
-
 func main() {
 	// Get API key from environment
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -92,12 +58,12 @@ func runOpenAIExample(apiKey string) {
 
 	// Generate embeddings for all documents
 	fmt.Println("Generating embeddings for documents...")
-	docs, err := embedDocuments(ctx, client, documents, "text-embedding-3-small")
+	index, err := indexDocuments(ctx, client, documents, "text-embedding-3-small")
 	if err != nil {
 		fmt.Printf("Failed to generate embeddings: %v\n", err)
 		return
 	}
-	fmt.Printf("Generated embeddings for %d documents\n\n", len(docs))
+	fmt.Printf("Indexed %d documents\n\n", index.Len())
 
 	// Search queries
 	queries := []string{
@@ -110,18 +76,37 @@ func runOpenAIExample(apiKey string) {
 		fmt.Printf("Query: \"%s\"\n", query)
 		fmt.Println("---")
 
-		results, err := semanticSearch(ctx, client, query, docs, "text-embedding-3-small", 3)
+		results, err := semanticSearch(ctx, client, index, query, "text-embedding-3-small", 3)
 		if err != nil {
 			fmt.Printf("Search failed: %v\n", err)
 			continue
 		}
 
 		for i, result := range results {
-			fmt.Printf("%d. [%.4f] %s\n", i+1, result.Similarity, result.Document.Text)
+			fmt.Printf("%d. [%.4f] %s\n", i+1, result.Score, result.Metadata["text"])
 		}
 		fmt.Println()
 	}
 
+	// Rerank the candidates for a query. Providers without a native rerank
+	// endpoint fall back to embedding similarity.
+	fmt.Println("=== Rerank Example ===")
+	reranker := gollmx.AsReranker(client)
+	rerankResp, err := reranker.Rerank(ctx, &gollmx.RerankRequest{
+		Model:     "text-embedding-3-small",
+		Query:     "Which city is the capital of France?",
+		Documents: documents,
+		TopN:      3,
+	})
+	if err != nil {
+		fmt.Printf("Rerank failed: %v\n", err)
+	} else {
+		for i, result := range rerankResp.Results {
+			fmt.Printf("%d. [%.4f] %s\n", i+1, result.Score, result.Document)
+		}
+	}
+	fmt.Println()
+
 	// Demonstrate batch embeddings
 	fmt.Println("=== Batch Embedding Example ===")
 	batchTexts := []string{
@@ -208,65 +193,50 @@ func runOllamaExample() {
 	fmt.Println("Text similarities:")
 	for i := 0; i < len(texts); i++ {
 		for j := i + 1; j < len(texts); j++ {
-			sim := cosineSimilarity(embeddings[i], embeddings[j])
+			sim := vector.Cosine(embeddings[i], embeddings[j])
 			fmt.Printf("  \"%s\" <-> \"%s\": %.4f\n", texts[i], texts[j], sim)
 		}
 	}
 }
 
-// embedDocuments generates embeddings for a list of documents
-func embedDocuments(ctx context.Context, client gollmx.LLM, texts []string, model string) ([]Document, error) {
+// indexDocuments embeds texts into a flat index, storing each text as
+// metadata
+func indexDocuments(ctx context.Context, client gollmx.LLM, texts []string, model string) (*vector.Flat, error) {
 	resp, err := client.Embed(ctx, &gollmx.EmbedRequest{
-		Model: model,
-		Input: texts,
+		Model:     model,
+		Input:     texts,
+		InputType: gollmx.EmbedInputSearchDocument,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	docs := make([]Document, len(texts))
+	metadata := make([]map[string]interface{}, len(texts))
 	for i, text := range texts {
-		docs[i] = Document{
-			ID:        i,
-			Text:      text,
-			Embedding: resp.Embeddings[i].Vector,
-		}
+		metadata[i] = map[string]interface{}{"text": text}
 	}
-
-	return docs, nil
-}
-
-// semanticSearch finds the most similar documents to a query
-func semanticSearch(ctx context.Context, client gollmx.LLM, query string, docs []Document, model string, topK int) ([]SearchResult, error) {
-	// Get embedding for query
-	resp, err := client.Embed(ctx, &gollmx.EmbedRequest{
-		Model: model,
-		Input: []string{query},
-	})
+	items, err := vector.FromEmbeddings(resp.Embeddings, nil, metadata)
 	if err != nil {
 		return nil, err
 	}
 
-	queryEmbedding := resp.Embeddings[0].Vector
-
-	// Calculate similarity with all documents
-	results := make([]SearchResult, len(docs))
-	for i, doc := range docs {
-		results[i] = SearchResult{
-			Document:   doc,
-			Similarity: cosineSimilarity(queryEmbedding, doc.Embedding),
-		}
+	index := vector.NewFlat(vector.MetricCosine)
+	if err := index.Add(items...); err != nil {
+		return nil, err
 	}
+	return index, nil
+}
 
-	// Sort by similarity (descending)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Similarity > results[j].Similarity
+// semanticSearch finds the documents most similar to a query
+func semanticSearch(ctx context.Context, client gollmx.LLM, index vector.Index, query, model string, topK int) ([]vector.Result, error) {
+	resp, err := client.Embed(ctx, &gollmx.EmbedRequest{
+		Model:     model,
+		Input:     []string{query},
+		InputType: gollmx.EmbedInputSearchQuery,
 	})
-
-	// Return top K results
-	if topK > len(results) {
-		topK = len(results)
+	if err != nil {
+		return nil, err
 	}
 
-	return results[:topK], nil
+	return index.Search(vector.ToFloat32(resp.Embeddings[0].Vector), topK, nil)
 }
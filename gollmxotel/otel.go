@@ -0,0 +1,466 @@
+// Package gollmxotel provides OpenTelemetry tracing for gollm-x clients.
+//
+// Wrap a client with WithTracing to create a span for every Chat,
+// ChatStream, Complete and Embed call. Spans follow the OpenTelemetry GenAI
+// semantic conventions:
+//
+//	client = gollmxotel.WithTracing(client)
+//
+// To propagate the trace context to the provider's API, build the client
+// with an HTTP client from HTTPClient:
+//
+//	client, err := gollmx.New("openai", gollmx.WithHTTPClient(gollmxotel.HTTPClient(nil)))
+//
+// gollmxotel is a separate Go module, github.com/onlyhyde/gollm-x/gollmxotel,
+// so only programs that import it depend on OpenTelemetry.
+package gollmxotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer
+const ScopeName = "github.com/onlyhyde/gollm-x/gollmxotel"
+
+// GenAI semantic convention attribute keys
+const (
+	AttrOperationName    = attribute.Key("gen_ai.operation.name")
+	AttrSystem           = attribute.Key("gen_ai.system")
+	AttrRequestModel     = attribute.Key("gen_ai.request.model")
+	AttrRequestMaxTokens = attribute.Key("gen_ai.request.max_tokens")
+	AttrRequestTemp      = attribute.Key("gen_ai.request.temperature")
+	AttrRequestTopP      = attribute.Key("gen_ai.request.top_p")
+	AttrRequestStop      = attribute.Key("gen_ai.request.stop_sequences")
+	AttrResponseID       = attribute.Key("gen_ai.response.id")
+	AttrResponseModel    = attribute.Key("gen_ai.response.model")
+	AttrFinishReasons    = attribute.Key("gen_ai.response.finish_reasons")
+	AttrInputTokens      = attribute.Key("gen_ai.usage.input_tokens")
+	AttrOutputTokens     = attribute.Key("gen_ai.usage.output_tokens")
+	AttrEmbedDimensions  = attribute.Key("gen_ai.embeddings.dimension.count")
+	AttrErrorType        = attribute.Key("error.type")
+)
+
+// Attribute keys without a GenAI semantic convention equivalent
+const (
+	AttrTimeToFirstToken  = attribute.Key("gollmx.response.time_to_first_token")
+	AttrStreamChunksCount = attribute.Key("gollmx.response.chunks.count")
+	AttrEmbeddingsCount   = attribute.Key("gollmx.request.embeddings.count")
+)
+
+// systems maps provider IDs to well-known gen_ai.system values. Providers
+// without one use their ID.
+var systems = map[string]string{
+	"google":  "gcp.gemini",
+	"mistral": "mistral_ai",
+}
+
+// System returns the gen_ai.system value for a provider ID
+func System(providerID string) string {
+	if system, ok := systems[providerID]; ok {
+		return system
+	}
+	return providerID
+}
+
+// Operation names
+const (
+	OperationChat       = "chat"
+	OperationCompletion = "text_completion"
+	OperationEmbeddings = "embeddings"
+)
+
+// EventFirstToken is the span event recorded when a stream's first content
+// arrives
+const EventFirstToken = "gollmx.first_token"
+
+// =============================================================================
+// Options
+// =============================================================================
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures tracing
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider (default: the global provider)
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPropagators sets the propagators used by Transport (default: the
+// global propagators)
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.propagators == nil {
+		c.propagators = otel.GetTextMapPropagator()
+	}
+	return c
+}
+
+// =============================================================================
+// Tracing Client
+// =============================================================================
+
+// TracingClient wraps an LLM client with OpenTelemetry spans
+type TracingClient struct {
+	client gollmx.LLM
+	tracer trace.Tracer
+}
+
+// WithTracing wraps an LLM client with OpenTelemetry tracing
+func WithTracing(client gollmx.LLM, opts ...Option) *TracingClient {
+	c := newConfig(opts)
+	return &TracingClient{
+		client: client,
+		tracer: c.tracerProvider.Tracer(ScopeName),
+	}
+}
+
+// ID returns the provider identifier
+func (c *TracingClient) ID() string {
+	return c.client.ID()
+}
+
+// Name returns the provider name
+func (c *TracingClient) Name() string {
+	return c.client.Name()
+}
+
+// Version returns the client version
+func (c *TracingClient) Version() string {
+	return c.client.Version()
+}
+
+// BaseURL returns the API base URL
+func (c *TracingClient) BaseURL() string {
+	return c.client.BaseURL()
+}
+
+// Models returns available models
+func (c *TracingClient) Models() []gollmx.Model {
+	return c.client.Models()
+}
+
+// GetModel returns a specific model
+func (c *TracingClient) GetModel(id string) (*gollmx.Model, error) {
+	return c.client.GetModel(id)
+}
+
+// Chat performs a traced chat completion
+func (c *TracingClient) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.ChatResponse, error) {
+	ctx, span := c.start(ctx, OperationChat, req.Model, chatAttributes(req)...)
+	defer span.End()
+
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(responseAttributes(resp.ID, resp.Model, resp.Usage)...)
+	span.SetAttributes(AttrFinishReasons.StringSlice(finishReasons(resp.Choices)))
+	return resp, nil
+}
+
+// ChatStream performs a traced streaming chat completion. The span ends
+// when the stream is exhausted or fails.
+func (c *TracingClient) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.StreamReader, error) {
+	ctx, span := c.start(ctx, OperationChat, req.Model, chatAttributes(req)...)
+
+	start := time.Now()
+	stream, err := c.client.ChatStream(ctx, req)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+		return nil, err
+	}
+
+	ch := make(chan gollmx.StreamChunk)
+	go traceStream(ctx, span, start, stream, ch)
+	return gollmx.NewStreamReader(ch), nil
+}
+
+// Complete performs a traced text completion
+func (c *TracingClient) Complete(ctx context.Context, req *gollmx.CompletionRequest) (*gollmx.CompletionResponse, error) {
+	ctx, span := c.start(ctx, OperationCompletion, req.Model, requestAttributes(req.MaxTokens, req.Temperature, req.TopP, req.Stop)...)
+	defer span.End()
+
+	resp, err := c.client.Complete(ctx, req)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	reasons := make([]string, len(resp.Choices))
+	for i, choice := range resp.Choices {
+		reasons[i] = choice.FinishReason
+	}
+	span.SetAttributes(responseAttributes(resp.ID, resp.Model, resp.Usage)...)
+	span.SetAttributes(AttrFinishReasons.StringSlice(reasons))
+	return resp, nil
+}
+
+// Embed generates traced embeddings
+func (c *TracingClient) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.EmbedResponse, error) {
+	ctx, span := c.start(ctx, OperationEmbeddings, req.Model, AttrEmbeddingsCount.Int(len(req.Input)))
+	defer span.End()
+
+	resp, err := c.client.Embed(ctx, req)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(responseAttributes("", resp.Model, resp.Usage)...)
+	if len(resp.Embeddings) > 0 {
+		span.SetAttributes(AttrEmbedDimensions.Int(len(resp.Embeddings[0].Vector)))
+	}
+	return resp, nil
+}
+
+// HasFeature checks if a feature is supported
+func (c *TracingClient) HasFeature(feature gollmx.Feature) bool {
+	return c.client.HasFeature(feature)
+}
+
+// Features returns all supported features
+func (c *TracingClient) Features() []gollmx.Feature {
+	return c.client.Features()
+}
+
+// SetOption sets a provider-specific option
+func (c *TracingClient) SetOption(key string, value interface{}) error {
+	return c.client.SetOption(key, value)
+}
+
+// GetOption gets a provider-specific option
+func (c *TracingClient) GetOption(key string) (interface{}, bool) {
+	return c.client.GetOption(key)
+}
+
+// Unwrap returns the underlying LLM client
+func (c *TracingClient) Unwrap() gollmx.LLM {
+	return c.client
+}
+
+// start starts a client span named "{operation} {model}"
+func (c *TracingClient) start(ctx context.Context, operation, model string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	name := operation
+	if model != "" {
+		name += " " + model
+	}
+	attrs = append(attrs,
+		AttrOperationName.String(operation),
+		AttrSystem.String(System(c.client.ID())),
+	)
+	if model != "" {
+		attrs = append(attrs, AttrRequestModel.String(model))
+	}
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// traceStream forwards chunks from stream to ch, recording the first token,
+// usage and finish reason on span before ending it
+func traceStream(ctx context.Context, span trace.Span, start time.Time, stream *gollmx.StreamReader, ch chan<- gollmx.StreamChunk) {
+	defer close(ch)
+	defer span.End()
+
+	var (
+		id, model    string
+		usage        gollmx.Usage
+		finishReason string
+		chunks       int
+		firstToken   bool
+	)
+
+	for {
+		chunk, ok := stream.Next()
+		if !ok {
+			break
+		}
+		chunks++
+
+		if !firstToken && (chunk.Content != "" || len(chunk.ToolCalls) > 0) {
+			firstToken = true
+			ttft := time.Since(start)
+			span.AddEvent(EventFirstToken, trace.WithAttributes(AttrTimeToFirstToken.Float64(ttft.Seconds())))
+			span.SetAttributes(AttrTimeToFirstToken.Float64(ttft.Seconds()))
+		}
+		if chunk.ID != "" {
+			id = chunk.ID
+		}
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
+		if chunk.Usage != (gollmx.Usage{}) {
+			usage = chunk.Usage
+		}
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+
+		select {
+		case ch <- *chunk:
+		case <-ctx.Done():
+			recordError(span, ctx.Err())
+			stream.Drain()
+			return
+		}
+	}
+
+	span.SetAttributes(responseAttributes(id, model, usage)...)
+	span.SetAttributes(AttrStreamChunksCount.Int(chunks))
+	if finishReason != "" {
+		span.SetAttributes(AttrFinishReasons.StringSlice([]string{finishReason}))
+	}
+
+	if err := stream.Err(); err != nil {
+		recordError(span, err)
+		select {
+		case ch <- gollmx.StreamChunk{Error: err}:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// =============================================================================
+// Attributes
+// =============================================================================
+
+func chatAttributes(req *gollmx.ChatRequest) []attribute.KeyValue {
+	return requestAttributes(req.MaxTokens, req.Temperature, req.TopP, req.Stop)
+}
+
+func requestAttributes(maxTokens int, temperature, topP *float64, stop []string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if maxTokens > 0 {
+		attrs = append(attrs, AttrRequestMaxTokens.Int(maxTokens))
+	}
+	if temperature != nil {
+		attrs = append(attrs, AttrRequestTemp.Float64(*temperature))
+	}
+	if topP != nil {
+		attrs = append(attrs, AttrRequestTopP.Float64(*topP))
+	}
+	if len(stop) > 0 {
+		attrs = append(attrs, AttrRequestStop.StringSlice(stop))
+	}
+	return attrs
+}
+
+func responseAttributes(id, model string, usage gollmx.Usage) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if id != "" {
+		attrs = append(attrs, AttrResponseID.String(id))
+	}
+	if model != "" {
+		attrs = append(attrs, AttrResponseModel.String(model))
+	}
+	if usage.PromptTokens > 0 {
+		attrs = append(attrs, AttrInputTokens.Int(usage.PromptTokens))
+	}
+	if usage.CompletionTokens > 0 {
+		attrs = append(attrs, AttrOutputTokens.Int(usage.CompletionTokens))
+	}
+	return attrs
+}
+
+func finishReasons(choices []gollmx.Choice) []string {
+	reasons := make([]string, len(choices))
+	for i, choice := range choices {
+		reasons[i] = choice.FinishReason
+	}
+	return reasons
+}
+
+// recordError marks span as failed, using the API error type as error.type
+func recordError(span trace.Span, err error) {
+	errType := fmt.Sprintf("%T", err)
+	var apiErr *gollmx.APIError
+	if errors.As(err, &apiErr) {
+		errType = string(apiErr.Type)
+	} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		errType = err.Error()
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(AttrErrorType.String(errType))
+}
+
+// =============================================================================
+// HTTP Propagation
+// =============================================================================
+
+// Transport is an http.RoundTripper that injects the trace context of each
+// request's context into its headers
+type Transport struct {
+	// Base is the underlying transport; http.DefaultTransport if nil
+	Base http.RoundTripper
+
+	propagators propagation.TextMapPropagator
+}
+
+// NewTransport creates a Transport wrapping base
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	c := newConfig(opts)
+	return &Transport{Base: base, propagators: c.propagators}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	t.propagators.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	return base.RoundTrip(req)
+}
+
+// HTTPClient returns a copy of client whose requests carry the trace
+// context. A nil client is replaced by gollmx's default client. Pass the
+// result to gollmx.WithHTTPClient.
+func HTTPClient(client *http.Client, opts ...Option) *http.Client {
+	if client == nil {
+		client = gollmx.DefaultConfig().GetHTTPClient()
+	}
+	traced := *client
+	traced.Transport = NewTransport(traced.Transport, opts...)
+	return &traced
+}
+
+// Ensure TracingClient implements LLM interface
+var _ gollmx.LLM = (*TracingClient)(nil)
@@ -0,0 +1,235 @@
+package gollmxotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracedMock(t *testing.T) (*gollmxtest.MockLLM, *TracingClient, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	mock := gollmxtest.NewMockLLM("mock")
+	return mock, WithTracing(mock, WithTracerProvider(tp)), recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func onlySpan(t *testing.T, recorder *tracetest.SpanRecorder) sdktrace.ReadOnlySpan {
+	t.Helper()
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 ended span, got %d", len(spans))
+	}
+	return spans[0]
+}
+
+func TestChatSpan(t *testing.T) {
+	mock, client, recorder := newTracedMock(t)
+
+	resp := gollmxtest.TextResponse("Hello!")
+	resp.Usage = gollmx.Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}
+	mock.QueueChat(resp)
+
+	temperature := 0.2
+	_, err := client.Chat(context.Background(), &gollmx.ChatRequest{
+		Model:       "gpt-4o",
+		Messages:    []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+		MaxTokens:   100,
+		Temperature: &temperature,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	span := onlySpan(t, recorder)
+	if span.Name() != "chat gpt-4o" {
+		t.Errorf("expected span name 'chat gpt-4o', got '%s'", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected client span, got %v", span.SpanKind())
+	}
+
+	attrs := attributes(span)
+	checks := map[attribute.Key]interface{}{
+		AttrOperationName:    "chat",
+		AttrSystem:           "mock",
+		AttrRequestModel:     "gpt-4o",
+		AttrRequestMaxTokens: int64(100),
+		AttrRequestTemp:      0.2,
+		AttrResponseID:       "mock-response",
+		AttrResponseModel:    "gpt-4o",
+		AttrInputTokens:      int64(12),
+		AttrOutputTokens:     int64(3),
+	}
+	for key, want := range checks {
+		if got := attrs[key].AsInterface(); got != want {
+			t.Errorf("expected %s=%v, got %v", key, want, got)
+		}
+	}
+	if reasons := attrs[AttrFinishReasons].AsStringSlice(); len(reasons) != 1 || reasons[0] != "stop" {
+		t.Errorf("expected finish reasons [stop], got %v", reasons)
+	}
+}
+
+func TestSystem(t *testing.T) {
+	tests := map[string]string{
+		"google":    "gcp.gemini",
+		"mistral":   "mistral_ai",
+		"openai":    "openai",
+		"anthropic": "anthropic",
+		"ollama":    "ollama",
+	}
+	for id, want := range tests {
+		if got := System(id); got != want {
+			t.Errorf("System(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestChatSpanError(t *testing.T) {
+	mock, client, recorder := newTracedMock(t)
+	mock.QueueError(gollmx.NewAPIError(gollmx.ErrorTypeRateLimit, "mock", "slow down"))
+
+	_, err := client.Chat(context.Background(), &gollmx.ChatRequest{Model: "gpt-4o"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	span := onlySpan(t, recorder)
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", span.Status().Code)
+	}
+	if got := attributes(span)[AttrErrorType].AsString(); got != string(gollmx.ErrorTypeRateLimit) {
+		t.Errorf("expected error.type 'rate_limit', got '%s'", got)
+	}
+}
+
+func TestChatStreamSpan(t *testing.T) {
+	mock, client, recorder := newTracedMock(t)
+	mock.QueueStream(
+		gollmx.StreamChunk{ID: "s1", Content: "Hel"},
+		gollmx.StreamChunk{ID: "s1", Content: "lo"},
+		gollmx.StreamChunk{ID: "s1", FinishReason: "stop", Usage: gollmx.Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7}},
+	)
+
+	stream, err := client.ChatStream(context.Background(), &gollmx.ChatRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.Ended()) != 0 {
+		t.Error("expected the span to stay open until the stream ends")
+	}
+
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "Hello" {
+		t.Errorf("expected 'Hello', got '%s'", resp.GetContent())
+	}
+
+	span := onlySpan(t, recorder)
+	events := span.Events()
+	if len(events) != 1 || events[0].Name != EventFirstToken {
+		t.Fatalf("expected a single first token event, got %v", events)
+	}
+
+	attrs := attributes(span)
+	if attrs[AttrInputTokens].AsInt64() != 5 || attrs[AttrOutputTokens].AsInt64() != 2 {
+		t.Errorf("expected usage 5/2, got %v/%v", attrs[AttrInputTokens].AsInt64(), attrs[AttrOutputTokens].AsInt64())
+	}
+	if _, ok := attrs[AttrTimeToFirstToken]; !ok {
+		t.Error("expected time to first token attribute")
+	}
+	if reasons := attrs[AttrFinishReasons].AsStringSlice(); len(reasons) != 1 || reasons[0] != "stop" {
+		t.Errorf("expected finish reasons [stop], got %v", reasons)
+	}
+}
+
+func TestChatStreamCancel(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	gollmxtest.CheckStreamCancel(t, func(client gollmx.LLM) gollmx.LLM {
+		return WithTracing(client, WithTracerProvider(tp))
+	}, &gollmx.ChatRequest{Model: "gpt-4o"})
+
+	span := onlySpan(t, recorder)
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", span.Status().Code)
+	}
+}
+
+func TestEmbedSpan(t *testing.T) {
+	mock, client, recorder := newTracedMock(t)
+	mock.QueueEmbed(gollmxtest.EmbedResponse([]float64{0.1, 0.2, 0.3}, []float64{0.4, 0.5, 0.6}))
+
+	_, err := client.Embed(context.Background(), &gollmx.EmbedRequest{Model: "text-embedding-3-small", Input: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	span := onlySpan(t, recorder)
+	if span.Name() != "embeddings text-embedding-3-small" {
+		t.Errorf("unexpected span name '%s'", span.Name())
+	}
+	attrs := attributes(span)
+	if attrs[AttrEmbeddingsCount].AsInt64() != 2 {
+		t.Errorf("expected 2 inputs, got %d", attrs[AttrEmbeddingsCount].AsInt64())
+	}
+	if attrs[AttrEmbedDimensions].AsInt64() != 3 {
+		t.Errorf("expected 3 dimensions, got %d", attrs[AttrEmbedDimensions].AsInt64())
+	}
+}
+
+func TestTransportPropagation(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	ctx, span := tp.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	client := HTTPClient(nil, WithPropagators(propagation.TraceContext{}))
+	req, _ := http.NewRequestWithContext(ctx, "POST", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if traceparent == "" {
+		t.Fatal("expected traceparent header")
+	}
+	if want := span.SpanContext().TraceID().String(); len(traceparent) < 35 || traceparent[3:35] != want {
+		t.Errorf("expected trace ID %s in '%s'", want, traceparent)
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+}
@@ -0,0 +1,168 @@
+// Package gollmxprom provides a Prometheus gollmx.MetricsRecorder.
+//
+//	recorder, err := gollmxprom.NewRecorder(prometheus.DefaultRegisterer)
+//	client = gollmx.WithMetrics(client, recorder)
+//
+// All metrics are labelled with provider, model, operation and stream.
+//
+// Import it only when exporting to Prometheus: it is its own Go module,
+// github.com/onlyhyde/gollm-x/gollmxprom, and the only part of gollm-x that
+// requires prometheus/client_golang. The expvar recorder in gollmx needs no
+// dependencies.
+package gollmxprom
+
+import (
+	"context"
+	"strconv"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace prefixes every metric name
+const DefaultNamespace = "gollmx"
+
+var (
+	// DefaultLatencyBuckets are the buckets for request latency and time to
+	// first token, in seconds
+	DefaultLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30, 60, 120}
+
+	// DefaultThroughputBuckets are the buckets for output tokens per second
+	DefaultThroughputBuckets = []float64{5, 10, 20, 40, 60, 80, 100, 150, 200, 400}
+)
+
+var labels = []string{"provider", "model", "operation", "stream"}
+
+type config struct {
+	namespace         string
+	latencyBuckets    []float64
+	throughputBuckets []float64
+}
+
+// Option configures a Recorder
+type Option func(*config)
+
+// WithNamespace sets the metric name prefix
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithLatencyBuckets sets the latency and time to first token buckets
+func WithLatencyBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.latencyBuckets = buckets
+	}
+}
+
+// WithThroughputBuckets sets the tokens per second buckets
+func WithThroughputBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.throughputBuckets = buckets
+	}
+}
+
+// Recorder is a gollmx.MetricsRecorder backed by Prometheus collectors
+type Recorder struct {
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	tokens          *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	ttft            *prometheus.HistogramVec
+	tokensPerSecond *prometheus.HistogramVec
+	modelLoad       *prometheus.HistogramVec
+}
+
+// NewRecorder creates a Recorder and registers its collectors with reg
+func NewRecorder(reg prometheus.Registerer, opts ...Option) (*Recorder, error) {
+	c := &config{
+		namespace:         DefaultNamespace,
+		latencyBuckets:    DefaultLatencyBuckets,
+		throughputBuckets: DefaultThroughputBuckets,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	r := &Recorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "requests_total",
+			Help:      "LLM requests, including failed ones.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "errors_total",
+			Help:      "Failed LLM requests by error type.",
+		}, append(labels, "error_type")),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "tokens_total",
+			Help:      "Tokens used by LLM requests, by direction (input or output).",
+		}, append(labels, "direction")),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Time until an LLM response or stream completed.",
+			Buckets:   c.latencyBuckets,
+		}, labels),
+		ttft: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "time_to_first_token_seconds",
+			Help:      "Time until the first streamed token.",
+			Buckets:   c.latencyBuckets,
+		}, labels),
+		tokensPerSecond: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "output_tokens_per_second",
+			Help:      "Output tokens per second of generation.",
+			Buckets:   c.throughputBuckets,
+		}, labels),
+		modelLoad: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "model_load_duration_seconds",
+			Help:      "Time local runtimes spent loading the model.",
+			Buckets:   c.latencyBuckets,
+		}, labels),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		r.requests, r.errors, r.tokens, r.latency, r.ttft, r.tokensPerSecond, r.modelLoad,
+	} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RecordRequest implements gollmx.MetricsRecorder
+func (r *Recorder) RecordRequest(ctx context.Context, m gollmx.RequestMetrics) {
+	values := []string{m.Provider, m.Model, m.Operation, strconv.FormatBool(m.Stream)}
+
+	r.requests.WithLabelValues(values...).Inc()
+	r.latency.WithLabelValues(values...).Observe(m.Latency.Seconds())
+
+	if m.ErrorType != "" {
+		r.errors.WithLabelValues(append(values, string(m.ErrorType))...).Inc()
+	}
+	if m.Usage.PromptTokens > 0 {
+		r.tokens.WithLabelValues(append(values, "input")...).Add(float64(m.Usage.PromptTokens))
+	}
+	if m.Usage.CompletionTokens > 0 {
+		r.tokens.WithLabelValues(append(values, "output")...).Add(float64(m.Usage.CompletionTokens))
+	}
+	if m.TimeToFirstToken > 0 {
+		r.ttft.WithLabelValues(values...).Observe(m.TimeToFirstToken.Seconds())
+	}
+	if m.TokensPerSecond > 0 {
+		r.tokensPerSecond.WithLabelValues(values...).Observe(m.TokensPerSecond)
+	}
+	if m.Usage.LoadDuration > 0 {
+		r.modelLoad.WithLabelValues(values...).Observe(m.Usage.LoadDuration.Seconds())
+	}
+}
+
+// Ensure Recorder implements MetricsRecorder
+var _ gollmx.MetricsRecorder = (*Recorder)(nil)
@@ -0,0 +1,113 @@
+package gollmxprom
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	recorder, err := NewRecorder(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	recorder.RecordRequest(ctx, gollmx.RequestMetrics{
+		Provider:         "ollama",
+		Model:            "llama3.2",
+		Operation:        gollmx.OperationChat,
+		Stream:           true,
+		Latency:          2 * time.Second,
+		TimeToFirstToken: 300 * time.Millisecond,
+		TokensPerSecond:  42,
+		Usage:            gollmx.Usage{PromptTokens: 10, CompletionTokens: 70, LoadDuration: time.Second},
+	})
+	recorder.RecordRequest(ctx, gollmx.RequestMetrics{
+		Provider:  "ollama",
+		Model:     "llama3.2",
+		Operation: gollmx.OperationChat,
+		Stream:    true,
+		Latency:   time.Second,
+		ErrorType: gollmx.ErrorTypeServer,
+	})
+
+	expected := `
+# HELP gollmx_errors_total Failed LLM requests by error type.
+# TYPE gollmx_errors_total counter
+gollmx_errors_total{error_type="server",model="llama3.2",operation="chat",provider="ollama",stream="true"} 1
+# HELP gollmx_requests_total LLM requests, including failed ones.
+# TYPE gollmx_requests_total counter
+gollmx_requests_total{model="llama3.2",operation="chat",provider="ollama",stream="true"} 2
+# HELP gollmx_tokens_total Tokens used by LLM requests, by direction (input or output).
+# TYPE gollmx_tokens_total counter
+gollmx_tokens_total{direction="input",model="llama3.2",operation="chat",provider="ollama",stream="true"} 10
+gollmx_tokens_total{direction="output",model="llama3.2",operation="chat",provider="ollama",stream="true"} 70
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gollmx_errors_total", "gollmx_requests_total", "gollmx_tokens_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	counts := map[string]int{
+		"gollmx_request_duration_seconds":    2,
+		"gollmx_time_to_first_token_seconds": 1,
+		"gollmx_output_tokens_per_second":    1,
+		"gollmx_model_load_duration_seconds": 1,
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, family := range families {
+		want, ok := counts[family.GetName()]
+		if !ok {
+			continue
+		}
+		delete(counts, family.GetName())
+		if got := family.GetMetric()[0].GetHistogram().GetSampleCount(); got != uint64(want) {
+			t.Errorf("expected %d samples in %s, got %d", want, family.GetName(), got)
+		}
+	}
+	for name := range counts {
+		t.Errorf("expected histogram %s to be registered", name)
+	}
+}
+
+func TestRecorderWithMetricsClient(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	recorder, err := NewRecorder(reg, WithNamespace("app"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := gollmxtest.NewMockLLM("mock")
+	mock.QueueText("Hello")
+	client := gollmx.WithMetrics(mock, recorder)
+
+	if _, err := client.Chat(context.Background(), &gollmx.ChatRequest{Model: "small"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := testutil.CollectAndCount(reg, "app_requests_total"); n != 1 {
+		t.Errorf("expected 1 request series, got %d", n)
+	}
+}
+
+func TestNewRecorderDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := NewRecorder(reg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewRecorder(reg); err == nil {
+		t.Error("expected an error registering the same metrics twice")
+	}
+}
@@ -0,0 +1,427 @@
+package gollmxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// =============================================================================
+// Cassettes
+// =============================================================================
+
+// Redacted replaces secrets in recorded cassettes
+const Redacted = "REDACTED"
+
+// RecordEnv is the environment variable that switches UseCassette into
+// record mode when set to a non-empty value
+const RecordEnv = "GOLLMX_RECORD"
+
+// Mode controls whether a Recorder talks to the network
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real transport and records them
+	ModeRecord
+)
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a cassette
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored in a cassette. Streaming (SSE
+// and NDJSON) bodies are stored verbatim.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating parent directories
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// =============================================================================
+// Recorder
+// =============================================================================
+
+// Recorder is an http.RoundTripper that records traffic to a cassette or
+// replays it. Plug it into a provider with gollmx.WithHTTPClient:
+//
+//	rec, _ := gollmxtest.NewRecorder("testdata/chat.json", gollmxtest.ModeReplay)
+//	defer rec.Stop()
+//	client, _ := gollmx.New("openai", gollmx.WithHTTPClient(rec.Client()))
+//
+// Replayed requests are matched by method, URL path and normalized body.
+type Recorder struct {
+	mu       sync.Mutex
+	path     string
+	mode     Mode
+	cassette *Cassette
+	used     []bool
+
+	// Transport performs real requests in record mode (defaults to
+	// http.DefaultTransport)
+	Transport http.RoundTripper
+
+	// RedactHeaders lists request and response headers whose values are
+	// replaced with Redacted before saving (defaults to
+	// gollmx.DefaultRedactHeaders). Their values are also redacted wherever
+	// they appear in recorded bodies.
+	RedactHeaders []string
+
+	// RedactQueryParams lists URL query parameters whose values are
+	// replaced with Redacted before saving (defaults to
+	// gollmx.DefaultRedactQueryParams), like RedactHeaders
+	RedactQueryParams []string
+}
+
+// NewRecorder creates a recorder for the cassette at path. In ModeReplay the
+// cassette must already exist; in ModeRecord it is overwritten by Stop.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:              path,
+		mode:              mode,
+		cassette:          &Cassette{},
+		RedactHeaders:     gollmx.DefaultRedactHeaders,
+		RedactQueryParams: gollmx.DefaultRedactQueryParams,
+	}
+
+	if mode == ModeReplay {
+		c, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+
+	return r, nil
+}
+
+// UseCassette returns an HTTP client backed by the cassette at path. It
+// replays by default and records when the GOLLMX_RECORD environment variable
+// is set. The cassette is saved when the test finishes.
+func UseCassette(t testing.TB, path string) *http.Client {
+	t.Helper()
+
+	mode := ModeReplay
+	if os.Getenv(RecordEnv) != "" {
+		mode = ModeRecord
+	}
+
+	rec, err := NewRecorder(path, mode)
+	if err != nil {
+		t.Fatalf("failed to open cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to save cassette: %v", err)
+		}
+	})
+
+	return rec.Client()
+}
+
+// Mode returns the recorder mode
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client that uses the recorder as its transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette returns the interactions recorded or loaded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Stop saves the cassette when recording. It is a no-op in replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	return r.Cassette().Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	// Recorded bodies have their credentials redacted
+	normalized := normalizeBody([]byte(redactSecrets(string(body), r.secrets(req))))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Prefer the first unused match so repeated identical requests replay
+	// in recorded order, then fall back to the last match
+	match := -1
+	for i, it := range r.cassette.Interactions {
+		if !r.matches(req, normalized, it.Request) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("gollmxtest: no recorded interaction for %s %s in %s", req.Method, req.URL.Path, r.path)
+	}
+	r.used[match] = true
+
+	rec := r.cassette.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) matches(req *http.Request, normalized string, rec RecordedRequest) bool {
+	if req.Method != rec.Method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+	return normalizeBody([]byte(rec.Body)) == normalized
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := r.secrets(req)
+	recReq := RecordedRequest{
+		Method:  req.Method,
+		URL:     r.redactURL(req.URL),
+		Headers: r.redactHeaders(req.Header, secrets),
+		Body:    redactSecrets(string(body), secrets),
+	}
+	recHeaders := r.redactHeaders(resp.Header, secrets)
+
+	// Tee the body so streams reach the caller as they arrive; the
+	// interaction is stored once the caller finishes with the body
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		done: func(data []byte) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+				Request: recReq,
+				Response: RecordedResponse{
+					StatusCode: resp.StatusCode,
+					Headers:    recHeaders,
+					Body:       redactSecrets(string(data), secrets),
+				},
+			})
+		},
+	}
+
+	return resp, nil
+}
+
+// redactHeaders returns a copy of h with RedactHeaders and any secrets in
+// other header values replaced
+func (r *Recorder) redactHeaders(h http.Header, secrets []string) http.Header {
+	out := h.Clone()
+	for _, values := range out {
+		for i, v := range values {
+			values[i] = redactSecrets(v, secrets)
+		}
+	}
+	for _, name := range r.RedactHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, Redacted)
+		}
+	}
+	return out
+}
+
+// secrets returns the credentials sent in the RedactHeaders and
+// RedactQueryParams of req, without auth schemes such as "Bearer"
+func (r *Recorder) secrets(req *http.Request) []string {
+	var secrets []string
+	for _, name := range r.RedactHeaders {
+		for _, v := range req.Header.Values(name) {
+			if _, credentials, ok := strings.Cut(v, " "); ok {
+				v = credentials
+			}
+			if v != "" {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+	query := req.URL.Query()
+	for _, name := range r.RedactQueryParams {
+		for _, v := range query[name] {
+			if v != "" {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+	return secrets
+}
+
+// redactSecrets replaces every occurrence of secrets in s
+func redactSecrets(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
+
+func (r *Recorder) redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	changed := false
+	for _, name := range r.RedactQueryParams {
+		if query.Has(name) {
+			query.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// recordingBody captures everything read from a response body and reports
+// it once on EOF or Close
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func([]byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	// Drain so the cassette holds the full response even if the caller
+	// stopped reading early
+	io.Copy(&b.buf, b.ReadCloser)
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) finish() {
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+}
+
+// readRequestBody reads and restores the request body
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// normalizeBody re-encodes JSON bodies with sorted keys and no insignificant
+// whitespace so field order doesn't affect matching
+func normalizeBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(bytes.TrimSpace(body))
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+// Ensure Recorder implements http.RoundTripper interface
+var _ http.RoundTripper = (*Recorder)(nil)
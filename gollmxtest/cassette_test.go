@@ -0,0 +1,339 @@
+package gollmxtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	_ "github.com/onlyhyde/gollm-x/providers/anthropic"
+	_ "github.com/onlyhyde/gollm-x/providers/cohere"
+	_ "github.com/onlyhyde/gollm-x/providers/google"
+	_ "github.com/onlyhyde/gollm-x/providers/groq"
+	_ "github.com/onlyhyde/gollm-x/providers/mistral"
+	_ "github.com/onlyhyde/gollm-x/providers/ollama"
+	_ "github.com/onlyhyde/gollm-x/providers/openai"
+)
+
+const openAIChatBody = `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4o-mini",` +
+	`"choices":[{"index":0,"message":{"role":"assistant","content":"Recorded hello"},"finish_reason":"stop"}],` +
+	`"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`
+
+func openAIServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-secret" {
+			t.Errorf("expected real API key to reach the server")
+		}
+		if strings.Contains(readBody(r), `"stream":true`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, part := range []string{"Str", "eamed"} {
+				fmt.Fprintf(w, "data: {\"id\":\"chatcmpl-2\",\"model\":\"gpt-4o-mini\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", part)
+				w.(http.Flusher).Flush()
+			}
+			fmt.Fprint(w, "data: {\"id\":\"chatcmpl-2\",\"model\":\"gpt-4o-mini\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, openAIChatBody)
+	}))
+}
+
+func readBody(r *http.Request) string {
+	body, _ := readRequestBody(r)
+	return string(body)
+}
+
+func chatAndStream(t *testing.T, client gollmx.LLM) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	resp, err := client.Chat(ctx, chatRequest("hi"))
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	stream, err := client.ChatStream(ctx, chatRequest("stream please"))
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	streamed, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	return resp.GetContent(), streamed.GetContent()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := openAIServer(t)
+	path := filepath.Join(t.TempDir(), "openai.json")
+
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	client, err := gollmx.New("openai",
+		gollmx.WithAPIKey("sk-secret"),
+		gollmx.WithBaseURL(server.URL),
+		gollmx.WithHTTPClient(rec.Client()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	chat, streamed := chatAndStream(t, client)
+	if chat != "Recorded hello" || streamed != "Streamed" {
+		t.Fatalf("unexpected recorded responses: '%s', '%s'", chat, streamed)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("failed to save cassette: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "sk-secret") {
+		t.Error("expected API key to be redacted from cassette")
+	}
+	if !strings.Contains(string(data), "data: [DONE]") {
+		t.Error("expected SSE stream to be recorded verbatim")
+	}
+
+	// Replay with the server gone and a different key
+	replay, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+	client, _ = gollmx.New("openai",
+		gollmx.WithAPIKey("other-key"),
+		gollmx.WithBaseURL(server.URL),
+		gollmx.WithHTTPClient(replay.Client()),
+	)
+
+	chat, streamed = chatAndStream(t, client)
+	if chat != "Recorded hello" {
+		t.Errorf("expected replayed chat 'Recorded hello', got '%s'", chat)
+	}
+	if streamed != "Streamed" {
+		t.Errorf("expected replayed stream 'Streamed', got '%s'", streamed)
+	}
+}
+
+func TestRecordAndReplayProviders(t *testing.T) {
+	tests := []struct {
+		provider string
+		server   func() FakeServer
+		stream   string // Marks the recorded stream, e.g. its framing or endpoint
+	}{
+		{"anthropic", NewAnthropicServer, "event: message_stop"},
+		{"cohere", NewCohereServer, "event: message-end"},
+		{"google", NewGeminiServer, ":streamGenerateContent?alt=sse"},
+		{"mistral", NewOpenAIServer, "data: [DONE]"},
+		{"groq", NewOpenAIServer, "data: [DONE]"},
+		{"ollama", NewOllamaServer, `"done":true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			server := tt.server()
+			server.Queue(
+				Reply{Chat: TextResponse("Recorded hello")},
+				Reply{Stream: []gollmx.StreamChunk{{Content: "Str"}, {Content: "eamed", FinishReason: "stop"}}},
+			)
+			path := filepath.Join(t.TempDir(), tt.provider+".json")
+
+			rec, err := NewRecorder(path, ModeRecord)
+			if err != nil {
+				t.Fatalf("failed to create recorder: %v", err)
+			}
+			client, err := gollmx.New(tt.provider,
+				gollmx.WithAPIKey("sk-secret"),
+				gollmx.WithBaseURL(server.URL()),
+				gollmx.WithHTTPClient(rec.Client()),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			chat, streamed := chatAndStream(t, client)
+			if chat != "Recorded hello" || streamed != "Streamed" {
+				t.Fatalf("unexpected recorded responses: '%s', '%s'", chat, streamed)
+			}
+			if err := rec.Stop(); err != nil {
+				t.Fatalf("failed to save cassette: %v", err)
+			}
+			server.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read cassette: %v", err)
+			}
+			if strings.Contains(string(data), "sk-secret") {
+				t.Error("expected API key to be redacted from cassette")
+			}
+			if !strings.Contains(string(data), strings.ReplaceAll(tt.stream, `"`, `\"`)) {
+				t.Errorf("expected the stream to be recorded verbatim, missing %s", tt.stream)
+			}
+
+			// Replay with the server gone and a different key
+			replay, err := NewRecorder(path, ModeReplay)
+			if err != nil {
+				t.Fatalf("failed to load cassette: %v", err)
+			}
+			client, _ = gollmx.New(tt.provider,
+				gollmx.WithAPIKey("other-key"),
+				gollmx.WithBaseURL(server.URL()),
+				gollmx.WithHTTPClient(replay.Client()),
+			)
+
+			chat, streamed = chatAndStream(t, client)
+			if chat != "Recorded hello" {
+				t.Errorf("expected replayed chat 'Recorded hello', got '%s'", chat)
+			}
+			if streamed != "Streamed" {
+				t.Errorf("expected replayed stream 'Streamed', got '%s'", streamed)
+			}
+		})
+	}
+}
+
+func TestReplayNoMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := (&Cassette{}).Save(path); err != nil {
+		t.Fatalf("failed to save cassette: %v", err)
+	}
+
+	rec, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+	client, _ := gollmx.New("openai", gollmx.WithAPIKey("k"), gollmx.WithHTTPClient(rec.Client()))
+
+	if _, err := client.Chat(context.Background(), chatRequest("hi")); err == nil {
+		t.Error("expected error for unrecorded request")
+	}
+}
+
+func TestReplayMatchesNormalizedBody(t *testing.T) {
+	rec := &Recorder{
+		mode: ModeReplay,
+		cassette: &Cassette{Interactions: []Interaction{
+			{
+				Request:  RecordedRequest{Method: "POST", URL: "http://x/v1/chat", Body: `{"b":2, "a":1}`},
+				Response: RecordedResponse{StatusCode: 200, Body: "first"},
+			},
+			{
+				Request:  RecordedRequest{Method: "POST", URL: "http://x/v1/chat", Body: `{"a":1,"b":2}`},
+				Response: RecordedResponse{StatusCode: 200, Body: "second"},
+			},
+		}},
+		used: make([]bool, 2),
+	}
+
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("POST", "http://y/v1/chat?key=abc", strings.NewReader(`{"a":1,"b":2}`))
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bodies = append(bodies, readAll(resp))
+	}
+
+	expected := []string{"first", "second", "second"}
+	for i := range expected {
+		if bodies[i] != expected[i] {
+			t.Errorf("request %d: expected '%s', got '%s'", i, expected[i], bodies[i])
+		}
+	}
+}
+
+func TestRedactQueryKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"Gemini says hi"}]},"finishReason":"STOP"}]}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "google.json")
+	rec, _ := NewRecorder(path, ModeRecord)
+	client, _ := gollmx.New("google",
+		gollmx.WithAPIKey("AIza-secret"),
+		gollmx.WithBaseURL(server.URL),
+		gollmx.WithHTTPClient(rec.Client()),
+	)
+
+	req := &gollmx.ChatRequest{Model: "gemini-1.5-flash", Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "hi"}}}
+	if _, err := client.Chat(context.Background(), req); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	rec.Stop()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "AIza-secret") {
+		t.Error("expected key query parameter to be redacted")
+	}
+
+	replay, _ := NewRecorder(path, ModeReplay)
+	client, _ = gollmx.New("google",
+		gollmx.WithAPIKey("AIza-other"),
+		gollmx.WithHTTPClient(replay.Client()),
+	)
+	resp, err := client.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if resp.GetContent() != "Gemini says hi" {
+		t.Errorf("expected 'Gemini says hi', got '%s'", resp.GetContent())
+	}
+}
+
+func TestRedactBodiesAndResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Echo the key back, as some error responses do
+		w.Header().Set("X-Api-Key", "sk-secret")
+		w.Header().Set("X-Echo", "key sk-secret")
+		fmt.Fprint(w, `{"error":"invalid key sk-secret"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "redact.json")
+	rec, _ := NewRecorder(path, ModeRecord)
+	send := func(client *http.Client, key string) string {
+		req, _ := http.NewRequest("POST", server.URL+"/v1/chat", strings.NewReader(`{"api_key":"`+key+`"}`))
+		req.Header.Set("Authorization", "Bearer "+key)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		return readAll(resp)
+	}
+	send(rec.Client(), "sk-secret")
+	rec.Stop()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "sk-secret") {
+		t.Errorf("expected the key to be redacted everywhere, got %s", data)
+	}
+
+	// Requests with another key match the redacted body
+	replay, _ := NewRecorder(path, ModeReplay)
+	if body := send(replay.Client(), "sk-other"); !strings.Contains(body, "invalid key") {
+		t.Errorf("expected the recorded response, got '%s'", body)
+	}
+}
+
+func readAll(resp *http.Response) string {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
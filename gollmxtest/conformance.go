@@ -0,0 +1,514 @@
+package gollmxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// =============================================================================
+// Conformance Suite
+// =============================================================================
+
+// conformanceUsage is the usage every scripted conformance reply reports
+var conformanceUsage = gollmx.Usage{PromptTokens: 11, CompletionTokens: 7, TotalTokens: 18}
+
+// sameTokens compares token counts, ignoring provider-reported timings
+func sameTokens(a, b gollmx.Usage) bool {
+	return a.PromptTokens == b.PromptTokens &&
+		a.CompletionTokens == b.CompletionTokens &&
+		a.TotalTokens == b.TotalTokens
+}
+
+// RunConformance checks that a provider honors the gollmx.LLM contract.
+// factory builds the client under test and is called with the fake server's
+// base URL and a test API key; server must speak the provider's wire
+// protocol. Optional capabilities (completion, embeddings, tools) are only
+// exercised when the client reports the feature.
+//
+//	func TestConformance(t *testing.T) {
+//		server := gollmxtest.NewOpenAIServer()
+//		defer server.Close()
+//		gollmxtest.RunConformance(t, New, server)
+//	}
+func RunConformance(t *testing.T, factory gollmx.ProviderFactory, server FakeServer) {
+	t.Helper()
+
+	client, err := factory(
+		gollmx.WithBaseURL(server.URL()),
+		gollmx.WithAPIKey("test-key"),
+		gollmx.WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	c := &conformance{client: client, server: server}
+
+	t.Run("Metadata", c.testMetadata)
+	t.Run("Chat", c.testChat)
+	t.Run("ChatFinishReasons", c.testFinishReasons)
+	t.Run("ChatStream", c.testChatStream)
+	t.Run("ChatStreamInterrupted", c.testStreamInterrupted)
+	t.Run("Tools", c.testTools)
+	t.Run("ToolsStream", c.testToolsStream)
+	t.Run("ToolResults", c.testToolResults)
+	t.Run("Complete", c.testComplete)
+	t.Run("Embed", c.testEmbed)
+	t.Run("Errors", c.testErrors)
+	t.Run("Cancellation", c.testCancellation)
+	t.Run("StreamCancellation", c.testStreamCancellation)
+}
+
+type conformance struct {
+	client gollmx.LLM
+	server FakeServer
+}
+
+// lastBody returns the body of the most recent request to the server
+func (c *conformance) lastBody(t *testing.T) string {
+	t.Helper()
+	requests := c.server.Requests()
+	if len(requests) == 0 {
+		t.Fatal("expected the client to send a request")
+	}
+	return requests[len(requests)-1].Body
+}
+
+// bodiesSince joins the bodies of requests received after the first n, for
+// calls that a provider may split across several requests
+func (c *conformance) bodiesSince(n int) string {
+	var bodies []string
+	for _, r := range c.server.Requests()[n:] {
+		bodies = append(bodies, r.Body)
+	}
+	return strings.Join(bodies, "\n")
+}
+
+func (c *conformance) testMetadata(t *testing.T) {
+	if c.client.ID() == "" {
+		t.Error("expected non-empty ID")
+	}
+	if c.client.Name() == "" {
+		t.Error("expected non-empty Name")
+	}
+	if len(c.client.Models()) == 0 {
+		t.Error("expected at least one model")
+	}
+	if !c.client.HasFeature(gollmx.FeatureChat) {
+		t.Error("expected chat to be supported")
+	}
+	for _, f := range c.client.Features() {
+		if !c.client.HasFeature(f) {
+			t.Errorf("Features lists %s but HasFeature reports false", f)
+		}
+	}
+}
+
+func (c *conformance) testChat(t *testing.T) {
+	resp := TextResponse("Hello from the fake server")
+	resp.Usage = conformanceUsage
+	c.server.Queue(Reply{Chat: resp})
+
+	got, err := c.client.Chat(context.Background(), &gollmx.ChatRequest{
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleSystem, Content: "You are a conformance test."},
+			{Role: gollmx.RoleUser, Content: "Say hello."},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.GetContent() != "Hello from the fake server" {
+		t.Errorf("expected content 'Hello from the fake server', got '%s'", got.GetContent())
+	}
+	if got.Provider != c.client.ID() {
+		t.Errorf("expected provider '%s', got '%s'", c.client.ID(), got.Provider)
+	}
+	if len(got.Choices) == 0 || got.Choices[0].Message.Role != gollmx.RoleAssistant {
+		t.Errorf("expected an assistant choice, got %+v", got.Choices)
+	}
+	if got.Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish reason 'stop', got '%s'", got.Choices[0].FinishReason)
+	}
+	if !sameTokens(got.Usage, conformanceUsage) {
+		t.Errorf("expected usage %+v, got %+v", conformanceUsage, got.Usage)
+	}
+
+	body := c.lastBody(t)
+	if !strings.Contains(body, "You are a conformance test.") {
+		t.Error("expected the system prompt to be sent")
+	}
+	if !strings.Contains(body, "Say hello.") {
+		t.Error("expected the user message to be sent")
+	}
+}
+
+func (c *conformance) testFinishReasons(t *testing.T) {
+	for _, reason := range []string{"stop", "length"} {
+		resp := TextResponse("truncated")
+		resp.Choices[0].FinishReason = reason
+		c.server.Queue(Reply{Chat: resp})
+
+		got, err := c.client.Chat(context.Background(), userRequest("Hi"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Choices[0].FinishReason != reason {
+			t.Errorf("expected finish reason '%s', got '%s'", reason, got.Choices[0].FinishReason)
+		}
+	}
+}
+
+func (c *conformance) testChatStream(t *testing.T) {
+	c.server.Queue(Reply{Stream: []gollmx.StreamChunk{
+		{Content: "Hello"},
+		{Content: ", "},
+		{Content: "world", FinishReason: "stop", Usage: conformanceUsage},
+	}})
+
+	stream, err := c.client.ChatStream(context.Background(), userRequest("Stream a greeting."))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var content string
+	var chunks int
+	var finishReason string
+	var usage gollmx.Usage
+	for {
+		chunk, ok := stream.Next()
+		if !ok {
+			break
+		}
+		chunks++
+		content += chunk.Content
+		if chunk.Provider != c.client.ID() {
+			t.Errorf("expected chunk provider '%s', got '%s'", c.client.ID(), chunk.Provider)
+		}
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+		if chunk.Usage != (gollmx.Usage{}) {
+			usage = chunk.Usage
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	if content != "Hello, world" {
+		t.Errorf("expected content 'Hello, world', got '%s'", content)
+	}
+	if chunks < 2 {
+		t.Errorf("expected content to arrive in multiple chunks, got %d", chunks)
+	}
+	if finishReason != "stop" {
+		t.Errorf("expected finish reason 'stop', got '%s'", finishReason)
+	}
+	if !sameTokens(usage, conformanceUsage) {
+		t.Errorf("expected stream usage %+v, got %+v", conformanceUsage, usage)
+	}
+}
+
+func (c *conformance) testStreamInterrupted(t *testing.T) {
+	c.server.Queue(Reply{Stream: []gollmx.StreamChunk{
+		{Content: "partial"},
+		{Error: errors.New("connection lost")},
+	}})
+
+	stream, err := c.client.ChatStream(context.Background(), userRequest("Hi"))
+	if err != nil {
+		return // failing before the stream starts is acceptable
+	}
+	if _, err := stream.Collect(); err == nil {
+		t.Error("expected an error when the stream is cut off")
+	}
+}
+
+func (c *conformance) requireTools(t *testing.T) {
+	if !c.client.HasFeature(gollmx.FeatureTools) {
+		t.Skip("tools not supported")
+	}
+}
+
+func (c *conformance) testTools(t *testing.T) {
+	c.requireTools(t)
+
+	resp := ToolCallResponse(ToolCall("get_weather", `{"city":"Paris"}`))
+	resp.Usage = conformanceUsage
+	c.server.Queue(Reply{Chat: resp})
+
+	got, err := c.client.Chat(context.Background(), toolRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checkToolCalls(t, got.GetToolCalls())
+	if got.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish reason 'tool_calls', got '%s'", got.Choices[0].FinishReason)
+	}
+	if !strings.Contains(c.lastBody(t), "get_weather") {
+		t.Error("expected the tool definition to be sent")
+	}
+}
+
+func (c *conformance) testToolsStream(t *testing.T) {
+	c.requireTools(t)
+
+	c.server.Queue(Reply{Chat: ToolCallResponse(ToolCall("get_weather", `{"city":"Paris"}`))})
+
+	stream, err := c.client.ChatStream(context.Background(), toolRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	checkToolCalls(t, got.GetToolCalls())
+	if got.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish reason 'tool_calls', got '%s'", got.Choices[0].FinishReason)
+	}
+}
+
+func (c *conformance) testToolResults(t *testing.T) {
+	c.requireTools(t)
+
+	c.server.Queue(Reply{Chat: TextResponse("It is 21 degrees in Paris.")})
+
+	req := toolRequest()
+	req.Messages = append(req.Messages,
+		gollmx.Message{
+			Role:      gollmx.RoleAssistant,
+			Content:   "",
+			ToolCalls: []gollmx.ToolCall{{ID: "call_weather", Type: "function", Function: gollmx.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}}},
+		},
+		gollmx.Message{Role: gollmx.RoleTool, ToolCallID: "call_weather", Name: "get_weather", Content: `{"temperature":21}`},
+	)
+
+	got, err := c.client.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetContent() != "It is 21 degrees in Paris." {
+		t.Errorf("expected final answer, got '%s'", got.GetContent())
+	}
+	if !strings.Contains(c.lastBody(t), "temperature") {
+		t.Error("expected the tool result to be sent")
+	}
+}
+
+func (c *conformance) testComplete(t *testing.T) {
+	if !c.client.HasFeature(gollmx.FeatureCompletion) {
+		t.Skip("completion not supported")
+	}
+
+	resp := TextResponse("Once upon a time")
+	resp.Usage = conformanceUsage
+	c.server.Queue(Reply{Chat: resp})
+
+	got, err := c.client.Complete(context.Background(), &gollmx.CompletionRequest{Prompt: "Start a story."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetText() != "Once upon a time" {
+		t.Errorf("expected text 'Once upon a time', got '%s'", got.GetText())
+	}
+	if got.Provider != c.client.ID() {
+		t.Errorf("expected provider '%s', got '%s'", c.client.ID(), got.Provider)
+	}
+	if len(got.Choices) > 0 && got.Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish reason 'stop', got '%s'", got.Choices[0].FinishReason)
+	}
+	if !strings.Contains(c.lastBody(t), "Start a story.") {
+		t.Error("expected the prompt to be sent")
+	}
+}
+
+func (c *conformance) testEmbed(t *testing.T) {
+	if !c.client.HasFeature(gollmx.FeatureEmbedding) {
+		t.Skip("embeddings not supported")
+	}
+
+	vectors := [][]float64{{0.1, 0.2, 0.3}, {0.4, 0.5, 0.6}}
+	embedResp := EmbedResponse(vectors...)
+	embedResp.Usage = gollmx.Usage{PromptTokens: 4, TotalTokens: 4}
+	c.server.Queue(Reply{Embed: embedResp})
+	sent := len(c.server.Requests())
+
+	got, err := c.client.Embed(context.Background(), &gollmx.EmbedRequest{Input: []string{"first text", "second text"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.Embeddings) != len(vectors) {
+		t.Fatalf("expected %d embeddings, got %d", len(vectors), len(got.Embeddings))
+	}
+	for i, e := range got.Embeddings {
+		if e.Index != i {
+			t.Errorf("expected embedding %d to have index %d, got %d", i, i, e.Index)
+		}
+		if !reflect.DeepEqual(e.Vector, vectors[i]) {
+			t.Errorf("expected embedding %d to be %v, got %v", i, vectors[i], e.Vector)
+		}
+	}
+	if got.Provider != c.client.ID() {
+		t.Errorf("expected provider '%s', got '%s'", c.client.ID(), got.Provider)
+	}
+
+	body := c.bodiesSince(sent)
+	if !strings.Contains(body, "first text") || !strings.Contains(body, "second text") {
+		t.Error("expected all inputs to be sent")
+	}
+}
+
+func (c *conformance) testErrors(t *testing.T) {
+	cases := []struct {
+		errType   gollmx.ErrorType
+		retryable bool
+	}{
+		{gollmx.ErrorTypeAuth, false},
+		{gollmx.ErrorTypeRateLimit, true},
+		{gollmx.ErrorTypeInvalidRequest, false},
+		{gollmx.ErrorTypeServer, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.errType), func(t *testing.T) {
+			message := "fake " + string(tc.errType) + " error"
+
+			c.server.Queue(Reply{Err: gollmx.NewAPIError(tc.errType, "", message)})
+			_, err := c.client.Chat(context.Background(), userRequest("Hi"))
+			c.checkError(t, err, tc.errType, tc.retryable, message)
+
+			c.server.Queue(Reply{Err: gollmx.NewAPIError(tc.errType, "", message)})
+			stream, err := c.client.ChatStream(context.Background(), userRequest("Hi"))
+			if err == nil {
+				_, err = stream.Collect()
+			}
+			c.checkError(t, err, tc.errType, tc.retryable, message)
+		})
+	}
+}
+
+func (c *conformance) checkError(t *testing.T, err error, errType gollmx.ErrorType, retryable bool, message string) {
+	t.Helper()
+
+	var apiErr *gollmx.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *gollmx.APIError, got %T: %v", err, err)
+	}
+	if apiErr.Type != errType {
+		t.Errorf("expected error type '%s', got '%s'", errType, apiErr.Type)
+	}
+	if apiErr.Provider != c.client.ID() {
+		t.Errorf("expected error provider '%s', got '%s'", c.client.ID(), apiErr.Provider)
+	}
+	if apiErr.IsRetryable() != retryable {
+		t.Errorf("expected retryable=%v for %s, got %v", retryable, errType, apiErr.IsRetryable())
+	}
+	if !strings.Contains(apiErr.Error(), message) {
+		t.Errorf("expected error message to contain '%s', got '%s'", message, apiErr.Error())
+	}
+}
+
+func (c *conformance) testCancellation(t *testing.T) {
+	c.server.Queue(Reply{Chat: TextResponse("too late"), Delay: 10 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.client.Chat(ctx, userRequest("Hi")); err == nil {
+		t.Error("expected an error when the context is cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected Chat to return promptly after cancellation, took %s", elapsed)
+	}
+}
+
+func (c *conformance) testStreamCancellation(t *testing.T) {
+	c.server.Queue(Reply{Stream: []gollmx.StreamChunk{{Content: "never"}}, Delay: 10 * time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		stream, err := c.client.ChatStream(ctx, userRequest("Hi"))
+		if err != nil {
+			done <- err
+			return
+		}
+		for {
+			if _, ok := stream.Next(); !ok {
+				break
+			}
+		}
+		done <- stream.Err()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the stream to end promptly after cancellation")
+	}
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func userRequest(text string) *gollmx.ChatRequest {
+	return &gollmx.ChatRequest{
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: text}},
+	}
+}
+
+func toolRequest() *gollmx.ChatRequest {
+	req := userRequest("What's the weather in Paris?")
+	req.Tools = []gollmx.Tool{{
+		Type: "function",
+		Function: gollmx.Function{
+			Name:        "get_weather",
+			Description: "Get the current weather for a city",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`),
+		},
+	}}
+	return req
+}
+
+func checkToolCalls(t *testing.T, calls []gollmx.ToolCall) {
+	t.Helper()
+
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d: %+v", len(calls), calls)
+	}
+	call := calls[0]
+	if call.ID == "" {
+		t.Error("expected tool call to have an ID")
+	}
+	if call.Function.Name != "get_weather" {
+		t.Errorf("expected tool 'get_weather', got '%s'", call.Function.Name)
+	}
+
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+		t.Fatalf("expected tool arguments to be valid JSON, got '%s'", call.Function.Arguments)
+	}
+	if args["city"] != "Paris" {
+		t.Errorf("expected argument city=Paris, got %v", args)
+	}
+}
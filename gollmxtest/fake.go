@@ -0,0 +1,250 @@
+package gollmxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// =============================================================================
+// Fake Servers
+// =============================================================================
+
+// FakeServer is a local HTTP server that speaks a provider's wire protocol.
+// It answers each request with the next queued Reply, translated into the
+// provider's response format. Errors are returned with the status code and
+// error body the provider would use for that error type.
+type FakeServer interface {
+	// URL returns the base URL to pass to gollmx.WithBaseURL
+	URL() string
+
+	// Queue appends replies to the reply queue
+	Queue(replies ...Reply)
+
+	// Requests returns every request received so far
+	Requests() []RecordedRequest
+
+	// Close shuts down the server
+	Close()
+}
+
+// fakeHandler writes reply in a provider's wire format
+type fakeHandler func(s *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply)
+
+// fakeServer implements the queueing and recording shared by all fakes
+type fakeServer struct {
+	server   *httptest.Server
+	handle   fakeHandler
+	mu       sync.Mutex
+	replies  []Reply
+	requests []RecordedRequest
+}
+
+func newFakeServer(handle fakeHandler) *fakeServer {
+	s := &fakeServer{handle: handle}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// URL returns the base URL of the server
+func (s *fakeServer) URL() string {
+	return s.server.URL
+}
+
+// Queue appends replies to the reply queue
+func (s *fakeServer) Queue(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = append(s.replies, replies...)
+}
+
+// Requests returns every request received so far
+func (s *fakeServer) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// Close shuts down the server
+func (s *fakeServer) Close() {
+	s.server.Close()
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readRequestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method:  r.Method,
+		URL:     r.URL.String(),
+		Headers: r.Header.Clone(),
+		Body:    string(body),
+	})
+	if len(s.replies) == 0 {
+		s.mu.Unlock()
+		http.Error(w, ErrNoReply.Error(), http.StatusInternalServerError)
+		return
+	}
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	s.mu.Unlock()
+
+	s.handle(s, w, r, body, reply)
+}
+
+// requeue puts reply back at the front of the queue, for protocols that
+// split one scripted reply across several requests
+func (s *fakeServer) requeue(reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = append([]Reply{reply}, s.replies...)
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// ErrorStatus returns the HTTP status code providers use for an error type
+func ErrorStatus(errType gollmx.ErrorType) int {
+	switch errType {
+	case gollmx.ErrorTypeAuth:
+		return http.StatusUnauthorized
+	case gollmx.ErrorTypeRateLimit, gollmx.ErrorTypeQuota:
+		return http.StatusTooManyRequests
+	case gollmx.ErrorTypeInvalidRequest, gollmx.ErrorTypeContentFilter, gollmx.ErrorTypeUnsupportedFeature:
+		return http.StatusBadRequest
+	case gollmx.ErrorTypeModelNotFound:
+		return http.StatusNotFound
+	case gollmx.ErrorTypeTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// replyError returns the status code and message for a reply error
+func replyError(err error) (int, string) {
+	var apiErr *gollmx.APIError
+	if errors.As(err, &apiErr) {
+		status := apiErr.StatusCode
+		if status == 0 {
+			status = ErrorStatus(apiErr.Type)
+		}
+		return status, apiErr.Message
+	}
+	return http.StatusInternalServerError, err.Error()
+}
+
+// wait sleeps for the reply delay, reporting false if the client went away
+func wait(ctx context.Context, reply Reply) bool {
+	return sleep(ctx, reply.Delay) == nil
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// streamWriter writes a streaming response, flushing after every event
+type streamWriter struct {
+	w       http.ResponseWriter
+	r       *http.Request
+	flusher http.Flusher
+}
+
+func newStreamWriter(w http.ResponseWriter, r *http.Request, contentType string) *streamWriter {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	sw := &streamWriter{w: w, r: r, flusher: flusher}
+	sw.flush()
+	return sw
+}
+
+// sse writes a server-sent event; event may be empty
+func (s *streamWriter) sse(event string, data interface{}) {
+	if event != "" {
+		fmt.Fprintf(s.w, "event: %s\n", event)
+	}
+	fmt.Fprintf(s.w, "data: %s\n\n", marshal(data))
+	s.flush()
+}
+
+// line writes a single newline-delimited JSON object
+func (s *streamWriter) line(data interface{}) {
+	fmt.Fprintf(s.w, "%s\n", marshal(data))
+	s.flush()
+}
+
+func (s *streamWriter) flush() {
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// chunks writes each chunk with write after the reply delay. A chunk with
+// Error set aborts the connection mid-stream. It reports false if the
+// stream ended early.
+func (s *streamWriter) chunks(reply Reply, write func(chunk gollmx.StreamChunk, last bool)) bool {
+	chunks, _ := reply.chunks()
+	for i, chunk := range chunks {
+		if !wait(s.r.Context(), reply) {
+			return false
+		}
+		if chunk.Error != nil {
+			panic(http.ErrAbortHandler)
+		}
+		write(chunk, i == len(chunks)-1)
+	}
+	return true
+}
+
+func marshal(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// streamUsage returns the last non-zero usage reported by chunks
+func streamUsage(chunks []gollmx.StreamChunk) gollmx.Usage {
+	var usage gollmx.Usage
+	for _, c := range chunks {
+		if c.Usage != (gollmx.Usage{}) {
+			usage = c.Usage
+		}
+	}
+	return usage
+}
+
+// streamFinishReason returns the last finish reason reported by chunks
+func streamFinishReason(chunks []gollmx.StreamChunk) string {
+	var reason string
+	for _, c := range chunks {
+		if c.FinishReason != "" {
+			reason = c.FinishReason
+		}
+	}
+	return reason
+}
+
+// toolArguments decodes tool call arguments into a JSON object, falling
+// back to an empty object for blank or invalid arguments
+func toolArguments(arguments string) map[string]interface{} {
+	args := map[string]interface{}{}
+	json.Unmarshal([]byte(arguments), &args)
+	return args
+}
@@ -0,0 +1,207 @@
+package gollmxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// NewAnthropicServer starts a fake server for the Anthropic Messages API,
+// including its named server-sent event stream
+func NewAnthropicServer() FakeServer {
+	return newFakeServer(handleAnthropic)
+}
+
+type anthropicFakeRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+func handleAnthropic(_ *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply) {
+	if !strings.HasSuffix(r.URL.Path, "/messages") {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req anthropicFakeRequest
+	json.Unmarshal(body, &req)
+
+	if reply.Err != nil {
+		if !wait(r.Context(), reply) {
+			return
+		}
+		status, message := replyError(reply.Err)
+		writeJSON(w, status, anthropicError(status, message))
+		return
+	}
+
+	if req.Stream {
+		streamAnthropic(w, r, &req, reply)
+		return
+	}
+
+	resp, _, err := reply.response()
+	if resp == nil || err != nil {
+		http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+		return
+	}
+	if !wait(r.Context(), reply) {
+		return
+	}
+
+	var content []map[string]interface{}
+	var finishReason string
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		finishReason = choice.FinishReason
+		if text := resp.GetContent(); text != "" {
+			content = append(content, map[string]interface{}{"type": "text", "text": text})
+		}
+		for _, tc := range choice.Message.ToolCalls {
+			content = append(content, map[string]interface{}{
+				"type":  "tool_use",
+				"id":    tc.ID,
+				"name":  tc.Function.Name,
+				"input": toolArguments(tc.Function.Arguments),
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":            fakeID(resp.ID),
+		"type":          "message",
+		"role":          "assistant",
+		"content":       content,
+		"model":         fakeModel(resp.Model, req.Model),
+		"stop_reason":   anthropicStopReason(finishReason),
+		"stop_sequence": nil,
+		"usage":         anthropicUsage(resp.Usage),
+	})
+}
+
+func streamAnthropic(w http.ResponseWriter, r *http.Request, req *anthropicFakeRequest, reply Reply) {
+	chunks, _ := reply.chunks()
+	usage := streamUsage(chunks)
+
+	sw := newStreamWriter(w, r, "text/event-stream")
+
+	// Input tokens are reported up front and output tokens at the end
+	id := "msg_fake"
+	if len(chunks) > 0 {
+		id = fakeID(chunks[0].ID)
+	}
+	sw.sse("message_start", map[string]interface{}{
+		"type": "message_start",
+		"message": map[string]interface{}{
+			"id":            id,
+			"type":          "message",
+			"role":          "assistant",
+			"content":       []interface{}{},
+			"model":         req.Model,
+			"stop_reason":   nil,
+			"stop_sequence": nil,
+			"usage":         map[string]int{"input_tokens": usage.PromptTokens, "output_tokens": 1},
+		},
+	})
+
+	index := 0
+	textOpen := false
+	closeText := func() {
+		if textOpen {
+			sw.sse("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+			index++
+			textOpen = false
+		}
+	}
+
+	completed := sw.chunks(reply, func(chunk gollmx.StreamChunk, last bool) {
+		if chunk.Content != "" {
+			if !textOpen {
+				sw.sse("content_block_start", map[string]interface{}{
+					"type":          "content_block_start",
+					"index":         index,
+					"content_block": map[string]interface{}{"type": "text", "text": ""},
+				})
+				textOpen = true
+			}
+			sw.sse("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": index,
+				"delta": map[string]interface{}{"type": "text_delta", "text": chunk.Content},
+			})
+		}
+
+		for _, tc := range chunk.ToolCalls {
+			closeText()
+			sw.sse("content_block_start", map[string]interface{}{
+				"type":  "content_block_start",
+				"index": index,
+				"content_block": map[string]interface{}{
+					"type":  "tool_use",
+					"id":    tc.ID,
+					"name":  tc.Function.Name,
+					"input": map[string]interface{}{},
+				},
+			})
+			sw.sse("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": index,
+				"delta": map[string]interface{}{"type": "input_json_delta", "partial_json": tc.Function.Arguments},
+			})
+			sw.sse("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+			index++
+		}
+	})
+	if !completed {
+		return
+	}
+	closeText()
+
+	sw.sse("message_delta", map[string]interface{}{
+		"type": "message_delta",
+		"delta": map[string]interface{}{
+			"stop_reason":   anthropicStopReason(streamFinishReason(chunks)),
+			"stop_sequence": nil,
+		},
+		"usage": map[string]int{"output_tokens": usage.CompletionTokens},
+	})
+	sw.sse("message_stop", map[string]interface{}{"type": "message_stop"})
+}
+
+func anthropicError(status int, message string) map[string]interface{} {
+	errType := "api_error"
+	switch status {
+	case http.StatusUnauthorized:
+		errType = "authentication_error"
+	case http.StatusTooManyRequests:
+		errType = "rate_limit_error"
+	case http.StatusBadRequest:
+		errType = "invalid_request_error"
+	case http.StatusNotFound:
+		errType = "not_found_error"
+	}
+	return map[string]interface{}{
+		"type":  "error",
+		"error": map[string]interface{}{"type": errType, "message": message},
+	}
+}
+
+func anthropicStopReason(finishReason string) string {
+	switch finishReason {
+	case "length":
+		return "max_tokens"
+	case "tool_calls":
+		return "tool_use"
+	default:
+		return "end_turn"
+	}
+}
+
+func anthropicUsage(u gollmx.Usage) map[string]int {
+	return map[string]int{
+		"input_tokens":  u.PromptTokens,
+		"output_tokens": u.CompletionTokens,
+	}
+}
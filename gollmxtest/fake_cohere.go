@@ -0,0 +1,209 @@
+package gollmxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// NewCohereServer starts a fake server for the Cohere v2 /v2/chat (with its
+// typed server-sent event stream) and /v2/embed endpoints
+func NewCohereServer() FakeServer {
+	return cohereServer{newFakeServer(handleCohere)}
+}
+
+// cohereServer serves under a versioned base URL, as the Cohere API does
+type cohereServer struct {
+	*fakeServer
+}
+
+// URL returns the base URL of the server, including the API version
+func (s cohereServer) URL() string {
+	return s.fakeServer.URL() + "/v2"
+}
+
+type cohereFakeRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+func handleCohere(_ *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply) {
+	var req cohereFakeRequest
+	json.Unmarshal(body, &req)
+
+	if reply.Err != nil {
+		if !wait(r.Context(), reply) {
+			return
+		}
+		status, message := replyError(reply.Err)
+		writeJSON(w, status, map[string]string{"message": message})
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/v2/chat"):
+		if req.Stream {
+			streamCohere(w, r, reply)
+			return
+		}
+		resp, _, err := reply.response()
+		if resp == nil || err != nil {
+			http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+
+		message := map[string]interface{}{"role": "assistant"}
+		if text := resp.GetContent(); text != "" {
+			message["content"] = []map[string]string{{"type": "text", "text": text}}
+		}
+		if calls := resp.GetToolCalls(); len(calls) > 0 {
+			message["tool_plan"] = "I will call the requested tools."
+			message["tool_calls"] = cohereToolCalls(calls)
+		}
+		var finishReason string
+		if len(resp.Choices) > 0 {
+			finishReason = resp.Choices[0].FinishReason
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":            fakeID(resp.ID),
+			"finish_reason": cohereFinishReason(finishReason),
+			"message":       message,
+			"usage":         cohereUsage(resp.Usage),
+		})
+
+	case strings.HasSuffix(r.URL.Path, "/v2/embed"):
+		if reply.Embed == nil {
+			http.Error(w, "reply is not an embedding response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		vectors := make([][]float64, len(reply.Embed.Embeddings))
+		for i, e := range reply.Embed.Embeddings {
+			vectors[i] = e.Vector
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":         "fake-embed",
+			"embeddings": map[string]interface{}{"float": vectors},
+			"meta": map[string]interface{}{
+				"api_version":  map[string]string{"version": "2"},
+				"billed_units": map[string]int{"input_tokens": reply.Embed.Usage.TotalTokens},
+			},
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func streamCohere(w http.ResponseWriter, r *http.Request, reply Reply) {
+	chunks, ok := reply.chunks()
+	if !ok {
+		http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+		return
+	}
+
+	sw := newStreamWriter(w, r, "text/event-stream")
+	event := func(eventType string, index int, delta interface{}) {
+		data := map[string]interface{}{"type": eventType, "index": index}
+		if delta != nil {
+			data["delta"] = delta
+		}
+		sw.sse(eventType, data)
+	}
+
+	id := "fake-response"
+	if len(chunks) > 0 {
+		id = fakeID(chunks[0].ID)
+	}
+	sw.sse("message-start", map[string]interface{}{
+		"type":  "message-start",
+		"id":    id,
+		"delta": map[string]interface{}{"message": map[string]string{"role": "assistant"}},
+	})
+
+	textOpen := false
+	toolIndex := 0
+	completed := sw.chunks(reply, func(chunk gollmx.StreamChunk, last bool) {
+		if chunk.Content != "" {
+			if !textOpen {
+				event("content-start", 0, map[string]interface{}{
+					"message": map[string]interface{}{"content": map[string]string{"type": "text", "text": ""}},
+				})
+				textOpen = true
+			}
+			event("content-delta", 0, map[string]interface{}{
+				"message": map[string]interface{}{"content": map[string]string{"text": chunk.Content}},
+			})
+		}
+
+		for _, tc := range chunk.ToolCalls {
+			event("tool-call-start", toolIndex, map[string]interface{}{
+				"message": map[string]interface{}{"tool_calls": map[string]interface{}{
+					"id":       tc.ID,
+					"type":     "function",
+					"function": map[string]string{"name": tc.Function.Name, "arguments": ""},
+				}},
+			})
+			event("tool-call-delta", toolIndex, map[string]interface{}{
+				"message": map[string]interface{}{"tool_calls": map[string]interface{}{
+					"function": map[string]string{"arguments": tc.Function.Arguments},
+				}},
+			})
+			event("tool-call-end", toolIndex, nil)
+			toolIndex++
+		}
+	})
+	if !completed {
+		return
+	}
+	if textOpen {
+		event("content-end", 0, nil)
+	}
+
+	sw.sse("message-end", map[string]interface{}{
+		"type": "message-end",
+		"delta": map[string]interface{}{
+			"finish_reason": cohereFinishReason(streamFinishReason(chunks)),
+			"usage":         cohereUsage(streamUsage(chunks)),
+		},
+	})
+}
+
+func cohereToolCalls(calls []gollmx.ToolCall) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(calls))
+	for i, tc := range calls {
+		out[i] = map[string]interface{}{
+			"id":   tc.ID,
+			"type": "function",
+			"function": map[string]string{
+				"name":      tc.Function.Name,
+				"arguments": tc.Function.Arguments,
+			},
+		}
+	}
+	return out
+}
+
+func cohereFinishReason(finishReason string) string {
+	switch finishReason {
+	case "length":
+		return "MAX_TOKENS"
+	case "tool_calls":
+		return "TOOL_CALL"
+	default:
+		return "COMPLETE"
+	}
+}
+
+func cohereUsage(u gollmx.Usage) map[string]interface{} {
+	counts := map[string]int{"input_tokens": u.PromptTokens, "output_tokens": u.CompletionTokens}
+	return map[string]interface{}{"billed_units": counts, "tokens": counts}
+}
@@ -0,0 +1,153 @@
+package gollmxtest
+
+import (
+	"net/http"
+	"strings"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// NewGeminiServer starts a fake server for the Gemini generateContent,
+// streamGenerateContent (alt=sse) and batchEmbedContents endpoints. Like
+// the real API, function calls finish with STOP.
+func NewGeminiServer() FakeServer {
+	return newFakeServer(handleGemini)
+}
+
+func handleGemini(_ *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply) {
+	// Paths look like /v1beta/models/{model}:{method}
+	i := strings.Index(r.URL.Path, "/models/")
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	model, method, found := strings.Cut(r.URL.Path[i+len("/models/"):], ":")
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	if model == "" {
+		writeJSON(w, http.StatusNotFound, geminiError(http.StatusNotFound, "model name is required"))
+		return
+	}
+
+	if reply.Err != nil {
+		if !wait(r.Context(), reply) {
+			return
+		}
+		status, message := replyError(reply.Err)
+		writeJSON(w, status, geminiError(status, message))
+		return
+	}
+
+	switch method {
+	case "generateContent":
+		resp, _, err := reply.response()
+		if resp == nil || err != nil {
+			http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		var finishReason string
+		if len(resp.Choices) > 0 {
+			finishReason = resp.Choices[0].FinishReason
+		}
+		writeJSON(w, http.StatusOK, geminiResponse(resp.GetContent(), resp.GetToolCalls(), finishReason, &resp.Usage))
+
+	case "streamGenerateContent":
+		if _, ok := reply.chunks(); !ok {
+			http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+			return
+		}
+		sw := newStreamWriter(w, r, "text/event-stream")
+		sw.chunks(reply, func(chunk gollmx.StreamChunk, last bool) {
+			var usage *gollmx.Usage
+			if chunk.Usage != (gollmx.Usage{}) {
+				usage = &chunk.Usage
+			}
+			sw.sse("", geminiResponse(chunk.Content, chunk.ToolCalls, chunk.FinishReason, usage))
+		})
+
+	case "batchEmbedContents":
+		if reply.Embed == nil {
+			http.Error(w, "reply is not an embedding response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		embeddings := make([]map[string]interface{}, len(reply.Embed.Embeddings))
+		for i, e := range reply.Embed.Embeddings {
+			embeddings[i] = map[string]interface{}{"values": e.Vector}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"embeddings": embeddings})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func geminiResponse(text string, toolCalls []gollmx.ToolCall, finishReason string, usage *gollmx.Usage) map[string]interface{} {
+	parts := []map[string]interface{}{}
+	if text != "" {
+		parts = append(parts, map[string]interface{}{"text": text})
+	}
+	for _, tc := range toolCalls {
+		parts = append(parts, map[string]interface{}{
+			"functionCall": map[string]interface{}{
+				"name": tc.Function.Name,
+				"args": toolArguments(tc.Function.Arguments),
+			},
+		})
+	}
+
+	candidate := map[string]interface{}{
+		"content": map[string]interface{}{"role": "model", "parts": parts},
+		"index":   0,
+	}
+	if reason := geminiFinishReason(finishReason); reason != "" {
+		candidate["finishReason"] = reason
+	}
+
+	resp := map[string]interface{}{"candidates": []interface{}{candidate}}
+	if usage != nil {
+		resp["usageMetadata"] = map[string]int{
+			"promptTokenCount":     usage.PromptTokens,
+			"candidatesTokenCount": usage.CompletionTokens,
+			"totalTokenCount":      usage.TotalTokens,
+		}
+	}
+	return resp
+}
+
+func geminiFinishReason(finishReason string) string {
+	switch finishReason {
+	case "":
+		return ""
+	case "length":
+		return "MAX_TOKENS"
+	case "content_filter":
+		return "SAFETY"
+	default:
+		return "STOP"
+	}
+}
+
+func geminiError(status int, message string) map[string]interface{} {
+	code := "INTERNAL"
+	switch status {
+	case http.StatusUnauthorized:
+		code = "UNAUTHENTICATED"
+	case http.StatusTooManyRequests:
+		code = "RESOURCE_EXHAUSTED"
+	case http.StatusBadRequest:
+		code = "INVALID_ARGUMENT"
+	case http.StatusNotFound:
+		code = "NOT_FOUND"
+	}
+	return map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": message, "status": code},
+	}
+}
@@ -0,0 +1,166 @@
+package gollmxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// NewOllamaServer starts a fake server for the Ollama /api/chat,
+// /api/generate, /api/embed and legacy /api/embeddings endpoints. Streams
+// are newline-delimited JSON, and requests stream unless they set
+// "stream": false. The legacy embeddings endpoint embeds one prompt per
+// request, so each call consumes one vector of the queued embed reply.
+func NewOllamaServer() FakeServer {
+	return newFakeServer(handleOllama)
+}
+
+type ollamaFakeRequest struct {
+	Model  string `json:"model"`
+	Stream *bool  `json:"stream"`
+}
+
+func handleOllama(s *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply) {
+	var req ollamaFakeRequest
+	json.Unmarshal(body, &req)
+	stream := req.Stream == nil || *req.Stream
+
+	if reply.Err != nil {
+		if !wait(r.Context(), reply) {
+			return
+		}
+		status, message := replyError(reply.Err)
+		writeJSON(w, status, map[string]string{"error": message})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/chat", "/api/generate":
+		generate := r.URL.Path == "/api/generate"
+		if stream {
+			chunks, ok := reply.chunks()
+			if !ok {
+				http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+				return
+			}
+			usage := streamUsage(chunks)
+			finishReason := streamFinishReason(chunks)
+
+			sw := newStreamWriter(w, r, "application/x-ndjson")
+			completed := sw.chunks(reply, func(chunk gollmx.StreamChunk, last bool) {
+				// Finish reason and usage are reported on the final line
+				if chunk.Content == "" && len(chunk.ToolCalls) == 0 {
+					return
+				}
+				sw.line(ollamaMessage(req.Model, generate, chunk.Content, chunk.ToolCalls))
+			})
+			if completed {
+				final := ollamaMessage(req.Model, generate, "", nil)
+				ollamaDone(final, finishReason, usage)
+				sw.line(final)
+			}
+			return
+		}
+
+		resp, _, err := reply.response()
+		if resp == nil || err != nil {
+			http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		var finishReason string
+		if len(resp.Choices) > 0 {
+			finishReason = resp.Choices[0].FinishReason
+		}
+		msg := ollamaMessage(fakeModel(resp.Model, req.Model), generate, resp.GetContent(), resp.GetToolCalls())
+		ollamaDone(msg, finishReason, resp.Usage)
+		writeJSON(w, http.StatusOK, msg)
+
+	case "/api/embed":
+		if reply.Embed == nil {
+			http.Error(w, "reply is not an embedding response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		embeddings := make([][]float64, len(reply.Embed.Embeddings))
+		for i, e := range reply.Embed.Embeddings {
+			embeddings[i] = e.Vector
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"model":             req.Model,
+			"embeddings":        embeddings,
+			"total_duration":    int64(time.Millisecond),
+			"load_duration":     int64(time.Microsecond),
+			"prompt_eval_count": reply.Embed.Usage.PromptTokens,
+		})
+
+	case "/api/embeddings":
+		if reply.Embed == nil || len(reply.Embed.Embeddings) == 0 {
+			http.Error(w, "reply is not an embedding response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		if len(reply.Embed.Embeddings) > 1 {
+			rest := *reply.Embed
+			rest.Embeddings = rest.Embeddings[1:]
+			s.requeue(Reply{Embed: &rest})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"embedding": reply.Embed.Embeddings[0].Vector})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// ollamaMessage builds a chat or generate response object
+func ollamaMessage(model string, generate bool, content string, toolCalls []gollmx.ToolCall) map[string]interface{} {
+	msg := map[string]interface{}{
+		"model":      model,
+		"created_at": time.Now().UTC().Format(time.RFC3339Nano),
+		"done":       false,
+	}
+	if generate {
+		msg["response"] = content
+		return msg
+	}
+
+	message := map[string]interface{}{"role": "assistant", "content": content}
+	if len(toolCalls) > 0 {
+		calls := make([]map[string]interface{}, len(toolCalls))
+		for i, tc := range toolCalls {
+			calls[i] = map[string]interface{}{
+				"function": map[string]interface{}{
+					"name":      tc.Function.Name,
+					"arguments": toolArguments(tc.Function.Arguments),
+				},
+			}
+		}
+		message["tool_calls"] = calls
+	}
+	msg["message"] = message
+	return msg
+}
+
+// ollamaDone marks msg as the final message with timings and token counts
+func ollamaDone(msg map[string]interface{}, finishReason string, usage gollmx.Usage) {
+	doneReason := "stop"
+	if finishReason == "length" {
+		doneReason = "length"
+	}
+	msg["done"] = true
+	msg["done_reason"] = doneReason
+	msg["total_duration"] = int64(5 * time.Millisecond)
+	msg["load_duration"] = int64(time.Millisecond)
+	msg["prompt_eval_count"] = usage.PromptTokens
+	msg["prompt_eval_duration"] = int64(time.Millisecond)
+	msg["eval_count"] = usage.CompletionTokens
+	msg["eval_duration"] = int64(3 * time.Millisecond)
+}
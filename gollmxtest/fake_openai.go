@@ -0,0 +1,226 @@
+package gollmxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// NewOpenAIServer starts a fake server for the OpenAI chat completions and
+// embeddings API. It also serves OpenAI-compatible providers such as Groq
+// and Mistral. Stream usage is sent in a trailing chunk when the request
+// sets stream_options.include_usage, and on the final chunk otherwise.
+func NewOpenAIServer() FakeServer {
+	return newFakeServer(handleOpenAI)
+}
+
+type openAIFakeRequest struct {
+	Model         string `json:"model"`
+	Stream        bool   `json:"stream"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+}
+
+func handleOpenAI(_ *fakeServer, w http.ResponseWriter, r *http.Request, body []byte, reply Reply) {
+	var req openAIFakeRequest
+	json.Unmarshal(body, &req)
+
+	if reply.Err != nil {
+		if !wait(r.Context(), reply) {
+			return
+		}
+		status, message := replyError(reply.Err)
+		writeJSON(w, status, map[string]interface{}{
+			"error": map[string]interface{}{
+				"message": message,
+				"type":    openAIErrorType(status),
+				"code":    nil,
+			},
+		})
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+		if req.Stream {
+			streamOpenAIChat(w, r, &req, reply)
+			return
+		}
+		resp, _, err := reply.response()
+		if resp == nil || err != nil {
+			http.Error(w, "reply is not a chat response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		writeJSON(w, http.StatusOK, openAIChatResponse(resp, req.Model))
+
+	case strings.HasSuffix(r.URL.Path, "/embeddings"):
+		if reply.Embed == nil {
+			http.Error(w, "reply is not an embedding response", http.StatusInternalServerError)
+			return
+		}
+		if !wait(r.Context(), reply) {
+			return
+		}
+		data := make([]map[string]interface{}, len(reply.Embed.Embeddings))
+		for i, e := range reply.Embed.Embeddings {
+			data[i] = map[string]interface{}{"object": "embedding", "index": e.Index, "embedding": e.Vector}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"object": "list",
+			"data":   data,
+			"model":  req.Model,
+			"usage": map[string]int{
+				"prompt_tokens": reply.Embed.Usage.PromptTokens,
+				"total_tokens":  reply.Embed.Usage.TotalTokens,
+			},
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func openAIErrorType(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case http.StatusBadRequest, http.StatusNotFound:
+		return "invalid_request_error"
+	}
+	return "server_error"
+}
+
+func openAIChatResponse(resp *gollmx.ChatResponse, model string) map[string]interface{} {
+	choices := make([]map[string]interface{}, len(resp.Choices))
+	for i, c := range resp.Choices {
+		message := map[string]interface{}{
+			"role":    "assistant",
+			"content": c.Message.Content,
+		}
+		if len(c.Message.ToolCalls) > 0 {
+			message["content"] = nil
+			message["tool_calls"] = openAIToolCalls(c.Message.ToolCalls)
+		}
+		choices[i] = map[string]interface{}{
+			"index":         i,
+			"message":       message,
+			"finish_reason": c.FinishReason,
+		}
+	}
+
+	return map[string]interface{}{
+		"id":      fakeID(resp.ID),
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   fakeModel(resp.Model, model),
+		"choices": choices,
+		"usage":   openAIUsage(resp.Usage),
+	}
+}
+
+func streamOpenAIChat(w http.ResponseWriter, r *http.Request, req *openAIFakeRequest, reply Reply) {
+	includeUsage := req.StreamOptions != nil && req.StreamOptions.IncludeUsage
+	sw := newStreamWriter(w, r, "text/event-stream")
+
+	var id string
+	var usage gollmx.Usage
+	completed := sw.chunks(reply, func(chunk gollmx.StreamChunk, last bool) {
+		id = fakeID(chunk.ID)
+		if chunk.Usage != (gollmx.Usage{}) {
+			usage = chunk.Usage
+		}
+
+		delta := map[string]interface{}{}
+		if chunk.Content != "" {
+			delta["content"] = chunk.Content
+		}
+		if len(chunk.ToolCalls) > 0 {
+			delta["tool_calls"] = openAIToolCalls(chunk.ToolCalls)
+		}
+
+		var finishReason interface{}
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+
+		event := map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   fakeModel(chunk.Model, req.Model),
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"delta":         delta,
+				"finish_reason": finishReason,
+			}},
+		}
+		if last && !includeUsage {
+			event["usage"] = openAIUsage(usage)
+		}
+		sw.sse("", event)
+	})
+	if !completed {
+		return
+	}
+
+	if includeUsage {
+		sw.sse("", map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []interface{}{},
+			"usage":   openAIUsage(usage),
+		})
+	}
+	sw.sse("", "[DONE]")
+}
+
+func openAIToolCalls(calls []gollmx.ToolCall) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(calls))
+	for i, tc := range calls {
+		out[i] = map[string]interface{}{
+			"index": i,
+			"id":    tc.ID,
+			"type":  "function",
+			"function": map[string]interface{}{
+				"name":      tc.Function.Name,
+				"arguments": tc.Function.Arguments,
+			},
+		}
+	}
+	return out
+}
+
+func openAIUsage(u gollmx.Usage) map[string]int {
+	return map[string]int{
+		"prompt_tokens":     u.PromptTokens,
+		"completion_tokens": u.CompletionTokens,
+		"total_tokens":      u.TotalTokens,
+	}
+}
+
+// fakeID returns id, or a fixed response ID when it is empty
+func fakeID(id string) string {
+	if id == "" {
+		return "fake-response"
+	}
+	return id
+}
+
+// fakeModel returns the reply model, falling back to the requested model
+func fakeModel(model, requested string) string {
+	if model == "" {
+		return requested
+	}
+	return model
+}
@@ -0,0 +1,111 @@
+package gollmxtest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func post(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestFakeServerEmptyQueue(t *testing.T) {
+	server := NewOpenAIServer()
+	defer server.Close()
+
+	status, body := post(t, server.URL()+"/v1/chat/completions", `{"model":"gpt-4o"}`)
+	if status != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", status)
+	}
+	if !strings.Contains(body, ErrNoReply.Error()) {
+		t.Errorf("expected body to mention the empty queue, got %q", body)
+	}
+	if len(server.Requests()) != 1 {
+		t.Errorf("expected 1 recorded request, got %d", len(server.Requests()))
+	}
+}
+
+func TestAnthropicServerStream(t *testing.T) {
+	server := NewAnthropicServer()
+	defer server.Close()
+
+	server.Queue(Reply{Stream: []gollmx.StreamChunk{
+		{Content: "Hel"},
+		{Content: "lo", FinishReason: "length", Usage: gollmx.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}},
+	}})
+
+	status, body := post(t, server.URL()+"/v1/messages", `{"model":"claude-3-5-haiku-latest","stream":true}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+
+	events := []string{
+		"event: message_start", "event: content_block_start", "event: content_block_delta",
+		"event: content_block_stop", "event: message_delta", "event: message_stop",
+	}
+	last := -1
+	for _, event := range events {
+		i := strings.Index(body, event)
+		if i < last {
+			t.Fatalf("expected %q after the previous event in %q", event, body)
+		}
+		last = i
+	}
+	if !strings.Contains(body, `"stop_reason":"max_tokens"`) {
+		t.Errorf("expected stop reason 'max_tokens' in %q", body)
+	}
+}
+
+func TestOllamaServerErrors(t *testing.T) {
+	server := NewOllamaServer()
+	defer server.Close()
+
+	server.Queue(Reply{Err: gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, "ollama", "model 'llama9' not found")})
+
+	status, body := post(t, server.URL()+"/api/chat", `{"model":"llama9"}`)
+	if status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+	if strings.TrimSpace(body) != `{"error":"model 'llama9' not found"}` {
+		t.Errorf("unexpected error body: %q", body)
+	}
+}
+
+func TestOllamaServerStream(t *testing.T) {
+	server := NewOllamaServer()
+	defer server.Close()
+
+	server.Queue(Reply{Chat: TextResponse("Hi there")})
+
+	_, body := post(t, server.URL()+"/api/chat", `{"model":"llama3.2"}`)
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d: %q", len(lines), body)
+	}
+	if !strings.Contains(lines[1], `"done":true`) || !strings.Contains(lines[1], `"done_reason":"stop"`) {
+		t.Errorf("expected final line to be done, got %q", lines[1])
+	}
+}
+
+func TestGeminiServerRequiresModel(t *testing.T) {
+	server := NewGeminiServer()
+	defer server.Close()
+
+	server.Queue(Reply{Chat: TextResponse("unused")})
+
+	status, _ := post(t, server.URL()+"/v1beta/models/:generateContent", `{}`)
+	if status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+}
@@ -0,0 +1,708 @@
+// Package gollmxtest provides utilities for testing code that uses gollm-x.
+//
+// MockLLM is an in-memory gollmx.LLM that returns scripted replies and
+// records every request:
+//
+//	mock := gollmxtest.Register("mock")
+//	mock.QueueError(gollmx.NewAPIError(gollmx.ErrorTypeRateLimit, "mock", "slow down"))
+//	mock.QueueText("Hello!")
+//
+//	client, _ := gollmx.New("mock")
+//	client = gollmx.WithRetry(client)
+//	resp, _ := client.Chat(ctx, req) // retried, returns "Hello!"
+//
+//	if mock.CallCount() != 2 { ... }
+package gollmxtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// ErrNoReply is returned when a MockLLM is called with an empty reply
+// queue and no Handler
+var ErrNoReply = errors.New("gollmxtest: no reply queued")
+
+// Reply is a scripted reply to a single call. Exactly one of Chat, Stream,
+// Embed and Err is normally set. Chat replies are streamed as a single chunk
+// by ChatStream, and Stream replies are collected into a response by Chat
+// and Complete.
+type Reply struct {
+	Chat   *gollmx.ChatResponse
+	Stream []gollmx.StreamChunk
+	Embed  *gollmx.EmbedResponse
+	Err    error
+
+	// Delay postpones the reply. Streamed replies wait Delay before each chunk.
+	Delay time.Duration
+}
+
+// response returns the reply as a chat response
+func (r Reply) response() (*gollmx.ChatResponse, bool, error) {
+	switch {
+	case r.Chat != nil:
+		resp := *r.Chat
+		return &resp, true, nil
+	case r.Stream != nil:
+		resp, err := gollmx.NewStreamReader(chunkChannel(r.Stream)).Collect()
+		return resp, true, err
+	}
+	return nil, false, nil
+}
+
+// chunks returns the reply as stream chunks
+func (r Reply) chunks() ([]gollmx.StreamChunk, bool) {
+	switch {
+	case r.Stream != nil:
+		return r.Stream, true
+	case r.Chat != nil:
+		return chunksFromResponse(r.Chat), true
+	}
+	return nil, false
+}
+
+// Call is a recorded request to a MockLLM
+type Call struct {
+	Method     string // "Chat", "ChatStream", "Complete" or "Embed"
+	Chat       *gollmx.ChatRequest
+	Completion *gollmx.CompletionRequest
+	Embed      *gollmx.EmbedRequest
+	Time       time.Time
+}
+
+// MockLLM is a scriptable gollmx.LLM for unit tests. It is safe for
+// concurrent use.
+type MockLLM struct {
+	mu       sync.Mutex
+	id       string
+	models   []gollmx.Model
+	features []gollmx.Feature
+	options  map[string]interface{}
+	replies  []Reply
+	calls    []Call
+	failures int
+	failErr  error
+
+	// Config holds the options passed through the registered factory
+	Config *gollmx.Config
+
+	// Latency delays every call, returning early if the context is done
+	Latency time.Duration
+
+	// Handler produces replies when the queue is empty
+	Handler func(call Call) Reply
+}
+
+// NewMockLLM creates a MockLLM with the given provider ID that supports
+// every feature and lists a single model, "mock-model"
+func NewMockLLM(id string) *MockLLM {
+	return &MockLLM{
+		id: id,
+		models: []gollmx.Model{{
+			ID:            "mock-model",
+			Name:          "Mock Model",
+			Provider:      id,
+			ContextWindow: 128000,
+			MaxOutput:     4096,
+			Features:      allFeatures(),
+		}},
+		features: allFeatures(),
+		options:  make(map[string]interface{}),
+		Config:   gollmx.DefaultConfig(),
+	}
+}
+
+// Register creates a MockLLM and registers it under id, so gollmx.New(id)
+// returns it
+func Register(id string) *MockLLM {
+	m := NewMockLLM(id)
+	gollmx.Register(id, m.Factory())
+	return m
+}
+
+// Factory returns a provider factory that records the applied options in
+// Config and returns m
+func (m *MockLLM) Factory() gollmx.ProviderFactory {
+	return func(opts ...gollmx.Option) (gollmx.LLM, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.Config = gollmx.DefaultConfig()
+		m.Config.Apply(opts...)
+		return m, nil
+	}
+}
+
+func allFeatures() []gollmx.Feature {
+	return []gollmx.Feature{
+		gollmx.FeatureChat,
+		gollmx.FeatureCompletion,
+		gollmx.FeatureEmbedding,
+		gollmx.FeatureStreaming,
+		gollmx.FeatureVision,
+		gollmx.FeatureTools,
+		gollmx.FeatureJSON,
+		gollmx.FeatureSystemPrompt,
+	}
+}
+
+// =============================================================================
+// Scripting
+// =============================================================================
+
+// Queue appends replies to the reply queue
+func (m *MockLLM) Queue(replies ...Reply) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies = append(m.replies, replies...)
+	return m
+}
+
+// QueueChat queues a chat response
+func (m *MockLLM) QueueChat(resp *gollmx.ChatResponse) *MockLLM {
+	return m.Queue(Reply{Chat: resp})
+}
+
+// QueueText queues a chat response with the given assistant text
+func (m *MockLLM) QueueText(text string) *MockLLM {
+	return m.QueueChat(TextResponse(text))
+}
+
+// QueueToolCalls queues a chat response requesting the given tool calls
+func (m *MockLLM) QueueToolCalls(calls ...gollmx.ToolCall) *MockLLM {
+	return m.QueueChat(ToolCallResponse(calls...))
+}
+
+// QueueStream queues a streamed reply. Chunks with Error set end the stream
+// with that error.
+func (m *MockLLM) QueueStream(chunks ...gollmx.StreamChunk) *MockLLM {
+	return m.Queue(Reply{Stream: chunks})
+}
+
+// QueueStreamText queues a streamed reply that yields each part as a chunk
+func (m *MockLLM) QueueStreamText(parts ...string) *MockLLM {
+	chunks := make([]gollmx.StreamChunk, len(parts))
+	for i, p := range parts {
+		chunks[i] = gollmx.StreamChunk{Content: p}
+	}
+	if len(chunks) > 0 {
+		chunks[len(chunks)-1].FinishReason = "stop"
+	}
+	return m.QueueStream(chunks...)
+}
+
+// QueueEmbed queues an embedding response
+func (m *MockLLM) QueueEmbed(resp *gollmx.EmbedResponse) *MockLLM {
+	return m.Queue(Reply{Embed: resp})
+}
+
+// QueueError queues an error, typically a *gollmx.APIError
+func (m *MockLLM) QueueError(err error) *MockLLM {
+	return m.Queue(Reply{Err: err})
+}
+
+// FailNext makes the next n calls return err without consuming the queue
+func (m *MockLLM) FailNext(n int, err error) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = n
+	m.failErr = err
+	return m
+}
+
+// SetFeatures replaces the supported features
+func (m *MockLLM) SetFeatures(features ...gollmx.Feature) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.features = features
+	return m
+}
+
+// SetModels replaces the listed models
+func (m *MockLLM) SetModels(models ...gollmx.Model) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.models = models
+	return m
+}
+
+// Pending returns the number of queued replies not yet consumed
+func (m *MockLLM) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.replies)
+}
+
+// =============================================================================
+// Assertions
+// =============================================================================
+
+// Calls returns all recorded calls in order
+func (m *MockLLM) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns the number of recorded calls
+func (m *MockLLM) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// LastChatRequest returns the most recent Chat or ChatStream request, or nil
+func (m *MockLLM) LastChatRequest() *gollmx.ChatRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.calls) - 1; i >= 0; i-- {
+		if m.calls[i].Chat != nil {
+			return m.calls[i].Chat
+		}
+	}
+	return nil
+}
+
+// Reset clears recorded calls, queued replies and injected failures
+func (m *MockLLM) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.replies = nil
+	m.failures = 0
+	m.failErr = nil
+}
+
+// =============================================================================
+// gollmx.LLM
+// =============================================================================
+
+// ID returns the provider identifier
+func (m *MockLLM) ID() string { return m.id }
+
+// Name returns the provider name
+func (m *MockLLM) Name() string { return "Mock " + m.id }
+
+// Version returns the client version
+func (m *MockLLM) Version() string { return "1.0.0" }
+
+// BaseURL returns the API base URL
+func (m *MockLLM) BaseURL() string { return "mock://" + m.id }
+
+// Models returns the configured models
+func (m *MockLLM) Models() []gollmx.Model {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]gollmx.Model(nil), m.models...)
+}
+
+// GetModel returns a configured model by ID
+func (m *MockLLM) GetModel(id string) (*gollmx.Model, error) {
+	for _, model := range m.Models() {
+		if model.ID == id {
+			return &model, nil
+		}
+	}
+	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, m.id, fmt.Sprintf("model not found: %s", id))
+}
+
+// Chat returns the next queued reply as a chat response
+func (m *MockLLM) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.ChatResponse, error) {
+	reply, err := m.next(ctx, Call{Method: "Chat", Chat: copyChat(req)})
+	if err != nil {
+		return nil, err
+	}
+	return m.chatResponse(reply, req.Model)
+}
+
+// ChatStream returns the next queued reply as a stream
+func (m *MockLLM) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.StreamReader, error) {
+	reply, err := m.next(ctx, Call{Method: "ChatStream", Chat: copyChat(req)})
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, ok := reply.chunks()
+	if !ok {
+		return nil, m.mismatch("ChatStream", reply)
+	}
+
+	// One slot of buffer lets the cancellation error be delivered even if
+	// the consumer has stopped reading
+	ch := make(chan gollmx.StreamChunk, 1)
+	go func() {
+		defer close(ch)
+		for _, chunk := range chunks {
+			if chunk.Provider == "" {
+				chunk.Provider = m.id
+			}
+			if chunk.Model == "" {
+				chunk.Model = req.Model
+			}
+			err := sleep(ctx, reply.Delay)
+			if err == nil {
+				select {
+				case ch <- chunk:
+					continue
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+			select {
+			case ch <- gollmx.StreamChunk{Error: err}:
+			default:
+			}
+			return
+		}
+	}()
+
+	return gollmx.NewStreamReader(ch), nil
+}
+
+// Complete returns the next queued reply as a completion response
+func (m *MockLLM) Complete(ctx context.Context, req *gollmx.CompletionRequest) (*gollmx.CompletionResponse, error) {
+	r := *req
+	reply, err := m.next(ctx, Call{Method: "Complete", Completion: &r})
+	if err != nil {
+		return nil, err
+	}
+
+	chatResp, err := m.chatResponse(reply, req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	var finishReason string
+	if len(chatResp.Choices) > 0 {
+		finishReason = chatResp.Choices[0].FinishReason
+	}
+
+	return &gollmx.CompletionResponse{
+		ID:       chatResp.ID,
+		Provider: chatResp.Provider,
+		Model:    chatResp.Model,
+		Created:  chatResp.Created,
+		Choices: []gollmx.CompletionChoice{{
+			Index:        0,
+			Text:         chatResp.GetContent(),
+			FinishReason: finishReason,
+		}},
+		Usage: chatResp.Usage,
+	}, nil
+}
+
+// Embed returns the next queued embedding response
+func (m *MockLLM) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.EmbedResponse, error) {
+	r := *req
+	r.Input = append([]string(nil), req.Input...)
+	reply, err := m.next(ctx, Call{Method: "Embed", Embed: &r})
+	if err != nil {
+		return nil, err
+	}
+	if reply.Embed == nil {
+		return nil, m.mismatch("Embed", reply)
+	}
+
+	resp := *reply.Embed
+	if resp.Provider == "" {
+		resp.Provider = m.id
+	}
+	if resp.Model == "" {
+		resp.Model = req.Model
+	}
+	return &resp, nil
+}
+
+// HasFeature checks if a feature is supported
+func (m *MockLLM) HasFeature(feature gollmx.Feature) bool {
+	for _, f := range m.Features() {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Features returns all supported features
+func (m *MockLLM) Features() []gollmx.Feature {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]gollmx.Feature(nil), m.features...)
+}
+
+// SetOption sets a provider-specific option
+func (m *MockLLM) SetOption(key string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.options[key] = value
+	return nil
+}
+
+// GetOption gets a provider-specific option
+func (m *MockLLM) GetOption(key string) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.options[key]
+	return v, ok
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// next records call, applies latency and injected failures, and pops the
+// next reply
+func (m *MockLLM) next(ctx context.Context, call Call) (Reply, error) {
+	call.Time = time.Now()
+
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	latency := m.Latency
+	m.mu.Unlock()
+
+	if err := sleep(ctx, latency); err != nil {
+		return Reply{}, err
+	}
+
+	m.mu.Lock()
+	if m.failures > 0 {
+		m.failures--
+		err := m.failErr
+		m.mu.Unlock()
+		return Reply{}, err
+	}
+
+	var reply Reply
+	if len(m.replies) > 0 {
+		reply = m.replies[0]
+		m.replies = m.replies[1:]
+	} else if m.Handler != nil {
+		handler := m.Handler
+		m.mu.Unlock()
+		reply = handler(call)
+		m.mu.Lock()
+	} else {
+		m.mu.Unlock()
+		return Reply{}, ErrNoReply
+	}
+	m.mu.Unlock()
+
+	// Streams apply the delay per chunk
+	if call.Method != "ChatStream" {
+		if err := sleep(ctx, reply.Delay); err != nil {
+			return Reply{}, err
+		}
+	}
+
+	if reply.Err != nil {
+		return Reply{}, reply.Err
+	}
+	return reply, nil
+}
+
+// sleep waits for d, returning early with the context error if ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(d):
+		}
+	}
+	return ctx.Err()
+}
+
+// chatResponse converts a reply into a chat response, filling in defaults
+func (m *MockLLM) chatResponse(reply Reply, model string) (*gollmx.ChatResponse, error) {
+	resp, ok, err := reply.response()
+	if !ok {
+		return nil, m.mismatch("Chat", reply)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Provider == "" {
+		resp.Provider = m.id
+	}
+	if resp.Model == "" {
+		resp.Model = model
+	}
+	return resp, nil
+}
+
+func (m *MockLLM) mismatch(method string, reply Reply) error {
+	return fmt.Errorf("gollmxtest: queued reply %+v cannot be returned from %s", reply, method)
+}
+
+// chunkChannel returns a closed, buffered channel holding chunks
+func chunkChannel(chunks []gollmx.StreamChunk) <-chan gollmx.StreamChunk {
+	ch := make(chan gollmx.StreamChunk, len(chunks))
+	for _, c := range chunks {
+		ch <- c
+	}
+	close(ch)
+	return ch
+}
+
+// chunksFromResponse splits a chat response into a content chunk and a
+// final chunk carrying tool calls, finish reason and usage
+func chunksFromResponse(resp *gollmx.ChatResponse) []gollmx.StreamChunk {
+	final := gollmx.StreamChunk{
+		ID:           resp.ID,
+		Provider:     resp.Provider,
+		Model:        resp.Model,
+		ToolCalls:    resp.GetToolCalls(),
+		Citations:    resp.Citations,
+		Documents:    resp.Documents,
+		FinishReason: "stop",
+		Usage:        resp.Usage,
+	}
+	if len(resp.Choices) > 0 && resp.Choices[0].FinishReason != "" {
+		final.FinishReason = resp.Choices[0].FinishReason
+	}
+
+	var chunks []gollmx.StreamChunk
+	if content := resp.GetContent(); content != "" {
+		chunks = append(chunks, gollmx.StreamChunk{
+			ID:       resp.ID,
+			Provider: resp.Provider,
+			Model:    resp.Model,
+			Content:  content,
+		})
+	}
+	return append(chunks, final)
+}
+
+// copyChat makes a shallow copy of req so later mutation by the caller
+// doesn't change the recorded request
+func copyChat(req *gollmx.ChatRequest) *gollmx.ChatRequest {
+	r := *req
+	r.Messages = append([]gollmx.Message(nil), req.Messages...)
+	return &r
+}
+
+// =============================================================================
+// Response Builders
+// =============================================================================
+
+// TextResponse builds a chat response with the given assistant text
+func TextResponse(text string) *gollmx.ChatResponse {
+	return &gollmx.ChatResponse{
+		ID:      "mock-response",
+		Created: time.Now().Unix(),
+		Choices: []gollmx.Choice{{
+			Index:        0,
+			Message:      gollmx.Message{Role: gollmx.RoleAssistant, Content: text},
+			FinishReason: "stop",
+		}},
+	}
+}
+
+// ToolCallResponse builds a chat response requesting the given tool calls
+func ToolCallResponse(calls ...gollmx.ToolCall) *gollmx.ChatResponse {
+	for i := range calls {
+		if calls[i].ID == "" {
+			calls[i].ID = fmt.Sprintf("call_%d", i)
+		}
+		if calls[i].Type == "" {
+			calls[i].Type = "function"
+		}
+	}
+	return &gollmx.ChatResponse{
+		ID:      "mock-response",
+		Created: time.Now().Unix(),
+		Choices: []gollmx.Choice{{
+			Index:        0,
+			Message:      gollmx.Message{Role: gollmx.RoleAssistant, Content: "", ToolCalls: calls},
+			FinishReason: "tool_calls",
+		}},
+	}
+}
+
+// ToolCall builds a function tool call with JSON arguments
+func ToolCall(name, arguments string) gollmx.ToolCall {
+	return gollmx.ToolCall{
+		Type:     "function",
+		Function: gollmx.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+// EmbedResponse builds an embedding response from vectors
+func EmbedResponse(vectors ...[]float64) *gollmx.EmbedResponse {
+	resp := &gollmx.EmbedResponse{}
+	for i, v := range vectors {
+		resp.Embeddings = append(resp.Embeddings, gollmx.Embedding{Index: i, Vector: v})
+	}
+	return resp
+}
+
+// =============================================================================
+// Middleware Checks
+// =============================================================================
+
+// CheckStreamCancel checks that the middleware built by wrap releases the
+// wrapped provider's stream when a caller cancels mid-stream. The provider
+// sends its chunks without watching the context, like a provider goroutine
+// blocked on a send, so it only finishes if the middleware drains it. The
+// middleware's own stream must then close.
+func CheckStreamCancel(t testing.TB, wrap func(gollmx.LLM) gollmx.LLM, req *gollmx.ChatRequest) {
+	t.Helper()
+	provider := blockingStreamLLM{MockLLM: NewMockLLM("mock"), done: make(chan struct{})}
+	client := wrap(provider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.ChatStream(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := stream.Next(); !ok {
+		t.Fatal("expected a chunk before cancelling")
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	select {
+	case <-provider.done:
+	case <-timeout:
+		t.Fatal("expected the provider stream to be drained after cancelling")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		stream.Drain()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-timeout:
+		t.Fatal("expected the stream to close after cancelling")
+	}
+}
+
+// blockingStreamLLM streams chunks on an unbuffered channel without watching
+// the context. done is closed once every chunk has been sent.
+type blockingStreamLLM struct {
+	*MockLLM
+	done chan struct{}
+}
+
+func (m blockingStreamLLM) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.StreamReader, error) {
+	ch := make(chan gollmx.StreamChunk)
+	go func() {
+		defer close(m.done)
+		defer close(ch)
+		for i := 0; i < 10; i++ {
+			ch <- gollmx.StreamChunk{Content: "token"}
+		}
+	}()
+	return gollmx.NewStreamReader(ch), nil
+}
+
+// Ensure MockLLM implements LLM interface
+var _ gollmx.LLM = (*MockLLM)(nil)
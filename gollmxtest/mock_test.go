@@ -0,0 +1,262 @@
+package gollmxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func chatRequest(text string) *gollmx.ChatRequest {
+	return &gollmx.ChatRequest{
+		Model:    "mock-model",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: text}},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	mock := Register("gollmxtest-register")
+	mock.QueueText("hi")
+
+	client, err := gollmx.New("gollmxtest-register", gollmx.WithAPIKey("test-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if mock.Config.APIKey != "test-key" {
+		t.Errorf("expected API key 'test-key', got '%s'", mock.Config.APIKey)
+	}
+
+	resp, err := client.Chat(context.Background(), chatRequest("hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "hi" {
+		t.Errorf("expected 'hi', got '%s'", resp.GetContent())
+	}
+	if resp.Provider != "gollmxtest-register" {
+		t.Errorf("expected provider 'gollmxtest-register', got '%s'", resp.Provider)
+	}
+}
+
+func TestQueueOrderAndRecording(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueText("first").QueueText("second")
+
+	ctx := context.Background()
+	req := chatRequest("one")
+	resp1, _ := mock.Chat(ctx, req)
+	req.Messages[0].Content = "mutated"
+	resp2, _ := mock.Chat(ctx, chatRequest("two"))
+
+	if resp1.GetContent() != "first" || resp2.GetContent() != "second" {
+		t.Errorf("expected replies in order, got '%s', '%s'", resp1.GetContent(), resp2.GetContent())
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[0].Chat.Messages[0].Content != "one" {
+		t.Errorf("expected recorded request to be unaffected by mutation, got '%v'", calls[0].Chat.Messages[0].Content)
+	}
+	if mock.LastChatRequest().Messages[0].Content != "two" {
+		t.Error("expected last chat request to be the second call")
+	}
+
+	if _, err := mock.Chat(ctx, chatRequest("three")); !errors.Is(err, ErrNoReply) {
+		t.Errorf("expected ErrNoReply, got %v", err)
+	}
+}
+
+func TestQueueToolCalls(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueToolCalls(ToolCall("get_weather", `{"city":"Paris"}`))
+
+	resp, err := mock.Chat(context.Background(), chatRequest("weather?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := resp.GetToolCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(calls))
+	}
+	if calls[0].ID != "call_0" || calls[0].Function.Name != "get_weather" {
+		t.Errorf("unexpected tool call: %+v", calls[0])
+	}
+	if resp.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish reason 'tool_calls', got '%s'", resp.Choices[0].FinishReason)
+	}
+}
+
+func TestChatStream(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueStreamText("Hel", "lo")
+	mock.QueueText("whole")
+
+	ctx := context.Background()
+	stream, err := mock.ChatStream(ctx, chatRequest("hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "Hello" {
+		t.Errorf("expected 'Hello', got '%s'", resp.GetContent())
+	}
+
+	// Chat replies are streamed too
+	stream, err = mock.ChatStream(ctx, chatRequest("hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err = stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "whole" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected streamed response: %+v", resp)
+	}
+}
+
+func TestChatStreamError(t *testing.T) {
+	mock := NewMockLLM("mock")
+	streamErr := gollmx.NewAPIError(gollmx.ErrorTypeServer, "mock", "stream broke")
+	mock.QueueStream(gollmx.StreamChunk{Content: "partial"}, gollmx.StreamChunk{Error: streamErr})
+
+	stream, err := mock.ChatStream(context.Background(), chatRequest("hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Collect(); err != streamErr {
+		t.Errorf("expected stream error, got %v", err)
+	}
+}
+
+func TestCompleteAndEmbed(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueText("completed")
+	mock.QueueEmbed(EmbedResponse([]float64{1, 0}, []float64{0, 1}))
+
+	ctx := context.Background()
+	comp, err := mock.Complete(ctx, &gollmx.CompletionRequest{Model: "mock-model", Prompt: "go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comp.GetText() != "completed" {
+		t.Errorf("expected 'completed', got '%s'", comp.GetText())
+	}
+
+	emb, err := mock.Embed(ctx, &gollmx.EmbedRequest{Model: "mock-embed", Input: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(emb.Embeddings) != 2 || emb.Model != "mock-embed" {
+		t.Errorf("unexpected embed response: %+v", emb)
+	}
+}
+
+func TestReplyMismatch(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueText("not an embedding")
+
+	if _, err := mock.Embed(context.Background(), &gollmx.EmbedRequest{Input: []string{"a"}}); err == nil {
+		t.Error("expected error when a chat reply is consumed by Embed")
+	}
+}
+
+func TestRetryWithQueuedError(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.QueueError(gollmx.NewAPIError(gollmx.ErrorTypeRateLimit, "mock", "slow down"))
+	mock.QueueText("ok")
+
+	client := gollmx.WithRetry(mock,
+		gollmx.WithRetryInitialDelay(time.Millisecond),
+		gollmx.WithRetryJitter(0),
+	)
+
+	resp, err := client.Chat(context.Background(), chatRequest("hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "ok" {
+		t.Errorf("expected 'ok', got '%s'", resp.GetContent())
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("expected 2 calls, got %d", mock.CallCount())
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	mock := NewMockLLM("mock")
+	failure := gollmx.NewAPIError(gollmx.ErrorTypeServer, "mock", "down")
+	mock.FailNext(2, failure).QueueText("ok")
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := mock.Chat(ctx, chatRequest("hi")); err != failure {
+			t.Errorf("call %d: expected injected failure, got %v", i, err)
+		}
+	}
+	if mock.Pending() != 1 {
+		t.Errorf("expected injected failures not to consume the queue, pending %d", mock.Pending())
+	}
+	if _, err := mock.Chat(ctx, chatRequest("hi")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.Latency = time.Second
+	mock.QueueText("late")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := mock.Chat(ctx, chatRequest("hi")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if mock.Pending() != 1 {
+		t.Error("expected cancelled call not to consume the queue")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	mock := NewMockLLM("mock")
+	mock.Handler = func(call Call) Reply {
+		return Reply{Chat: TextResponse("echo: " + call.Chat.Messages[0].Content.(string))}
+	}
+
+	resp, err := mock.Chat(context.Background(), chatRequest("ping"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "echo: ping" {
+		t.Errorf("expected 'echo: ping', got '%s'", resp.GetContent())
+	}
+}
+
+func TestFeaturesAndModels(t *testing.T) {
+	mock := NewMockLLM("mock")
+	if !mock.HasFeature(gollmx.FeatureTools) {
+		t.Error("expected tools to be supported by default")
+	}
+
+	mock.SetFeatures(gollmx.FeatureChat)
+	if mock.HasFeature(gollmx.FeatureTools) {
+		t.Error("expected tools to be unsupported after SetFeatures")
+	}
+
+	if _, err := mock.GetModel("mock-model"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := mock.GetModel("missing"); err == nil {
+		t.Error("expected error for unknown model")
+	}
+}
@@ -0,0 +1,59 @@
+// Package vecmath implements the vector math shared by gollmx and its
+// vector package. Callers check that vector lengths match.
+package vecmath
+
+import "math"
+
+// Float is the element type of vectors
+type Float interface {
+	~float32 | ~float64
+}
+
+// Dot returns the dot product of a and b
+func Dot[T Float](a, b []T) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// Norm returns the Euclidean length of v
+func Norm[T Float](v []T) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if either is a zero
+// vector
+func Cosine[T Float](a, b []T) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Normalize returns a copy of v scaled to unit length and whether v has a
+// length. A zero vector is copied unchanged.
+func Normalize[T Float](v []T) ([]T, bool) {
+	out := make([]T, len(v))
+	norm := Norm(v)
+	if norm == 0 {
+		copy(out, v)
+		return out, false
+	}
+	for i, x := range v {
+		out[i] = T(float64(x) / norm)
+	}
+	return out, true
+}
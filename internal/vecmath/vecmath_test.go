@@ -0,0 +1,31 @@
+package vecmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosine(t *testing.T) {
+	if got := Cosine([]float64{1, 0}, []float64{1, 1}); math.Abs(got-math.Sqrt2/2) > 1e-9 {
+		t.Errorf("expected %v, got %v", math.Sqrt2/2, got)
+	}
+	if got := Cosine([]float64{1, 0}, []float64{0, 1}); got != 0 {
+		t.Errorf("expected 0 for orthogonal vectors, got %v", got)
+	}
+	if got := Cosine([]float32{0, 0}, []float32{1, 1}); got != 0 {
+		t.Errorf("expected 0 for a zero vector, got %v", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v, ok := Normalize([]float64{3, 4})
+	if !ok || v[0] != 0.6 || v[1] != 0.8 {
+		t.Errorf("expected [0.6 0.8], got %v", v)
+	}
+	if _, ok := Normalize([]float64{0, 0}); ok {
+		t.Error("expected a zero vector to have no direction")
+	}
+	if got := Dot(v, v); math.Abs(got-1) > 1e-9 {
+		t.Errorf("expected unit length, got %v", got)
+	}
+}
@@ -63,6 +63,7 @@ const (
 	FeatureTools        Feature = "tools"        // Function calling
 	FeatureJSON         Feature = "json_mode"    // Structured JSON output
 	FeatureSystemPrompt Feature = "system_prompt"
+	FeatureRerank       Feature = "rerank"       // Document reranking (see Reranker)
 )
 
 // ProviderFactory is a function that creates a new LLM instance
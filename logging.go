@@ -0,0 +1,449 @@
+package gollmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces secrets in log output
+const redacted = "[REDACTED]"
+
+// maxLoggedBody is the largest request or response body dumped at debug level
+const maxLoggedBody = 64 << 10
+
+// maxBufferedBody is the largest response body read ahead for logging.
+// Larger bodies, e.g. media downloads, are passed through as they are read.
+const maxBufferedBody = 1 << 20
+
+// =============================================================================
+// Logging Transport
+// =============================================================================
+
+// LoggingTransport is an http.RoundTripper that logs every provider request.
+// Each request produces one record when its response is complete, with the
+// provider, model, status, latency and token usage. Streaming responses are
+// logged when the stream ends. At debug level the request and response
+// bodies are dumped with API keys, key query parameters and RedactFields
+// removed.
+type LoggingTransport struct {
+	// Base is the underlying transport; http.DefaultTransport if nil
+	Base http.RoundTripper
+
+	// Logger receives the log records
+	Logger *slog.Logger
+
+	// Provider is the provider ID attached to every record
+	Provider string
+
+	// APIKey is replaced wherever it appears in dumped bodies
+	APIKey string
+
+	// RedactFields are JSON body fields whose values are replaced in dumps
+	RedactFields []string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	debug := t.Logger.Enabled(ctx, slog.LevelDebug)
+
+	reqBody := requestBody(req)
+	attrs := []slog.Attr{
+		slog.String("provider", t.Provider),
+		slog.String("model", requestModel(req, reqBody)),
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+	}
+
+	if debug {
+		t.Logger.LogAttrs(ctx, slog.LevelDebug, "llm request",
+			append(attrs,
+				slog.Any("headers", redactHeaders(req.Header)),
+				slog.String("body", t.redactBody(reqBody)),
+			)...)
+	}
+
+	start := time.Now()
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		t.Logger.LogAttrs(ctx, slog.LevelError, "llm request failed",
+			append(attrs,
+				slog.Duration("latency", time.Since(start)),
+				slog.String("error", err.Error()),
+			)...)
+		return nil, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if isStreamResponse(resp) {
+		resp.Body = &loggingBody{
+			ReadCloser: resp.Body,
+			transport:  t,
+			ctx:        ctx,
+			attrs:      append(attrs, slog.Bool("stream", true), slog.Duration("ttfb", time.Since(start))),
+			status:     resp.StatusCode,
+			start:      start,
+		}
+		return resp, nil
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxBufferedBody+1))
+	if readErr == nil && len(body) > maxBufferedBody {
+		// Too large to buffer: the caller reads the rest, and the response
+		// is logged once it has been read or closed
+		resp.Body = &loggingBody{
+			ReadCloser: readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body},
+			transport:  t,
+			ctx:        ctx,
+			attrs:      attrs,
+			status:     resp.StatusCode,
+			start:      start,
+		}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		t.Logger.LogAttrs(ctx, slog.LevelError, "llm request failed",
+			append(attrs,
+				slog.Duration("latency", time.Since(start)),
+				slog.String("error", readErr.Error()),
+			)...)
+		return resp, nil
+	}
+
+	var usage wireUsage
+	usage.scan(body)
+	t.logResponse(ctx, attrs, resp.StatusCode, time.Since(start), usage, body, debug)
+	return resp, nil
+}
+
+func (t *LoggingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// logResponse emits the record for a completed response
+func (t *LoggingTransport) logResponse(ctx context.Context, attrs []slog.Attr, status int, latency time.Duration, usage wireUsage, body []byte, debug bool) {
+	attrs = append(attrs, slog.Duration("latency", latency))
+	if u := usage.usage(); u != (Usage{}) {
+		attrs = append(attrs, slog.Group("usage",
+			slog.Int("prompt_tokens", u.PromptTokens),
+			slog.Int("completion_tokens", u.CompletionTokens),
+			slog.Int("total_tokens", u.TotalTokens),
+		))
+	}
+
+	level := slog.LevelInfo
+	if status >= 400 {
+		level = slog.LevelWarn
+	}
+	// Error bodies are small and explain the failure, so they are always logged
+	if body != nil && (debug || status >= 400) {
+		attrs = append(attrs, slog.String("body", t.redactBody(body)))
+	}
+
+	t.Logger.LogAttrs(ctx, level, "llm response", attrs...)
+}
+
+// redactBody removes secrets from a body and truncates it for logging
+func (t *LoggingTransport) redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if len(t.RedactFields) > 0 {
+		var v interface{}
+		if json.Unmarshal(body, &v) == nil {
+			redactFields(v, t.RedactFields)
+			if data, err := json.Marshal(v); err == nil {
+				body = data
+			}
+		}
+	}
+
+	s := string(body)
+	if t.APIKey != "" {
+		s = strings.ReplaceAll(s, t.APIKey, redacted)
+	}
+	if len(s) > maxLoggedBody {
+		s = s[:maxLoggedBody] + "...(truncated)"
+	}
+	return s
+}
+
+// loggingBody scans a streaming or unbuffered response for usage and logs it
+// once the body has been read to the end or closed
+type loggingBody struct {
+	io.ReadCloser
+	transport *LoggingTransport
+	ctx       context.Context
+	attrs     []slog.Attr
+	status    int
+	start     time.Time
+
+	partial []byte
+	usage   wireUsage
+	once    sync.Once
+}
+
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.scan(p[:n])
+	if err != nil {
+		if err != io.EOF {
+			b.attrs = append(b.attrs, slog.String("error", err.Error()))
+		}
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *loggingBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+// scan feeds complete lines of SSE or NDJSON data to the usage decoder
+func (b *loggingBody) scan(data []byte) {
+	b.partial = append(b.partial, data...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			// Lines too long to hold usage, e.g. binary data, are dropped
+			if len(b.partial) > maxBufferedBody {
+				b.partial = b.partial[:0]
+			}
+			return
+		}
+		line := bytes.TrimSpace(b.partial[:i])
+		b.partial = b.partial[i+1:]
+
+		line = bytes.TrimPrefix(line, []byte("data:"))
+		b.usage.scan(bytes.TrimSpace(line))
+	}
+}
+
+func (b *loggingBody) finish() {
+	b.once.Do(func() {
+		b.transport.logResponse(b.ctx, b.attrs, b.status, time.Since(b.start), b.usage, nil, false)
+	})
+}
+
+// readCloser reads from one source and closes another
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// =============================================================================
+// Usage Decoding
+// =============================================================================
+
+// wireUsage accumulates token counts from any provider's response format
+type wireUsage struct {
+	prompt, completion, total int
+}
+
+// wireUsageCounts covers the token count fields used by the providers
+type wireUsageCounts struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	InputTokens      int `json:"input_tokens"`
+	OutputTokens     int `json:"output_tokens"`
+
+	Tokens      *wireUsageCounts `json:"tokens"`
+	BilledUnits *wireUsageCounts `json:"billed_units"`
+}
+
+type wireUsageBody struct {
+	Usage   *wireUsageCounts `json:"usage"`
+	Meta    *wireUsageCounts `json:"meta"`
+	Message *struct {
+		Usage *wireUsageCounts `json:"usage"`
+	} `json:"message"`
+	Delta *struct {
+		Usage *wireUsageCounts `json:"usage"`
+	} `json:"delta"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// scan merges the token counts found in a JSON document. Later non-zero
+// counts replace earlier ones, since streams report usage incrementally.
+func (u *wireUsage) scan(data []byte) {
+	if len(data) == 0 || data[0] != '{' {
+		return
+	}
+	// Fields of an unexpected type are skipped rather than failing the decode
+	var body wireUsageBody
+	json.Unmarshal(data, &body)
+
+	u.merge(body.Usage)
+	u.merge(body.Meta)
+	if body.Message != nil {
+		u.merge(body.Message.Usage)
+	}
+	if body.Delta != nil {
+		u.merge(body.Delta.Usage)
+	}
+	if m := body.UsageMetadata; m != nil {
+		u.set(m.PromptTokenCount, m.CandidatesTokenCount, m.TotalTokenCount)
+	}
+	u.set(body.PromptEvalCount, body.EvalCount, 0)
+}
+
+func (u *wireUsage) merge(c *wireUsageCounts) {
+	if c == nil {
+		return
+	}
+	if c.Tokens != nil {
+		u.merge(c.Tokens)
+		return
+	}
+	if c.BilledUnits != nil {
+		u.merge(c.BilledUnits)
+		return
+	}
+	u.set(c.PromptTokens+c.InputTokens, c.CompletionTokens+c.OutputTokens, c.TotalTokens)
+}
+
+func (u *wireUsage) set(prompt, completion, total int) {
+	if prompt > 0 {
+		u.prompt = prompt
+	}
+	if completion > 0 {
+		u.completion = completion
+	}
+	if total > 0 {
+		u.total = total
+	}
+}
+
+func (u wireUsage) usage() Usage {
+	total := u.total
+	if total < u.prompt+u.completion {
+		total = u.prompt + u.completion
+	}
+	return Usage{PromptTokens: u.prompt, CompletionTokens: u.completion, TotalTokens: total}
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// requestBody returns a copy of the request body without consuming it
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			defer rc.Close()
+			body, _ := io.ReadAll(rc)
+			return body
+		}
+	}
+	body, _ := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body
+}
+
+// requestModel finds the model in a JSON request body, or in Gemini-style
+// /models/{model}:{method} paths
+func requestModel(req *http.Request, body []byte) string {
+	var v struct {
+		Model string `json:"model"`
+	}
+	if json.Unmarshal(body, &v) == nil && v.Model != "" {
+		return v.Model
+	}
+	if _, rest, ok := strings.Cut(req.URL.Path, "/models/"); ok {
+		model, _, _ := strings.Cut(rest, ":")
+		return model
+	}
+	return ""
+}
+
+func isStreamResponse(resp *http.Response) bool {
+	contentType := resp.Header.Get("Content-Type")
+	return strings.HasPrefix(contentType, "text/event-stream") ||
+		strings.HasPrefix(contentType, "application/x-ndjson")
+}
+
+// redactURL returns the URL with API key query parameters removed
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, param := range DefaultRedactQueryParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+// redactHeaders returns the headers with credentials removed
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range DefaultRedactHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// redactFields replaces the values of matching object keys at any depth
+func redactFields(v interface{}, fields []string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			matched := false
+			for _, field := range fields {
+				if strings.EqualFold(key, field) {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				v[key] = redacted
+			} else {
+				redactFields(value, fields)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactFields(item, fields)
+		}
+	}
+}
+
+// debugLogger is the logger used when Debug is set without a Logger
+func debugLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
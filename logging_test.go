@@ -0,0 +1,337 @@
+package gollmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level})), &buf
+}
+
+// logRecords decodes the JSON log records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	cfg := DefaultConfig()
+	cfg.Apply(WithAPIKey("sk-secret"), WithLogger(logger))
+
+	resp, err := cfg.HTTPClientFor("openai").Post(server.URL+"/v1/chat/completions", "application/json",
+		strings.NewReader(`{"model":"gpt-4o","messages":[]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"id":"1"`) {
+		t.Errorf("expected the response body to be readable, got %q", body)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record at info level, got %d", len(records))
+	}
+	record := records[0]
+	if record["provider"] != "openai" {
+		t.Errorf("expected provider 'openai', got %v", record["provider"])
+	}
+	if record["model"] != "gpt-4o" {
+		t.Errorf("expected model 'gpt-4o', got %v", record["model"])
+	}
+	if record["status"] != float64(200) {
+		t.Errorf("expected status 200, got %v", record["status"])
+	}
+	if _, ok := record["latency"]; !ok {
+		t.Error("expected latency to be logged")
+	}
+	usage, _ := record["usage"].(map[string]interface{})
+	if usage["prompt_tokens"] != float64(10) || usage["completion_tokens"] != float64(5) || usage["total_tokens"] != float64(15) {
+		t.Errorf("expected usage 10/5/15, got %v", record["usage"])
+	}
+	if _, ok := record["body"]; ok {
+		t.Error("expected no body at info level")
+	}
+}
+
+func TestLoggingTransportRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "sk-secret" {
+			t.Errorf("expected the real key to reach the server")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"bad request for sk-secret"}}`)
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	cfg := DefaultConfig()
+	cfg.Apply(WithAPIKey("sk-secret"), WithLogger(logger), WithLogRedactFields("user"))
+
+	req, _ := http.NewRequest("POST", server.URL+"/v1beta/models/gemini-1.5-flash:generateContent?key=sk-secret",
+		strings.NewReader(`{"contents":[],"user":"alice@example.com"}`))
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	resp, err := cfg.HTTPClientFor("google").Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	out := buf.String()
+	for _, secret := range []string{"sk-secret", "alice@example.com"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected %q to be redacted from logs: %s", secret, out)
+		}
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("expected request and response records, got %d", len(records))
+	}
+	response := records[1]
+	if response["level"] != "WARN" {
+		t.Errorf("expected a 400 to be logged at WARN, got %v", response["level"])
+	}
+	if response["model"] != "gemini-1.5-flash" {
+		t.Errorf("expected model from the URL path, got %v", response["model"])
+	}
+	if !strings.Contains(response["url"].(string), "key=%5BREDACTED%5D") {
+		t.Errorf("expected key query param to be redacted, got %v", response["url"])
+	}
+	if !strings.Contains(response["body"].(string), "bad request") {
+		t.Errorf("expected error body to be logged, got %v", response["body"])
+	}
+}
+
+func TestLoggingTransportStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":12,\"output_tokens\":1}}}\n\n")
+		fmt.Fprint(w, "event: message_delta\ndata: {\"type\":\"message_delta\",\"usage\":{\"output_tokens\":8}}\n\n")
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	cfg := DefaultConfig()
+	cfg.Apply(WithAPIKey("sk-secret"), WithLogger(logger))
+
+	resp, err := cfg.HTTPClientFor("anthropic").Post(server.URL+"/v1/messages", "application/json",
+		strings.NewReader(`{"model":"claude-3-5-haiku-latest","stream":true}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("expected streams to be logged when they end")
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0]["stream"] != true {
+		t.Errorf("expected stream=true, got %v", records[0]["stream"])
+	}
+	usage, _ := records[0]["usage"].(map[string]interface{})
+	if usage["prompt_tokens"] != float64(12) || usage["completion_tokens"] != float64(8) || usage["total_tokens"] != float64(20) {
+		t.Errorf("expected usage 12/8/20, got %v", records[0]["usage"])
+	}
+}
+
+func TestLoggingTransportLargeBody(t *testing.T) {
+	maxBytes := DefaultMediaResolverConfig().MaxBytes
+	body := &largeBody{size: 2 * maxBytes}
+	transport := largeBodyTransport{body: body}
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	cfg := DefaultConfig()
+	cfg.Apply(WithAPIKey("sk-secret"), WithLogger(logger), WithHTTPClient(&http.Client{Transport: transport}))
+
+	// The logged download must stop at the resolver's size limit
+	resolver := cfg.MediaResolverFor("google")
+	if _, err := resolver.Resolve(context.Background(), "https://example.com/huge.png"); err == nil {
+		t.Fatal("expected error for oversized media")
+	}
+
+	if body.read > maxBytes+maxBufferedBody {
+		t.Errorf("expected download to stop near %d bytes, read %d", maxBytes, body.read)
+	}
+	if !body.closed {
+		t.Error("expected the response body to be closed")
+	}
+	records := logRecords(t, buf)
+	if len(records) != 2 || records[1]["msg"] != "llm response" || records[1]["status"] != float64(200) {
+		t.Errorf("expected request and response records, got %v", records)
+	}
+}
+
+func TestHTTPClientForWithoutLogger(t *testing.T) {
+	client := &http.Client{}
+	cfg := DefaultConfig()
+	cfg.Apply(WithHTTPClient(client))
+
+	if cfg.HTTPClientFor("openai") != client {
+		t.Error("expected the configured client when logging is disabled")
+	}
+	if cfg.GetLogger() != nil {
+		t.Error("expected no logger by default")
+	}
+
+	cfg = DefaultConfig()
+	cfg.Apply(WithHTTPClient(client), WithDebug(true))
+	logger := cfg.GetLogger()
+	if logger == nil {
+		t.Error("expected a logger when debug is enabled")
+	}
+	logged := cfg.HTTPClientFor("openai")
+	if logged.Transport == nil {
+		t.Error("expected a logging transport when debug is enabled")
+	}
+
+	// Both are built once and reused for every request
+	if cfg.GetLogger() != logger || cfg.HTTPClientFor("openai") != logged {
+		t.Error("expected the logger and HTTP client to be reused")
+	}
+}
+
+func TestRetryLogger(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+	retryer := NewRetryer(
+		WithRetryMaxRetries(2),
+		WithRetryInitialDelay(1),
+		WithRetryJitter(0),
+		WithRetryLogger(logger),
+	)
+
+	calls := 0
+	err := retryer.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return &APIError{Type: ErrorTypeServer, Provider: "openai", Message: "overloaded", Retryable: true}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 retry records, got %d", len(records))
+	}
+	if records[1]["attempt"] != float64(2) || records[1]["provider"] != "openai" {
+		t.Errorf("unexpected retry record: %v", records[1])
+	}
+}
+
+// configuredLLM fails its first chat and exposes its Config
+type configuredLLM struct {
+	mockLLM
+	config *Config
+	calls  int
+}
+
+func (m *configuredLLM) Config() *Config { return m.config }
+
+func (m *configuredLLM) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	m.calls++
+	if m.calls == 1 {
+		return nil, &APIError{Type: ErrorTypeServer, Provider: m.id, Message: "overloaded", Retryable: true}
+	}
+	return &ChatResponse{}, nil
+}
+
+func TestRetryLoggerFromClientConfig(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+	inner := &configuredLLM{mockLLM: mockLLM{id: "openai"}, config: DefaultConfig()}
+	inner.config.Apply(WithLogger(logger))
+
+	// The config is found through other wrappers
+	client := WithRetry(NewRateLimitedClient(inner, 6000), WithRetryInitialDelay(1), WithRetryJitter(0))
+	if ConfigOf(client) != inner.config {
+		t.Fatal("expected ConfigOf to unwrap to the client config")
+	}
+
+	if _, err := client.Chat(context.Background(), &ChatRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 || records[0]["msg"] != "retrying llm request" {
+		t.Errorf("expected one retry record, got %v", records)
+	}
+
+	if ConfigOf(&mockLLM{}) != nil {
+		t.Error("expected no config for a client without one")
+	}
+}
+
+// largeBody is a zero-filled response body of size bytes that counts reads
+type largeBody struct {
+	size   int64
+	read   int64
+	closed bool
+}
+
+func (b *largeBody) Read(p []byte) (int, error) {
+	if b.read >= b.size {
+		return 0, io.EOF
+	}
+	if remaining := b.size - b.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	clear(p)
+	b.read += int64(len(p))
+	return len(p), nil
+}
+
+func (b *largeBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+// largeBodyTransport answers every request with body
+type largeBodyTransport struct {
+	body *largeBody
+}
+
+func (t largeBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"image/png"}},
+		Body:          t.body,
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
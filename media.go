@@ -0,0 +1,270 @@
+package gollmx
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Media Resolution
+// =============================================================================
+
+// Media is a resolved media reference. Either Data holds base64-encoded
+// bytes, or URI holds a provider-side file reference that should be passed
+// through untouched (e.g. gs:// or Gemini File API URIs).
+type Media struct {
+	MediaType string // MIME type (e.g. "image/png"); may be empty for URI references
+	Data      string // Base64-encoded content
+	URI       string // File reference to pass through instead of inline data
+}
+
+// IsReference reports whether the media is a pass-through file reference
+func (m *Media) IsReference() bool {
+	return m.URI != ""
+}
+
+// MediaResolver converts media URLs into inline data for providers that
+// cannot fetch remote media themselves
+type MediaResolver interface {
+	Resolve(ctx context.Context, url string) (*Media, error)
+}
+
+// MediaResolverConfig holds configuration for the HTTP media resolver
+type MediaResolverConfig struct {
+	HTTPClient   *http.Client  // Client used for downloads (defaults to http.DefaultClient)
+	MaxBytes     int64         // Maximum download size in bytes
+	Timeout      time.Duration // Per-download timeout (0 = rely on the context)
+	PassThrough  []string      // URL prefixes returned as references instead of downloaded
+	AllowedHosts []string      // Hosts media may be downloaded from, including redirects (empty = any host)
+}
+
+// DefaultMediaResolverConfig returns the default media resolver configuration
+func DefaultMediaResolverConfig() *MediaResolverConfig {
+	return &MediaResolverConfig{
+		MaxBytes: 20 << 20, // 20MB, the Gemini inline request limit
+		Timeout:  30 * time.Second,
+		PassThrough: []string{
+			"gs://",
+			"https://generativelanguage.googleapis.com/v1beta/files/",
+		},
+	}
+}
+
+// HTTPMediaResolver resolves data URLs in place and downloads http(s) URLs.
+//
+// By default any host is fetched, including loopback, link-local and
+// private network addresses, since the URLs come from message content.
+// Servers that pass untrusted messages to a provider should set
+// AllowedHosts so users cannot make the server fetch internal URLs.
+type HTTPMediaResolver struct {
+	config *MediaResolverConfig
+}
+
+// NewMediaResolver creates a media resolver with the given configuration
+func NewMediaResolver(config *MediaResolverConfig) *HTTPMediaResolver {
+	if config == nil {
+		config = DefaultMediaResolverConfig()
+	}
+	return &HTTPMediaResolver{config: config}
+}
+
+// Resolve converts url into inline base64 data or a pass-through reference
+func (r *HTTPMediaResolver) Resolve(ctx context.Context, url string) (*Media, error) {
+	if strings.HasPrefix(url, "data:") {
+		mediaType, data, ok := ParseDataURL(url)
+		if !ok {
+			return nil, NewAPIError(ErrorTypeInvalidRequest, "", "media data URL must be base64 encoded")
+		}
+		if mediaType == "" {
+			mediaType = DetectBase64MediaType(data)
+		}
+		return &Media{MediaType: mediaType, Data: data}, nil
+	}
+
+	for _, prefix := range r.config.PassThrough {
+		if strings.HasPrefix(url, prefix) {
+			return &Media{MediaType: mime.TypeByExtension(path.Ext(url)), URI: url}, nil
+		}
+	}
+
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, NewAPIError(ErrorTypeInvalidRequest, "", fmt.Sprintf("unsupported media URL: %s", url))
+	}
+
+	parsed, err := neturl.Parse(url)
+	if err != nil || !r.allowedHost(parsed.Hostname()) {
+		return nil, NewAPIError(ErrorTypeInvalidRequest, "", fmt.Sprintf("media host is not allowed: %s", url))
+	}
+
+	return r.fetch(ctx, url)
+}
+
+// allowedHost reports whether media may be downloaded from host
+func (r *HTTPMediaResolver) allowedHost(host string) bool {
+	if len(r.config.AllowedHosts) == 0 {
+		return true
+	}
+	for _, allowed := range r.config.AllowedHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *HTTPMediaResolver) fetch(ctx context.Context, url string) (*Media, error) {
+	if r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media request: %w", err)
+	}
+
+	client := r.config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(r.config.AllowedHosts) > 0 {
+		// An allowed host must not redirect to one that is not
+		base := client
+		restricted := *client
+		restricted.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if !r.allowedHost(req.URL.Hostname()) {
+				return fmt.Errorf("redirect to %s is not allowed", req.URL.Host)
+			}
+			if base.CheckRedirect != nil {
+				return base.CheckRedirect(req, via)
+			}
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		}
+		client = &restricted
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &APIError{
+			Type:    ErrorTypeNetwork,
+			Message: fmt.Sprintf("failed to fetch media: %v", err),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Type:       ErrorTypeInvalidRequest,
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("failed to fetch media %s: status %d", url, resp.StatusCode),
+		}
+	}
+
+	var reader io.Reader = resp.Body
+	if r.config.MaxBytes > 0 {
+		if resp.ContentLength > r.config.MaxBytes {
+			return nil, r.tooLarge(url)
+		}
+		reader = io.LimitReader(resp.Body, r.config.MaxBytes+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, &APIError{
+			Type:    ErrorTypeNetwork,
+			Message: fmt.Sprintf("failed to read media: %v", err),
+		}
+	}
+	if r.config.MaxBytes > 0 && int64(len(body)) > r.config.MaxBytes {
+		return nil, r.tooLarge(url)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" || mediaType == "application/octet-stream" || mediaType == "binary/octet-stream" {
+		mediaType = DetectMediaType(body)
+	}
+
+	return &Media{
+		MediaType: mediaType,
+		Data:      base64.StdEncoding.EncodeToString(body),
+	}, nil
+}
+
+func (r *HTTPMediaResolver) tooLarge(url string) error {
+	return NewAPIError(ErrorTypeInvalidRequest, "",
+		fmt.Sprintf("media %s exceeds maximum size of %d bytes", url, r.config.MaxBytes))
+}
+
+// Ensure HTTPMediaResolver implements MediaResolver interface
+var _ MediaResolver = (*HTTPMediaResolver)(nil)
+
+// =============================================================================
+// Media Helpers
+// =============================================================================
+
+// ParseDataURL splits a data URL (e.g. "data:image/png;base64,iVBOR...")
+// into its media type and base64 payload. Non-base64 data URLs are not
+// supported and report ok=false.
+func ParseDataURL(url string) (mediaType string, data string, ok bool) {
+	if !strings.HasPrefix(url, "data:") {
+		return "", "", false
+	}
+
+	header, payload, found := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+	if !found {
+		return "", "", false
+	}
+
+	params := strings.Split(header, ";")
+	if params[len(params)-1] != "base64" {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(params[0]), payload, true
+}
+
+// DetectMediaType sniffs the MIME type of raw media bytes.
+// It returns "application/octet-stream" when the type cannot be determined.
+func DetectMediaType(data []byte) string {
+	mediaType := http.DetectContentType(data)
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	return mediaType
+}
+
+// DetectBase64MediaType sniffs the MIME type of base64-encoded media.
+// Only the leading bytes are decoded, which is enough for magic-number detection.
+func DetectBase64MediaType(data string) string {
+	// 512 bytes of content is all http.DetectContentType looks at
+	prefix := data
+	if len(prefix) > 684 {
+		prefix = prefix[:684]
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(prefix)
+	if err != nil {
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(prefix, "="))
+		if err != nil {
+			return "application/octet-stream"
+		}
+	}
+	return DetectMediaType(decoded)
+}
+
+// IsImageMediaType reports whether mediaType is an image MIME type
+func IsImageMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "image/")
+}
@@ -0,0 +1,185 @@
+package gollmx
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pngHeader is the 8-byte PNG signature followed by the start of an IHDR chunk
+var pngHeader = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 13, 'I', 'H', 'D', 'R'}
+
+func TestParseDataURL(t *testing.T) {
+	mediaType, data, ok := ParseDataURL("data:image/png;base64,iVBORw0KGgo=")
+	if !ok {
+		t.Fatal("expected data URL to parse")
+	}
+	if mediaType != "image/png" {
+		t.Errorf("expected media type 'image/png', got '%s'", mediaType)
+	}
+	if data != "iVBORw0KGgo=" {
+		t.Errorf("unexpected data: %s", data)
+	}
+}
+
+func TestParseDataURLInvalid(t *testing.T) {
+	tests := []string{
+		"https://example.com/image.png",
+		"data:image/png,rawdata",
+		"data:image/png;base64",
+	}
+
+	for _, url := range tests {
+		if _, _, ok := ParseDataURL(url); ok {
+			t.Errorf("expected %q not to parse", url)
+		}
+	}
+}
+
+func TestDetectMediaType(t *testing.T) {
+	if got := DetectMediaType(pngHeader); got != "image/png" {
+		t.Errorf("expected 'image/png', got '%s'", got)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+	if got := DetectBase64MediaType(encoded); got != "image/png" {
+		t.Errorf("expected 'image/png' from base64, got '%s'", got)
+	}
+
+	if got := DetectBase64MediaType("not base64!"); got != "application/octet-stream" {
+		t.Errorf("expected fallback media type, got '%s'", got)
+	}
+}
+
+func TestMediaResolverFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No Content-Type: the resolver should sniff the bytes
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(pngHeader)
+	}))
+	defer server.Close()
+
+	resolver := NewMediaResolver(nil)
+	media, err := resolver.Resolve(context.Background(), server.URL+"/image")
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+
+	if media.MediaType != "image/png" {
+		t.Errorf("expected 'image/png', got '%s'", media.MediaType)
+	}
+	if media.Data != base64.StdEncoding.EncodeToString(pngHeader) {
+		t.Error("expected base64-encoded body")
+	}
+	if media.IsReference() {
+		t.Error("fetched media should not be a reference")
+	}
+}
+
+func TestMediaResolverMaxBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 1024))
+	}))
+	defer server.Close()
+
+	config := DefaultMediaResolverConfig()
+	config.MaxBytes = 100
+	resolver := NewMediaResolver(config)
+
+	_, err := resolver.Resolve(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("expected error for oversized media")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Type != ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid request error, got %v", err)
+	}
+}
+
+func TestMediaResolverAllowedHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			// Same server, but under a host that is not allowed
+			_, port, _ := net.SplitHostPort(r.Host)
+			http.Redirect(w, r, "http://localhost:"+port+"/image", http.StatusFound)
+			return
+		}
+		w.Write(pngHeader)
+	}))
+	defer server.Close()
+
+	config := DefaultMediaResolverConfig()
+	config.AllowedHosts = []string{"127.0.0.1"}
+	resolver := NewMediaResolver(config)
+	ctx := context.Background()
+
+	if _, err := resolver.Resolve(ctx, server.URL+"/image"); err != nil {
+		t.Errorf("expected an allowed host to be fetched, got %v", err)
+	}
+	if _, err := resolver.Resolve(ctx, server.URL+"/redirect"); err == nil {
+		t.Error("expected a redirect to another host to fail")
+	}
+
+	config.AllowedHosts = []string{"cdn.example.com"}
+	_, err := resolver.Resolve(ctx, server.URL+"/image")
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Type != ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid request error for a host that is not allowed, got %v", err)
+	}
+}
+
+func TestMediaResolverHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewMediaResolver(nil).Resolve(context.Background(), server.URL)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", apiErr.StatusCode)
+	}
+}
+
+func TestMediaResolverPassThrough(t *testing.T) {
+	resolver := NewMediaResolver(nil)
+
+	tests := []struct {
+		url       string
+		mediaType string
+	}{
+		{"gs://bucket/photo.png", "image/png"},
+		{"https://generativelanguage.googleapis.com/v1beta/files/abc123", ""},
+	}
+
+	for _, tt := range tests {
+		media, err := resolver.Resolve(context.Background(), tt.url)
+		if err != nil {
+			t.Fatalf("resolve %s failed: %v", tt.url, err)
+		}
+		if !media.IsReference() || media.URI != tt.url {
+			t.Errorf("expected %s to pass through, got %+v", tt.url, media)
+		}
+		if media.MediaType != tt.mediaType {
+			t.Errorf("expected media type '%s', got '%s'", tt.mediaType, media.MediaType)
+		}
+	}
+}
+
+func TestMediaResolverDataURL(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+
+	media, err := NewMediaResolver(nil).Resolve(context.Background(), "data:;base64,"+encoded)
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if media.MediaType != "image/png" || media.Data != encoded {
+		t.Errorf("unexpected media: %+v", media)
+	}
+}
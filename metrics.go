@@ -0,0 +1,442 @@
+package gollmx
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operations reported in RequestMetrics
+const (
+	OperationChat     = "chat"
+	OperationComplete = "completion"
+	OperationEmbed    = "embed"
+)
+
+// RequestMetrics describes one completed LLM call
+type RequestMetrics struct {
+	Provider  string
+	Model     string // Requested model, or the response model if none was requested
+	Operation string // OperationChat, OperationComplete or OperationEmbed
+	Stream    bool
+
+	Latency          time.Duration // Time until the response or stream completed
+	TimeToFirstToken time.Duration // Time until the first content chunk (streams only)
+	TokensPerSecond  float64       // Output tokens per second of generation (0 if unknown)
+	Usage            Usage
+
+	Err       error     // Nil on success
+	ErrorType ErrorType // Empty on success
+}
+
+// MetricsRecorder receives metrics for every call made through a MetricsClient
+type MetricsRecorder interface {
+	RecordRequest(ctx context.Context, m RequestMetrics)
+}
+
+// =============================================================================
+// Metrics Client Wrapper
+// =============================================================================
+
+// MetricsClient wraps an LLM client and records metrics for every call
+type MetricsClient struct {
+	client   LLM
+	recorder MetricsRecorder
+}
+
+// WithMetrics wraps an LLM client with metrics recording. A nil recorder
+// uses DefaultMetricsRecorder.
+func WithMetrics(client LLM, recorder MetricsRecorder) *MetricsClient {
+	if recorder == nil {
+		recorder = DefaultMetricsRecorder()
+	}
+	return &MetricsClient{client: client, recorder: recorder}
+}
+
+// ID returns the provider identifier
+func (c *MetricsClient) ID() string {
+	return c.client.ID()
+}
+
+// Name returns the provider name
+func (c *MetricsClient) Name() string {
+	return c.client.Name()
+}
+
+// Version returns the client version
+func (c *MetricsClient) Version() string {
+	return c.client.Version()
+}
+
+// BaseURL returns the API base URL
+func (c *MetricsClient) BaseURL() string {
+	return c.client.BaseURL()
+}
+
+// Models returns available models
+func (c *MetricsClient) Models() []Model {
+	return c.client.Models()
+}
+
+// GetModel returns a specific model
+func (c *MetricsClient) GetModel(id string) (*Model, error) {
+	return c.client.GetModel(id)
+}
+
+// Chat performs a chat completion and records its metrics
+func (c *MetricsClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	start := time.Now()
+	resp, err := c.client.Chat(ctx, req)
+
+	m := c.newMetrics(OperationChat, req.Model, time.Since(start), err)
+	if resp != nil {
+		if m.Model == "" {
+			m.Model = resp.Model
+		}
+		m.Usage = resp.Usage
+		m.TokensPerSecond = tokensPerSecond(resp.Usage, m.Latency)
+	}
+	c.recorder.RecordRequest(ctx, m)
+	return resp, err
+}
+
+// ChatStream performs a streaming chat completion. Metrics are recorded when
+// the stream ends.
+func (c *MetricsClient) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	start := time.Now()
+	stream, err := c.client.ChatStream(ctx, req)
+	if err != nil {
+		m := c.newMetrics(OperationChat, req.Model, time.Since(start), err)
+		m.Stream = true
+		c.recorder.RecordRequest(ctx, m)
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go c.forwardStream(ctx, req.Model, start, stream, ch)
+	return NewStreamReader(ch), nil
+}
+
+// forwardStream copies chunks to ch, timing the first token and recording
+// metrics once the stream ends
+func (c *MetricsClient) forwardStream(ctx context.Context, model string, start time.Time, stream *StreamReader, ch chan<- StreamChunk) {
+	defer close(ch)
+
+	var ttft time.Duration
+	var usage Usage
+	var streamErr error
+
+	for {
+		chunk, ok := stream.Next()
+		if !ok {
+			streamErr = stream.Err()
+			break
+		}
+		if ttft == 0 && (chunk.Content != "" || len(chunk.ToolCalls) > 0) {
+			ttft = time.Since(start)
+		}
+		if model == "" {
+			model = chunk.Model
+		}
+		if chunk.Usage != (Usage{}) {
+			usage = chunk.Usage
+		}
+
+		select {
+		case ch <- *chunk:
+		case <-ctx.Done():
+			streamErr = ctx.Err()
+			stream.Drain()
+		}
+		if streamErr != nil {
+			break
+		}
+	}
+
+	m := c.newMetrics(OperationChat, model, time.Since(start), streamErr)
+	m.Stream = true
+	m.TimeToFirstToken = ttft
+	m.Usage = usage
+	m.TokensPerSecond = tokensPerSecond(usage, m.Latency-ttft)
+	c.recorder.RecordRequest(ctx, m)
+
+	if streamErr != nil && ctx.Err() == nil {
+		ch <- StreamChunk{Error: streamErr}
+	}
+}
+
+// Complete performs a text completion and records its metrics
+func (c *MetricsClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	start := time.Now()
+	resp, err := c.client.Complete(ctx, req)
+
+	m := c.newMetrics(OperationComplete, req.Model, time.Since(start), err)
+	if resp != nil {
+		if m.Model == "" {
+			m.Model = resp.Model
+		}
+		m.Usage = resp.Usage
+		m.TokensPerSecond = tokensPerSecond(resp.Usage, m.Latency)
+	}
+	c.recorder.RecordRequest(ctx, m)
+	return resp, err
+}
+
+// Embed generates embeddings and records their metrics
+func (c *MetricsClient) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	start := time.Now()
+	resp, err := c.client.Embed(ctx, req)
+
+	m := c.newMetrics(OperationEmbed, req.Model, time.Since(start), err)
+	if resp != nil {
+		if m.Model == "" {
+			m.Model = resp.Model
+		}
+		m.Usage = resp.Usage
+	}
+	c.recorder.RecordRequest(ctx, m)
+	return resp, err
+}
+
+// HasFeature checks if a feature is supported
+func (c *MetricsClient) HasFeature(feature Feature) bool {
+	return c.client.HasFeature(feature)
+}
+
+// Features returns all supported features
+func (c *MetricsClient) Features() []Feature {
+	return c.client.Features()
+}
+
+// SetOption sets a provider-specific option
+func (c *MetricsClient) SetOption(key string, value interface{}) error {
+	return c.client.SetOption(key, value)
+}
+
+// GetOption gets a provider-specific option
+func (c *MetricsClient) GetOption(key string) (interface{}, bool) {
+	return c.client.GetOption(key)
+}
+
+// Unwrap returns the underlying LLM client
+func (c *MetricsClient) Unwrap() LLM {
+	return c.client
+}
+
+func (c *MetricsClient) newMetrics(operation, model string, latency time.Duration, err error) RequestMetrics {
+	m := RequestMetrics{
+		Provider:  c.client.ID(),
+		Model:     model,
+		Operation: operation,
+		Latency:   latency,
+		Err:       err,
+	}
+	if err != nil {
+		m.ErrorType = errorTypeOf(err)
+	}
+	return m
+}
+
+// errorTypeOf classifies an error for metrics
+func errorTypeOf(err error) ErrorType {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Type
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTypeTimeout
+	default:
+		return ErrorTypeUnknown
+	}
+}
+
+// tokensPerSecond returns the output rate over the generation time,
+// preferring the provider-reported generation time when available
+func tokensPerSecond(usage Usage, generation time.Duration) float64 {
+	if usage.EvalDuration > 0 {
+		generation = usage.EvalDuration
+	}
+	if usage.CompletionTokens == 0 || generation <= 0 {
+		return 0
+	}
+	return float64(usage.CompletionTokens) / generation.Seconds()
+}
+
+// Ensure MetricsClient implements LLM interface
+var _ LLM = (*MetricsClient)(nil)
+
+// =============================================================================
+// Expvar Recorder
+// =============================================================================
+
+// metricsWindow is the number of recent samples kept for quantiles
+const metricsWindow = 1000
+
+// Summary summarizes a series of observations. Count and Sum cover every
+// observation; the quantiles cover the most recent ones.
+type Summary struct {
+	Count int64   `json:"count"`
+	Sum   float64 `json:"sum"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+}
+
+// ModelMetrics are the metrics collected for one provider and model
+type ModelMetrics struct {
+	Requests         int64               `json:"requests"`
+	Errors           map[ErrorType]int64 `json:"errors"`
+	InputTokens      int64               `json:"input_tokens"`
+	OutputTokens     int64               `json:"output_tokens"`
+	Latency          Summary             `json:"latency_seconds"`
+	TimeToFirstToken Summary             `json:"time_to_first_token_seconds"`
+	TokensPerSecond  Summary             `json:"tokens_per_second"`
+	ModelLoad        Summary             `json:"model_load_seconds"`
+}
+
+// ExpvarRecorder is a MetricsRecorder that aggregates metrics per provider
+// and model and publishes them with expvar
+type ExpvarRecorder struct {
+	mu     sync.Mutex
+	models map[string]*modelStats
+}
+
+var (
+	defaultRecorder     *ExpvarRecorder
+	defaultRecorderOnce sync.Once
+)
+
+// DefaultMetricsRecorder returns the shared recorder published as the
+// "gollmx" expvar
+func DefaultMetricsRecorder() *ExpvarRecorder {
+	defaultRecorderOnce.Do(func() {
+		defaultRecorder = NewExpvarRecorder("gollmx")
+	})
+	return defaultRecorder
+}
+
+// NewExpvarRecorder creates a recorder published under name. An empty name
+// creates an unpublished recorder. Like expvar.Publish, it panics if the
+// name is already in use.
+func NewExpvarRecorder(name string) *ExpvarRecorder {
+	r := &ExpvarRecorder{models: make(map[string]*modelStats)}
+	if name != "" {
+		expvar.Publish(name, expvar.Func(func() interface{} { return r.Snapshot() }))
+	}
+	return r
+}
+
+// RecordRequest implements MetricsRecorder
+func (r *ExpvarRecorder) RecordRequest(ctx context.Context, m RequestMetrics) {
+	key := m.Provider + "/" + m.Model
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.models[key]
+	if !ok {
+		stats = &modelStats{errors: make(map[ErrorType]int64)}
+		r.models[key] = stats
+	}
+
+	stats.requests++
+	if m.ErrorType != "" {
+		stats.errors[m.ErrorType]++
+	}
+	stats.inputTokens += int64(m.Usage.PromptTokens)
+	stats.outputTokens += int64(m.Usage.CompletionTokens)
+	stats.latency.observe(m.Latency.Seconds())
+	if m.TimeToFirstToken > 0 {
+		stats.ttft.observe(m.TimeToFirstToken.Seconds())
+	}
+	if m.TokensPerSecond > 0 {
+		stats.tokensPerSecond.observe(m.TokensPerSecond)
+	}
+	if m.Usage.LoadDuration > 0 {
+		stats.modelLoad.observe(m.Usage.LoadDuration.Seconds())
+	}
+}
+
+// Snapshot returns the current metrics keyed by "provider/model"
+func (r *ExpvarRecorder) Snapshot() map[string]ModelMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make(map[string]ModelMetrics, len(r.models))
+	for key, stats := range r.models {
+		errs := make(map[ErrorType]int64, len(stats.errors))
+		for t, n := range stats.errors {
+			errs[t] = n
+		}
+		snapshot[key] = ModelMetrics{
+			Requests:         stats.requests,
+			Errors:           errs,
+			InputTokens:      stats.inputTokens,
+			OutputTokens:     stats.outputTokens,
+			Latency:          stats.latency.summary(),
+			TimeToFirstToken: stats.ttft.summary(),
+			TokensPerSecond:  stats.tokensPerSecond.summary(),
+			ModelLoad:        stats.modelLoad.summary(),
+		}
+	}
+	return snapshot
+}
+
+type modelStats struct {
+	requests        int64
+	errors          map[ErrorType]int64
+	inputTokens     int64
+	outputTokens    int64
+	latency         series
+	ttft            series
+	tokensPerSecond series
+	modelLoad       series
+}
+
+// series keeps a running count and sum plus a window of recent samples
+type series struct {
+	count   int64
+	sum     float64
+	samples []float64
+	next    int
+}
+
+func (s *series) observe(v float64) {
+	s.count++
+	s.sum += v
+	if len(s.samples) < metricsWindow {
+		s.samples = append(s.samples, v)
+		return
+	}
+	s.samples[s.next] = v
+	s.next = (s.next + 1) % metricsWindow
+}
+
+func (s *series) summary() Summary {
+	sum := Summary{Count: s.count, Sum: s.sum}
+	if len(s.samples) == 0 {
+		return sum
+	}
+	sorted := append([]float64(nil), s.samples...)
+	sort.Float64s(sorted)
+	sum.P50 = quantile(sorted, 0.50)
+	sum.P95 = quantile(sorted, 0.95)
+	sum.P99 = quantile(sorted, 0.99)
+	return sum
+}
+
+// quantile returns the nearest-rank quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
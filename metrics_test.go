@@ -0,0 +1,206 @@
+package gollmx
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// metricsLLM returns scripted chat and stream responses
+type metricsLLM struct {
+	mockLLM
+	chat   *ChatResponse
+	chunks []StreamChunk
+	err    error
+	delay  time.Duration
+}
+
+func (m *metricsLLM) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	time.Sleep(m.delay)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.chat, nil
+}
+
+func (m *metricsLLM) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		for _, chunk := range m.chunks {
+			time.Sleep(m.delay)
+			ch <- chunk
+		}
+	}()
+	return NewStreamReader(ch), nil
+}
+
+// captureRecorder keeps every recorded RequestMetrics
+type captureRecorder struct {
+	mu      sync.Mutex
+	records []RequestMetrics
+}
+
+func (r *captureRecorder) RecordRequest(ctx context.Context, m RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, m)
+}
+
+func (r *captureRecorder) only(t *testing.T) RequestMetrics {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(r.records))
+	}
+	return r.records[0]
+}
+
+func TestMetricsClientChat(t *testing.T) {
+	inner := &metricsLLM{
+		mockLLM: mockLLM{id: "test"},
+		chat: &ChatResponse{
+			Model: "model-2024",
+			Usage: Usage{PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30, EvalDuration: 2 * time.Second},
+		},
+	}
+	recorder := &captureRecorder{}
+	client := WithMetrics(inner, recorder)
+
+	if _, err := client.Chat(context.Background(), &ChatRequest{Model: "model"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := recorder.only(t)
+	if m.Provider != "test" || m.Model != "model" || m.Operation != OperationChat {
+		t.Errorf("unexpected labels: %+v", m)
+	}
+	if m.Stream {
+		t.Error("expected stream=false")
+	}
+	if m.Usage.CompletionTokens != 20 {
+		t.Errorf("expected 20 output tokens, got %d", m.Usage.CompletionTokens)
+	}
+	// Provider-reported generation time takes precedence over latency
+	if m.TokensPerSecond != 10 {
+		t.Errorf("expected 10 tokens/s from eval duration, got %v", m.TokensPerSecond)
+	}
+	if m.ErrorType != "" {
+		t.Errorf("expected no error type, got '%s'", m.ErrorType)
+	}
+}
+
+func TestMetricsClientChatError(t *testing.T) {
+	inner := &metricsLLM{mockLLM: mockLLM{id: "test"}, err: NewAPIError(ErrorTypeRateLimit, "test", "slow down")}
+	recorder := &captureRecorder{}
+	client := WithMetrics(inner, recorder)
+
+	if _, err := client.Chat(context.Background(), &ChatRequest{Model: "model"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if m := recorder.only(t); m.ErrorType != ErrorTypeRateLimit {
+		t.Errorf("expected error type 'rate_limit', got '%s'", m.ErrorType)
+	}
+}
+
+func TestMetricsClientChatStream(t *testing.T) {
+	inner := &metricsLLM{
+		mockLLM: mockLLM{id: "test"},
+		delay:   20 * time.Millisecond,
+		chunks: []StreamChunk{
+			{Model: "model", Content: "Hello"},
+			{Model: "model", Content: " world", FinishReason: "stop", Usage: Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7}},
+		},
+	}
+	recorder := &captureRecorder{}
+	client := WithMetrics(inner, recorder)
+
+	stream, err := client.ChatStream(context.Background(), &ChatRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetContent() != "Hello world" {
+		t.Errorf("expected 'Hello world', got '%s'", resp.GetContent())
+	}
+
+	m := recorder.only(t)
+	if !m.Stream {
+		t.Error("expected stream=true")
+	}
+	if m.Model != "model" {
+		t.Errorf("expected model from the stream, got '%s'", m.Model)
+	}
+	if m.TimeToFirstToken < 20*time.Millisecond || m.TimeToFirstToken >= m.Latency {
+		t.Errorf("expected TTFT between the first chunk and the end, got %v (latency %v)", m.TimeToFirstToken, m.Latency)
+	}
+	if m.TokensPerSecond <= 0 {
+		t.Errorf("expected tokens/s to be measured, got %v", m.TokensPerSecond)
+	}
+}
+
+func TestExpvarRecorder(t *testing.T) {
+	recorder := NewExpvarRecorder("")
+	ctx := context.Background()
+
+	for i := 1; i <= 100; i++ {
+		recorder.RecordRequest(ctx, RequestMetrics{
+			Provider: "openai",
+			Model:    "gpt-4o",
+			Latency:  time.Duration(i) * time.Millisecond,
+			Usage:    Usage{PromptTokens: 2, CompletionTokens: 3},
+		})
+	}
+	recorder.RecordRequest(ctx, RequestMetrics{Provider: "openai", Model: "gpt-4o", ErrorType: ErrorTypeServer})
+	recorder.RecordRequest(ctx, RequestMetrics{
+		Provider: "ollama",
+		Model:    "llama3.2",
+		Usage:    Usage{LoadDuration: 1500 * time.Millisecond},
+	})
+
+	snapshot := recorder.Snapshot()
+	m, ok := snapshot["openai/gpt-4o"]
+	if !ok {
+		t.Fatalf("expected metrics for openai/gpt-4o, got %v", snapshot)
+	}
+	if m.Requests != 101 {
+		t.Errorf("expected 101 requests, got %d", m.Requests)
+	}
+	if m.Errors[ErrorTypeServer] != 1 {
+		t.Errorf("expected 1 server error, got %d", m.Errors[ErrorTypeServer])
+	}
+	if m.InputTokens != 200 || m.OutputTokens != 300 {
+		t.Errorf("expected 200/300 tokens, got %d/%d", m.InputTokens, m.OutputTokens)
+	}
+	if math.Abs(m.Latency.P95-0.095) > 1e-9 {
+		t.Errorf("expected p95 latency 0.095s, got %v", m.Latency.P95)
+	}
+
+	if load := snapshot["ollama/llama3.2"].ModelLoad; load.Count != 1 || load.Sum != 1.5 {
+		t.Errorf("expected one 1.5s model load, got %+v", load)
+	}
+}
+
+func TestSeriesWindow(t *testing.T) {
+	var s series
+	for i := 0; i < metricsWindow+10; i++ {
+		s.observe(float64(i))
+	}
+	summary := s.summary()
+	if summary.Count != metricsWindow+10 {
+		t.Errorf("expected count %d, got %d", metricsWindow+10, summary.Count)
+	}
+	// The oldest samples have been replaced
+	if summary.P50 < 10 {
+		t.Errorf("expected quantiles over the recent window, got p50 %v", summary.P50)
+	}
+}
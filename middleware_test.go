@@ -0,0 +1,50 @@
+package gollmx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+// Middleware tests that need gollmxtest, which imports this package
+
+// lastRecorder keeps the last metrics it receives
+type lastRecorder struct {
+	mu   sync.Mutex
+	last gollmx.RequestMetrics
+}
+
+func (r *lastRecorder) RecordRequest(ctx context.Context, m gollmx.RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = m
+}
+
+func TestMetricsClientChatStreamCancel(t *testing.T) {
+	recorder := &lastRecorder{}
+	gollmxtest.CheckStreamCancel(t, func(client gollmx.LLM) gollmx.LLM {
+		return gollmx.WithMetrics(client, recorder)
+	}, &gollmx.ChatRequest{Model: "model"})
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if !errors.Is(recorder.last.Err, context.Canceled) {
+		t.Errorf("expected a cancelled stream to be recorded, got %v", recorder.last.Err)
+	}
+}
+
+func TestCachingClientChatStreamCancel(t *testing.T) {
+	temperature := 0.0
+	req := &gollmx.ChatRequest{
+		Model:       "model",
+		Messages:    []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+		Temperature: &temperature,
+	}
+	gollmxtest.CheckStreamCancel(t, func(client gollmx.LLM) gollmx.LLM {
+		return gollmx.WithCache(client, nil)
+	}, req)
+}
@@ -0,0 +1,481 @@
+package gollmx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ModelRef identifies a model of a provider
+type ModelRef struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// String returns the reference as "provider/model"
+func (r ModelRef) String() string {
+	return r.Provider + "/" + r.Model
+}
+
+// ModelQuery selects models from a ModelRegistry. Zero fields match any
+// model.
+type ModelQuery struct {
+	Providers         []string  // Only these providers
+	Features          []Feature // Required features
+	MinContextWindow  int       // Minimum context window in tokens
+	MinOutput         int       // Minimum output tokens
+	Priced            bool      // Exclude models without pricing, such as local models
+	IncludeDeprecated bool      // Include deprecated models
+}
+
+// matches reports whether m satisfies the query
+func (q *ModelQuery) matches(m *Model) bool {
+	if len(q.Providers) > 0 && !containsString(q.Providers, m.Provider) {
+		return false
+	}
+	for _, f := range q.Features {
+		if !m.SupportsFeature(f) {
+			return false
+		}
+	}
+	return m.ContextWindow >= q.MinContextWindow &&
+		m.MaxOutput >= q.MinOutput &&
+		(!q.Priced || m.InputPrice+m.OutputPrice > 0) &&
+		(q.IncludeDeprecated || !m.Deprecated)
+}
+
+// ModelRegistry maps aliases such as "fast" or "cheap-embed" to provider
+// models and answers queries over the model metadata of every provider.
+// Providers register their built-in metadata with RegisterModels when
+// imported. A ModelRegistry is safe for concurrent use.
+type ModelRegistry struct {
+	mu      sync.RWMutex
+	aliases map[string]ModelRef
+	models  map[string][]Model // By provider ID
+	logger  *slog.Logger
+	warned  map[ModelRef]bool
+}
+
+// NewModelRegistry creates an empty model registry
+func NewModelRegistry() *ModelRegistry {
+	return &ModelRegistry{
+		aliases: make(map[string]ModelRef),
+		models:  make(map[string][]Model),
+		warned:  make(map[ModelRef]bool),
+	}
+}
+
+// DefaultModelRegistry is the registry used by the package-level functions
+// and populated by provider packages
+var DefaultModelRegistry = NewModelRegistry()
+
+// SetLogger sets the logger for deprecation warnings. A nil logger uses
+// slog.Default().
+func (r *ModelRegistry) SetLogger(logger *slog.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = logger
+}
+
+// RegisterModels sets the model metadata of a provider, replacing any
+// registered before. Call it with the result of ListModels to query live
+// models.
+func (r *ModelRegistry) RegisterModels(provider string, models []Model) {
+	copied := make([]Model, len(models))
+	for i, m := range models {
+		if m.Provider == "" {
+			m.Provider = provider
+		}
+		copied[i] = m
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models[provider] = copied
+}
+
+// SetAlias maps alias to a provider model, replacing any existing mapping
+func (r *ModelRegistry) SetAlias(alias string, ref ModelRef) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[alias] = ref
+}
+
+// RemoveAlias removes an alias
+func (r *ModelRegistry) RemoveAlias(alias string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.aliases, alias)
+}
+
+// Aliases returns a copy of the alias mappings
+func (r *ModelRegistry) Aliases() map[string]ModelRef {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(map[string]ModelRef, len(r.aliases))
+	for alias, ref := range r.aliases {
+		aliases[alias] = ref
+	}
+	return aliases
+}
+
+// Models returns the registered models of every provider, ordered by
+// provider ID
+func (r *ModelRegistry) Models() []Model {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]string, 0, len(r.models))
+	for p := range r.models {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+
+	var models []Model
+	for _, p := range providers {
+		models = append(models, r.models[p]...)
+	}
+	return models
+}
+
+// Lookup returns the metadata of a model. A model ID with a ":" tag (e.g.
+// "llama3.2:3b") also matches the untagged ID.
+func (r *ModelRegistry) Lookup(ref ModelRef) (*Model, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lookup(ref)
+}
+
+func (r *ModelRegistry) lookup(ref ModelRef) (*Model, bool) {
+	models := r.models[ref.Provider]
+	base, _, _ := strings.Cut(ref.Model, ":")
+	for _, id := range []string{ref.Model, base} {
+		for i := range models {
+			if models[i].ID == id {
+				m := models[i]
+				return &m, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Resolve returns the provider model for name, which may be an alias, a
+// "provider/model" reference or a model ID registered by exactly one
+// provider
+func (r *ModelRegistry) Resolve(name string) (ModelRef, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if ref, ok := r.aliases[name]; ok {
+		return ref, nil
+	}
+
+	// Model IDs may themselves contain "/", so only a known provider prefix
+	// makes a reference
+	if provider, model, ok := strings.Cut(name, "/"); ok {
+		if _, registered := r.models[provider]; registered || HasProvider(provider) {
+			return ModelRef{Provider: provider, Model: model}, nil
+		}
+	}
+
+	var matches []ModelRef
+	for provider := range r.models {
+		ref := ModelRef{Provider: provider, Model: name}
+		if _, ok := r.lookup(ref); ok {
+			matches = append(matches, ref)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ModelRef{}, NewAPIError(ErrorTypeModelNotFound, "", fmt.Sprintf("unknown model or alias: %s", name))
+	case 1:
+		return matches[0], nil
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Provider < matches[j].Provider })
+	return ModelRef{}, NewAPIError(ErrorTypeInvalidRequest, "",
+		fmt.Sprintf("model %s is offered by several providers %v; use provider/model", name, matches))
+}
+
+// Find returns the models matching q, cheapest first by combined input and
+// output price. Ties are broken by larger context window, then by provider
+// and model ID.
+func (r *ModelRegistry) Find(q ModelQuery) []Model {
+	var found []Model
+	for _, m := range r.Models() {
+		if q.matches(&m) {
+			found = append(found, m)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := &found[i], &found[j]
+		if pa, pb := a.InputPrice+a.OutputPrice, b.InputPrice+b.OutputPrice; pa != pb {
+			return pa < pb
+		}
+		if a.ContextWindow != b.ContextWindow {
+			return a.ContextWindow > b.ContextWindow
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.ID < b.ID
+	})
+	return found
+}
+
+// Cheapest returns the cheapest model matching q
+func (r *ModelRegistry) Cheapest(q ModelQuery) (*Model, error) {
+	found := r.Find(q)
+	if len(found) == 0 {
+		return nil, NewAPIError(ErrorTypeModelNotFound, "", "no model matches the query")
+	}
+	return &found[0], nil
+}
+
+// WarnIfDeprecated logs a warning the first time a deprecated model is
+// requested and reports whether the model is deprecated
+func (r *ModelRegistry) WarnIfDeprecated(ctx context.Context, ref ModelRef) bool {
+	r.mu.RLock()
+	m, ok := r.lookup(ref)
+	r.mu.RUnlock()
+	if !ok || !m.Deprecated {
+		return false
+	}
+	r.warnOnce(ctx, ref, nil)
+	return true
+}
+
+// warnOnce logs a deprecation warning for ref unless one was already logged.
+// A nil logger uses the registry's logger.
+func (r *ModelRegistry) warnOnce(ctx context.Context, ref ModelRef, logger *slog.Logger) {
+	r.mu.Lock()
+	warned := r.warned[ref]
+	r.warned[ref] = true
+	if logger == nil {
+		logger = r.logger
+	}
+	r.mu.Unlock()
+
+	if warned {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, slog.LevelWarn, "deprecated model requested",
+		slog.String("provider", ref.Provider),
+		slog.String("model", ref.Model),
+	)
+}
+
+// New resolves name and creates a client for its provider with the model
+// as the default. The client resolves aliases in request models and warns
+// about deprecated models; see RegistryClient.
+func (r *ModelRegistry) New(name string, opts ...Option) (*RegistryClient, error) {
+	ref, err := r.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts[:len(opts):len(opts)], WithDefaultModel(ref.Model))
+	client, err := New(ref.Provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewRegistryClient(client, r)
+	c.defaultModel = ref.Model
+	return c, nil
+}
+
+// RegisterModels sets a provider's model metadata in DefaultModelRegistry
+func RegisterModels(provider string, models []Model) {
+	DefaultModelRegistry.RegisterModels(provider, models)
+}
+
+// SetModelAlias maps alias to a provider model in DefaultModelRegistry
+func SetModelAlias(alias, provider, model string) {
+	DefaultModelRegistry.SetAlias(alias, ModelRef{Provider: provider, Model: model})
+}
+
+// ResolveModel resolves an alias, "provider/model" reference or model ID
+// with DefaultModelRegistry
+func ResolveModel(name string) (ModelRef, error) {
+	return DefaultModelRegistry.Resolve(name)
+}
+
+// FindModels returns the models in DefaultModelRegistry matching q,
+// cheapest first
+func FindModels(q ModelQuery) []Model {
+	return DefaultModelRegistry.Find(q)
+}
+
+// CheapestModel returns the cheapest model in DefaultModelRegistry
+// matching q
+func CheapestModel(q ModelQuery) (*Model, error) {
+	return DefaultModelRegistry.Cheapest(q)
+}
+
+// NewForModel creates a client for an alias, "provider/model" reference or
+// model ID resolved with DefaultModelRegistry
+func NewForModel(name string, opts ...Option) (*RegistryClient, error) {
+	return DefaultModelRegistry.New(name, opts...)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Registry Client
+// =============================================================================
+
+// RegistryClient wraps an LLM client to resolve request models through a
+// ModelRegistry. Aliases are replaced with the model they map to, and
+// requests for deprecated models log a warning.
+type RegistryClient struct {
+	client       LLM
+	registry     *ModelRegistry
+	defaultModel string // Used for deprecation checks when a request has no model
+}
+
+// NewRegistryClient wraps client with registry. A nil registry uses
+// DefaultModelRegistry.
+func NewRegistryClient(client LLM, registry *ModelRegistry) *RegistryClient {
+	if registry == nil {
+		registry = DefaultModelRegistry
+	}
+	return &RegistryClient{
+		client:   client,
+		registry: registry,
+	}
+}
+
+// resolve returns the provider model for a request model and warns if it is
+// deprecated. An empty chat or completion model is the client's default.
+func (c *RegistryClient) resolve(ctx context.Context, model string, chat bool) (string, error) {
+	if model == "" && chat {
+		model = c.defaultModel
+	} else if ref, ok := c.registry.Aliases()[model]; ok {
+		if ref.Provider != c.client.ID() {
+			return "", NewAPIError(ErrorTypeInvalidRequest, c.client.ID(),
+				fmt.Sprintf("alias %s refers to a %s model", model, ref.Provider))
+		}
+		model = ref.Model
+	}
+
+	if model != "" {
+		c.registry.WarnIfDeprecated(ctx, ModelRef{Provider: c.client.ID(), Model: model})
+	}
+	return model, nil
+}
+
+// ID returns the provider identifier
+func (c *RegistryClient) ID() string {
+	return c.client.ID()
+}
+
+// Name returns the provider name
+func (c *RegistryClient) Name() string {
+	return c.client.Name()
+}
+
+// Version returns the client version
+func (c *RegistryClient) Version() string {
+	return c.client.Version()
+}
+
+// BaseURL returns the API base URL
+func (c *RegistryClient) BaseURL() string {
+	return c.client.BaseURL()
+}
+
+// Models returns available models
+func (c *RegistryClient) Models() []Model {
+	return c.client.Models()
+}
+
+// GetModel returns a specific model
+func (c *RegistryClient) GetModel(id string) (*Model, error) {
+	return c.client.GetModel(id)
+}
+
+// Chat performs a chat completion with the resolved model
+func (c *RegistryClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	model, err := c.resolve(ctx, req.Model, true)
+	if err != nil {
+		return nil, err
+	}
+	r := *req
+	r.Model = model
+	return c.client.Chat(ctx, &r)
+}
+
+// ChatStream performs a streaming chat completion with the resolved model
+func (c *RegistryClient) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	model, err := c.resolve(ctx, req.Model, true)
+	if err != nil {
+		return nil, err
+	}
+	r := *req
+	r.Model = model
+	return c.client.ChatStream(ctx, &r)
+}
+
+// Complete performs a text completion with the resolved model
+func (c *RegistryClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	model, err := c.resolve(ctx, req.Model, true)
+	if err != nil {
+		return nil, err
+	}
+	r := *req
+	r.Model = model
+	return c.client.Complete(ctx, &r)
+}
+
+// Embed generates embeddings with the resolved model
+func (c *RegistryClient) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	model, err := c.resolve(ctx, req.Model, false)
+	if err != nil {
+		return nil, err
+	}
+	r := *req
+	r.Model = model
+	return c.client.Embed(ctx, &r)
+}
+
+// HasFeature checks if a feature is supported
+func (c *RegistryClient) HasFeature(feature Feature) bool {
+	return c.client.HasFeature(feature)
+}
+
+// Features returns all supported features
+func (c *RegistryClient) Features() []Feature {
+	return c.client.Features()
+}
+
+// SetOption sets a provider-specific option
+func (c *RegistryClient) SetOption(key string, value interface{}) error {
+	return c.client.SetOption(key, value)
+}
+
+// GetOption gets a provider-specific option
+func (c *RegistryClient) GetOption(key string) (interface{}, bool) {
+	return c.client.GetOption(key)
+}
+
+// Unwrap returns the underlying LLM client
+func (c *RegistryClient) Unwrap() LLM {
+	return c.client
+}
@@ -0,0 +1,213 @@
+package gollmx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// recordingLLM records the model of each request
+type recordingLLM struct {
+	mockLLM
+	models []string
+}
+
+func (m *recordingLLM) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	m.models = append(m.models, req.Model)
+	return &ChatResponse{}, nil
+}
+
+func (m *recordingLLM) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	m.models = append(m.models, req.Model)
+	return &EmbedResponse{}, nil
+}
+
+func newTestModelRegistry() *ModelRegistry {
+	r := NewModelRegistry()
+	r.RegisterModels("alpha", []Model{
+		{ID: "alpha-large", ContextWindow: 200000, MaxOutput: 8192, InputPrice: 3, OutputPrice: 15, Features: []Feature{FeatureChat, FeatureVision}},
+		{ID: "alpha-small", ContextWindow: 128000, MaxOutput: 4096, InputPrice: 0.15, OutputPrice: 0.6, Features: []Feature{FeatureChat, FeatureVision}},
+		{ID: "alpha-old", ContextWindow: 200000, InputPrice: 0.1, OutputPrice: 0.1, Features: []Feature{FeatureChat, FeatureVision}, Deprecated: true},
+		{ID: "alpha-embed", ContextWindow: 8192, InputPrice: 0.02, Features: []Feature{FeatureEmbedding}},
+	})
+	r.RegisterModels("beta", []Model{
+		{ID: "beta-chat", ContextWindow: 32000, InputPrice: 0.05, OutputPrice: 0.1, Features: []Feature{FeatureChat}},
+		{ID: "shared", ContextWindow: 8000, Features: []Feature{FeatureChat}},
+	})
+	r.RegisterModels("local", []Model{
+		{ID: "llama", ContextWindow: 128000, Features: []Feature{FeatureChat, FeatureVision}},
+		{ID: "shared", ContextWindow: 8000, Features: []Feature{FeatureChat}},
+	})
+	return r
+}
+
+func TestModelRegistryResolve(t *testing.T) {
+	r := newTestModelRegistry()
+	r.SetAlias("fast", ModelRef{Provider: "alpha", Model: "alpha-small"})
+
+	tests := []struct {
+		name     string
+		expected ModelRef
+	}{
+		{"fast", ModelRef{"alpha", "alpha-small"}},
+		{"beta/beta-chat", ModelRef{"beta", "beta-chat"}},
+		{"beta/new-model", ModelRef{"beta", "new-model"}},
+		{"alpha-large", ModelRef{"alpha", "alpha-large"}},
+		{"llama:8b", ModelRef{"local", "llama:8b"}},
+	}
+	for _, tt := range tests {
+		ref, err := r.Resolve(tt.name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if ref != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ref)
+		}
+	}
+
+	if _, err := r.Resolve("nope"); !isErrorType(err, ErrorTypeModelNotFound) {
+		t.Errorf("expected model_not_found error, got %v", err)
+	}
+	if _, err := r.Resolve("shared"); !isErrorType(err, ErrorTypeInvalidRequest) {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+
+	r.RemoveAlias("fast")
+	if len(r.Aliases()) != 0 {
+		t.Errorf("expected no aliases, got %v", r.Aliases())
+	}
+}
+
+func isErrorType(err error, errType ErrorType) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.Type == errType
+}
+
+func TestModelRegistryFind(t *testing.T) {
+	r := newTestModelRegistry()
+
+	found := r.Find(ModelQuery{Features: []Feature{FeatureVision}, MinContextWindow: 100000})
+	ids := make([]string, len(found))
+	for i, m := range found {
+		ids[i] = m.ID
+	}
+	// Free local models first, deprecated models excluded
+	if strings.Join(ids, ",") != "llama,alpha-small,alpha-large" {
+		t.Errorf("expected llama,alpha-small,alpha-large, got %v", ids)
+	}
+	if found[0].Provider != "local" {
+		t.Errorf("expected the provider to be filled in, got '%s'", found[0].Provider)
+	}
+
+	cheapest, err := r.Cheapest(ModelQuery{Features: []Feature{FeatureVision}, MinContextWindow: 100000, Priced: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cheapest.ID != "alpha-small" {
+		t.Errorf("expected alpha-small, got %s", cheapest.ID)
+	}
+
+	withDeprecated, _ := r.Cheapest(ModelQuery{Providers: []string{"alpha"}, Features: []Feature{FeatureChat}, IncludeDeprecated: true})
+	if withDeprecated.ID != "alpha-old" {
+		t.Errorf("expected alpha-old, got %s", withDeprecated.ID)
+	}
+
+	if _, err := r.Cheapest(ModelQuery{MinContextWindow: 1000000}); !isErrorType(err, ErrorTypeModelNotFound) {
+		t.Errorf("expected model_not_found error, got %v", err)
+	}
+}
+
+func TestModelRegistryWarnIfDeprecated(t *testing.T) {
+	r := newTestModelRegistry()
+	logger, buf := newTestLogger(slog.LevelInfo)
+	r.SetLogger(logger)
+	ctx := context.Background()
+
+	if !r.WarnIfDeprecated(ctx, ModelRef{"alpha", "alpha-old"}) {
+		t.Error("expected alpha-old to be deprecated")
+	}
+	r.WarnIfDeprecated(ctx, ModelRef{"alpha", "alpha-old"})
+	if r.WarnIfDeprecated(ctx, ModelRef{"alpha", "alpha-small"}) {
+		t.Error("expected alpha-small not to be deprecated")
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected one warning, got %d", len(records))
+	}
+	if records[0]["level"] != "WARN" || records[0]["model"] != "alpha-old" {
+		t.Errorf("unexpected record: %v", records[0])
+	}
+}
+
+func TestRegistryClient(t *testing.T) {
+	r := newTestModelRegistry()
+	r.SetAlias("fast", ModelRef{Provider: "alpha", Model: "alpha-small"})
+	r.SetAlias("cheap-embed", ModelRef{Provider: "alpha", Model: "alpha-embed"})
+	r.SetAlias("other", ModelRef{Provider: "beta", Model: "beta-chat"})
+	logger, buf := newTestLogger(slog.LevelInfo)
+	r.SetLogger(logger)
+
+	inner := &recordingLLM{mockLLM: mockLLM{id: "alpha"}}
+	client := NewRegistryClient(inner, r)
+	ctx := context.Background()
+
+	client.Chat(ctx, &ChatRequest{Model: "fast"})
+	client.Chat(ctx, &ChatRequest{Model: "alpha-old"})
+	client.Embed(ctx, &EmbedRequest{Model: "cheap-embed"})
+	client.Embed(ctx, &EmbedRequest{})
+
+	expected := []string{"alpha-small", "alpha-old", "alpha-embed", ""}
+	if strings.Join(inner.models, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected models %v, got %v", expected, inner.models)
+	}
+
+	if _, err := client.Chat(ctx, &ChatRequest{Model: "other"}); !isErrorType(err, ErrorTypeInvalidRequest) {
+		t.Errorf("expected invalid_request error for another provider's alias, got %v", err)
+	}
+
+	if records := logRecords(t, buf); len(records) != 1 || records[0]["model"] != "alpha-old" {
+		t.Errorf("expected one deprecation warning for alpha-old, got %v", records)
+	}
+	if client.Unwrap() != inner {
+		t.Error("expected Unwrap to return the inner client")
+	}
+}
+
+func TestModelRegistryNew(t *testing.T) {
+	inner := &recordingLLM{mockLLM: mockLLM{id: "registry-test"}}
+	var config *Config
+	Register("registry-test", func(opts ...Option) (LLM, error) {
+		config = DefaultConfig()
+		config.Apply(opts...)
+		return inner, nil
+	})
+
+	r := NewModelRegistry()
+	r.RegisterModels("registry-test", []Model{{ID: "old-model", Deprecated: true}})
+	r.SetAlias("smart", ModelRef{Provider: "registry-test", Model: "old-model"})
+	logger, buf := newTestLogger(slog.LevelInfo)
+	r.SetLogger(logger)
+
+	client, err := r.New("smart", WithAPIKey("key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DefaultModel != "old-model" || config.APIKey != "key" {
+		t.Errorf("expected the default model and options to be applied, got %+v", config)
+	}
+
+	client.Chat(context.Background(), &ChatRequest{})
+	if len(inner.models) != 1 || inner.models[0] != "old-model" {
+		t.Errorf("expected the default model to be requested, got %v", inner.models)
+	}
+	if records := logRecords(t, buf); len(records) != 1 {
+		t.Errorf("expected a deprecation warning, got %v", records)
+	}
+
+	if _, err := r.New("unknown"); err == nil {
+		t.Error("expected error for an unknown model")
+	}
+}
@@ -0,0 +1,186 @@
+package gollmx
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultModelCacheTTL is how long ListModels results are cached by default
+const DefaultModelCacheTTL = 10 * time.Minute
+
+// ModelLister is an optional interface for providers that can list the
+// models available to the caller from their API. Use type assertion or
+// ListModels to access it:
+//
+//	models, err := gollmx.ListModels(ctx, client)
+type ModelLister interface {
+	// ListModels returns the live model list merged with the provider's
+	// static metadata. Results are cached for Config.ModelCacheTTL.
+	ListModels(ctx context.Context) ([]Model, error)
+}
+
+// ListModels returns the models available to client. Wrapped clients are
+// unwrapped to find a ModelLister; otherwise the static Models are returned.
+func ListModels(ctx context.Context, client LLM) ([]Model, error) {
+	for c := client; c != nil; {
+		if l, ok := c.(ModelLister); ok {
+			return l.ListModels(ctx)
+		}
+		u, ok := c.(interface{ Unwrap() LLM })
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+	return client.Models(), nil
+}
+
+// MergeModels returns the live models enriched with static metadata such as
+// pricing and features. A live model matches a static model with the same ID,
+// or with the same ID before a ":" tag (e.g. "llama3.2:3b" matches
+// "llama3.2"). Matched models keep their live ID, and fields missing from the
+// static entry are taken from the live one. Static models that are not live
+// are omitted.
+func MergeModels(live, static []Model) []Model {
+	byID := make(map[string]Model, len(static))
+	for _, m := range static {
+		byID[m.ID] = m
+	}
+
+	merged := make([]Model, 0, len(live))
+	for _, m := range live {
+		s, ok := byID[m.ID]
+		if !ok {
+			if base, _, tagged := strings.Cut(m.ID, ":"); tagged {
+				s, ok = byID[base]
+			}
+		}
+		if !ok {
+			merged = append(merged, m)
+			continue
+		}
+
+		s.ID = m.ID
+		if s.Provider == "" {
+			s.Provider = m.Provider
+		}
+		if s.Name == "" {
+			s.Name = m.Name
+		}
+		if s.Description == "" {
+			s.Description = m.Description
+		}
+		if s.ContextWindow == 0 {
+			s.ContextWindow = m.ContextWindow
+		}
+		if s.MaxOutput == 0 {
+			s.MaxOutput = m.MaxOutput
+		}
+		if len(s.Features) == 0 {
+			s.Features = m.Features
+		}
+		if s.ReleaseDate == "" {
+			s.ReleaseDate = m.ReleaseDate
+		}
+		s.Deprecated = s.Deprecated || m.Deprecated
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// ModelCache caches a provider's model list for a TTL. It is safe for
+// concurrent use; concurrent callers share a single fetch.
+type ModelCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	models  []Model
+	expires time.Time
+	gen     int         // Incremented by Invalidate, so older fetches are not stored
+	fetch   *modelFetch // Fetch in progress, if any
+}
+
+// modelFetch is a fetch shared by the callers that arrive while it runs
+type modelFetch struct {
+	done      chan struct{}
+	models    []Model
+	err       error
+	cancelled bool // The context of the caller running the fetch ended
+}
+
+// NewModelCache creates a cache that keeps model lists for ttl. A zero ttl
+// disables caching.
+func NewModelCache(ttl time.Duration) *ModelCache {
+	return &ModelCache{ttl: ttl}
+}
+
+// Get returns the cached models, calling fetch when they are missing or
+// expired. The lock is not held during fetch: callers that arrive while a
+// fetch is running wait for its result, or until their own ctx is done.
+// Errors are not cached.
+func (c *ModelCache) Get(ctx context.Context, fetch func(context.Context) ([]Model, error)) ([]Model, error) {
+	for {
+		c.mu.Lock()
+		if c.models != nil && time.Now().Before(c.expires) {
+			models := append([]Model(nil), c.models...)
+			c.mu.Unlock()
+			return models, nil
+		}
+		f := c.fetch
+		if f == nil {
+			f = &modelFetch{done: make(chan struct{})}
+			c.fetch = f
+			gen := c.gen
+			c.mu.Unlock()
+			c.run(ctx, f, gen, fetch)
+		} else {
+			c.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// Another caller's fetch ended with its context; fetch again with ours
+		if f.cancelled && ctx.Err() == nil {
+			continue
+		}
+		if f.err != nil {
+			return nil, f.err
+		}
+		return append([]Model(nil), f.models...), nil
+	}
+}
+
+// run performs f and stores its result unless the cache was invalidated
+// since it started
+func (c *ModelCache) run(ctx context.Context, f *modelFetch, gen int, fetch func(context.Context) ([]Model, error)) {
+	f.models, f.err = fetch(ctx)
+	f.cancelled = f.err != nil && ctx.Err() != nil
+	if f.err == nil && f.models == nil {
+		f.models = []Model{}
+	}
+
+	c.mu.Lock()
+	if c.fetch == f {
+		c.fetch = nil
+	}
+	if f.err == nil && c.ttl > 0 && c.gen == gen {
+		c.models = f.models
+		c.expires = time.Now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+	close(f.done)
+}
+
+// Invalidate discards the cached models, including the result of a fetch in
+// progress
+func (c *ModelCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.models = nil
+	c.fetch = nil
+	c.gen++
+}
@@ -0,0 +1,171 @@
+package gollmx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// listerLLM lists models, counting calls
+type listerLLM struct {
+	mockLLM
+	calls int
+}
+
+func (m *listerLLM) ListModels(ctx context.Context) ([]Model, error) {
+	m.calls++
+	return []Model{{ID: "live"}}, nil
+}
+
+func TestListModelsUnwraps(t *testing.T) {
+	inner := &listerLLM{mockLLM: mockLLM{id: "test"}}
+	models, err := ListModels(context.Background(), WithCache(inner, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "live" || inner.calls != 1 {
+		t.Errorf("expected the wrapped client's live models, got %+v", models)
+	}
+
+	models, err = ListModels(context.Background(), &mockLLM{id: "static"})
+	if err != nil || models != nil {
+		t.Errorf("expected the static models of a client without ListModels, got %+v, %v", models, err)
+	}
+}
+
+func TestMergeModels(t *testing.T) {
+	static := []Model{
+		{ID: "big", Name: "Big Model", Provider: "test", InputPrice: 3, Features: []Feature{FeatureChat, FeatureTools}},
+		{ID: "llama3.2", Name: "Llama 3.2", ContextWindow: 128000},
+		{ID: "retired", Name: "Retired"},
+	}
+	live := []Model{
+		{ID: "big", Name: "big", Provider: "test", ContextWindow: 32000, ReleaseDate: "2024-01-01"},
+		{ID: "llama3.2:3b", Name: "llama3.2:3b", Features: []Feature{FeatureChat}},
+		{ID: "new", Name: "new", Provider: "test"},
+	}
+
+	merged := MergeModels(live, static)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 live models, got %d", len(merged))
+	}
+
+	big := merged[0]
+	if big.Name != "Big Model" || big.InputPrice != 3 || len(big.Features) != 2 {
+		t.Errorf("expected static metadata to take precedence, got %+v", big)
+	}
+	if big.ContextWindow != 32000 || big.ReleaseDate != "2024-01-01" {
+		t.Errorf("expected missing fields to come from the live model, got %+v", big)
+	}
+
+	tagged := merged[1]
+	if tagged.ID != "llama3.2:3b" || tagged.Name != "Llama 3.2" || tagged.ContextWindow != 128000 || len(tagged.Features) != 1 {
+		t.Errorf("expected the tagged model to match its base, got %+v", tagged)
+	}
+	if merged[2].ID != "new" {
+		t.Errorf("expected the live-only model to be kept, got %+v", merged[2])
+	}
+}
+
+func TestModelCache(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context) ([]Model, error) {
+		calls++
+		return []Model{{ID: "m"}}, nil
+	}
+	ctx := context.Background()
+
+	cache := NewModelCache(time.Hour)
+	cache.Get(ctx, fetch)
+	models, _ := cache.Get(ctx, fetch)
+	if calls != 1 || len(models) != 1 {
+		t.Errorf("expected one fetch within the TTL, got %d", calls)
+	}
+
+	models[0].ID = "changed"
+	if models, _ := cache.Get(ctx, fetch); models[0].ID != "m" {
+		t.Error("expected callers to get a copy of the cached models")
+	}
+
+	cache.Invalidate()
+	cache.Get(ctx, fetch)
+	if calls != 2 {
+		t.Errorf("expected a fetch after invalidation, got %d", calls)
+	}
+
+	uncached := NewModelCache(0)
+	uncached.Get(ctx, fetch)
+	uncached.Get(ctx, fetch)
+	if calls != 4 {
+		t.Errorf("expected a zero TTL to disable caching, got %d fetches", calls)
+	}
+
+	failing := NewModelCache(time.Hour)
+	if _, err := failing.Get(ctx, func(context.Context) ([]Model, error) { return nil, errors.New("down") }); err == nil {
+		t.Error("expected the fetch error")
+	}
+	if models, err := failing.Get(ctx, fetch); err != nil || len(models) != 1 {
+		t.Errorf("expected errors not to be cached, got %v", err)
+	}
+}
+
+func TestModelCacheConcurrentGet(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) ([]Model, error) {
+		calls.Add(1)
+		started <- struct{}{}
+		select {
+		case <-release:
+			return []Model{{ID: "m"}}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	cache := NewModelCache(time.Hour)
+
+	// The caller running the fetch is cancelled; a waiting caller fetches
+	// again with its own context instead of failing
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cache.Get(leaderCtx, fetch)
+		leaderErr <- err
+	}()
+	<-started
+
+	type result struct {
+		models []Model
+		err    error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		models, err := cache.Get(context.Background(), fetch)
+		waiter <- result{models, err}
+	}()
+
+	// The fetch runs without the lock, so a caller that gives up returns
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.Get(expired, fetch); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled caller not to wait for the fetch, got %v", err)
+	}
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled fetch to fail, got %v", err)
+	}
+	<-started
+	close(release)
+
+	res := <-waiter
+	if res.err != nil || len(res.models) != 1 {
+		t.Fatalf("expected the waiting caller to get the models, got %v, %v", res.models, res.err)
+	}
+	if models, _ := cache.Get(context.Background(), fetch); len(models) != 1 || calls.Load() != 2 {
+		t.Errorf("expected the second fetch to be cached, got %d fetches", calls.Load())
+	}
+}
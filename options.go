@@ -1,7 +1,9 @@
 package gollmx
 
 import (
+	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -23,6 +25,35 @@ type Config struct {
 
 	// Default model
 	DefaultModel string
+
+	// Resolves media URLs for providers that require inline data
+	MediaResolver MediaResolver
+
+	// Logging
+	Logger          *slog.Logger // Receives request/response logs (nil = no logging)
+	LogRedactFields []string     // JSON body fields redacted from debug logs
+
+	// How long ListModels results are cached (0 = no caching)
+	ModelCacheTTL time.Duration
+
+	// Check requests against the model's features before sending
+	CapabilityChecks bool
+
+	// Built on first use and shared by copies of the Config, so it can be
+	// copied safely
+	lazy *lazyConfig
+}
+
+// lazyConfig holds the parts of a Config that are built on first use
+type lazyConfig struct {
+	mediaOnce     sync.Once
+	mediaResolver MediaResolver // Default resolver
+
+	debugOnce   sync.Once
+	debugLogger *slog.Logger // Stderr logger for Debug
+
+	httpOnce   sync.Once
+	httpClient *http.Client // Provider HTTP client
 }
 
 // DefaultConfig returns the default configuration
@@ -32,6 +63,9 @@ func DefaultConfig() *Config {
 		MaxRetries: 3,
 		RetryDelay: 1 * time.Second,
 		Headers:    make(map[string]string),
+
+		ModelCacheTTL: DefaultModelCacheTTL,
+		lazy:          &lazyConfig{},
 	}
 }
 
@@ -110,13 +144,30 @@ func WithHeader(key, value string) Option {
 	}
 }
 
-// WithDebug enables debug mode
+// WithDebug enables debug mode. Without a logger, requests and response
+// bodies are logged to stderr at debug level.
 func WithDebug(debug bool) Option {
 	return func(c *Config) {
 		c.Debug = debug
 	}
 }
 
+// WithLogger sets the logger for request/response logging. Bodies are
+// logged when the logger is enabled at debug level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithLogRedactFields sets JSON body fields whose values are redacted from
+// debug logs, in addition to API keys
+func WithLogRedactFields(fields ...string) Option {
+	return func(c *Config) {
+		c.LogRedactFields = append(c.LogRedactFields, fields...)
+	}
+}
+
 // WithRateLimit sets the rate limit (requests per minute)
 func WithRateLimit(rpm int) Option {
 	return func(c *Config) {
@@ -131,6 +182,31 @@ func WithDefaultModel(model string) Option {
 	}
 }
 
+// WithMediaResolver sets the resolver used to inline remote media
+func WithMediaResolver(resolver MediaResolver) Option {
+	return func(c *Config) {
+		c.MediaResolver = resolver
+	}
+}
+
+// WithModelCacheTTL sets how long ListModels results are cached. Zero
+// disables caching.
+func WithModelCacheTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.ModelCacheTTL = ttl
+	}
+}
+
+// WithCapabilityChecks enables or disables checking each request against
+// the requested model's features before sending it. Checks are disabled by
+// default, since feature metadata can lag behind the provider; when enabled,
+// requests a model does not support fail with ErrorTypeUnsupportedFeature.
+func WithCapabilityChecks(enabled bool) Option {
+	return func(c *Config) {
+		c.CapabilityChecks = enabled
+	}
+}
+
 // Apply applies all options to the config
 func (c *Config) Apply(opts ...Option) {
 	for _, opt := range opts {
@@ -138,6 +214,28 @@ func (c *Config) Apply(opts ...Option) {
 	}
 }
 
+// Configurable is an optional interface for clients that expose their
+// Config, e.g. so wrappers can share the client's logger
+type Configurable interface {
+	Config() *Config
+}
+
+// ConfigOf returns the Config of client, unwrapping wrapped clients to find
+// a Configurable. It returns nil if there is none.
+func ConfigOf(client LLM) *Config {
+	for c := client; c != nil; {
+		if cfg, ok := c.(Configurable); ok {
+			return cfg.Config()
+		}
+		u, ok := c.(interface{ Unwrap() LLM })
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.APIKey == "" {
@@ -146,6 +244,35 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// GetMediaResolver returns the media resolver, creating a default one if needed
+func (c *Config) GetMediaResolver() MediaResolver {
+	return c.MediaResolverFor("")
+}
+
+// MediaResolverFor returns the media resolver for a provider. Unless one is
+// set with WithMediaResolver, a default resolver is created on first use
+// that downloads with HTTPClientFor(provider), so media fetches share the
+// client's HTTP client, timeout and logging. A Config not made by
+// DefaultConfig builds a new one on each call.
+func (c *Config) MediaResolverFor(provider string) MediaResolver {
+	if c.MediaResolver != nil {
+		return c.MediaResolver
+	}
+	if c.lazy == nil {
+		return c.newMediaResolver(provider)
+	}
+	c.lazy.mediaOnce.Do(func() {
+		c.lazy.mediaResolver = c.newMediaResolver(provider)
+	})
+	return c.lazy.mediaResolver
+}
+
+func (c *Config) newMediaResolver(provider string) MediaResolver {
+	config := DefaultMediaResolverConfig()
+	config.HTTPClient = c.HTTPClientFor(provider)
+	return NewMediaResolver(config)
+}
+
 // GetHTTPClient returns the HTTP client, creating a default one if needed
 func (c *Config) GetHTTPClient() *http.Client {
 	if c.HTTPClient != nil {
@@ -155,3 +282,52 @@ func (c *Config) GetHTTPClient() *http.Client {
 		Timeout: c.Timeout,
 	}
 }
+
+// GetLogger returns the logger, or a stderr debug logger if Debug is set
+// without one. It returns nil when logging is disabled.
+func (c *Config) GetLogger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	if !c.Debug {
+		return nil
+	}
+	if c.lazy == nil {
+		return debugLogger()
+	}
+	c.lazy.debugOnce.Do(func() {
+		c.lazy.debugLogger = debugLogger()
+	})
+	return c.lazy.debugLogger
+}
+
+// HTTPClientFor returns the HTTP client for a provider, logging its requests
+// when a logger is configured. The client is built on the first call and
+// reused, since a Config serves a single provider.
+func (c *Config) HTTPClientFor(provider string) *http.Client {
+	if c.lazy == nil {
+		return c.newHTTPClient(provider)
+	}
+	c.lazy.httpOnce.Do(func() {
+		c.lazy.httpClient = c.newHTTPClient(provider)
+	})
+	return c.lazy.httpClient
+}
+
+func (c *Config) newHTTPClient(provider string) *http.Client {
+	client := c.GetHTTPClient()
+	logger := c.GetLogger()
+	if logger == nil {
+		return client
+	}
+
+	logged := *client
+	logged.Transport = &LoggingTransport{
+		Base:         client.Transport,
+		Logger:       logger,
+		Provider:     provider,
+		APIKey:       c.APIKey,
+		RedactFields: c.LogRedactFields,
+	}
+	return &logged
+}
@@ -1,7 +1,9 @@
 package gollmx
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -168,3 +170,67 @@ func TestConfigGetHTTPClient(t *testing.T) {
 		t.Error("GetHTTPClient should return the custom client")
 	}
 }
+
+func TestWithMediaResolver(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.GetMediaResolver() == nil {
+		t.Fatal("GetMediaResolver should return a default resolver")
+	}
+
+	resolver := NewMediaResolver(&MediaResolverConfig{MaxBytes: 1024})
+	WithMediaResolver(resolver)(cfg)
+
+	if cfg.GetMediaResolver() != resolver {
+		t.Error("expected custom media resolver")
+	}
+}
+
+func TestDefaultMediaResolverUsesHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer server.Close()
+
+	var requests int
+	cfg := DefaultConfig()
+	WithHTTPClient(&http.Client{Transport: countingTransport{&requests}})(cfg)
+
+	resolver := cfg.MediaResolverFor("test")
+	if cfg.GetMediaResolver() != resolver {
+		t.Error("expected the default resolver to be reused")
+	}
+
+	if _, err := resolver.Resolve(context.Background(), server.URL+"/cat.png"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the fetch to use the configured HTTP client, got %d requests", requests)
+	}
+}
+
+func TestConfigCopy(t *testing.T) {
+	cfg := DefaultConfig()
+	resolver := cfg.GetMediaResolver()
+
+	// Copies share the lazily built resolver
+	copied := *cfg
+	if copied.GetMediaResolver() != resolver {
+		t.Error("expected a copied config to share the default resolver")
+	}
+
+	var zero Config
+	if zero.GetMediaResolver() == nil {
+		t.Error("expected a zero config to build a default resolver")
+	}
+}
+
+// countingTransport counts requests sent through http.DefaultTransport
+type countingTransport struct {
+	count *int
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
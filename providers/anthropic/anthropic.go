@@ -25,6 +25,7 @@ const (
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, AnthropicModels)
 }
 
 // Client implements the gollmx.LLM interface for Anthropic
@@ -32,6 +33,7 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
 }
 
 // New creates a new Anthropic client
@@ -48,6 +50,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 		config:  config,
 		baseURL: baseURL,
 		options: make(map[string]interface{}),
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -73,6 +76,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns the list of available models
 func (c *Client) Models() []gollmx.Model {
 	return AnthropicModels
@@ -88,6 +96,62 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from /models,
+// merged with AnthropicModels for pricing and features
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	var live []gollmx.Model
+	afterID := ""
+	for {
+		url := c.baseURL + "/models?limit=1000"
+		if afterID != "" {
+			url += "&after_id=" + afterID
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		c.setHeaders(httpReq)
+
+		resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+		if err != nil {
+			return nil, c.handleError(err, 0, nil)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, c.handleError(nil, resp.StatusCode, respBody)
+		}
+
+		var page anthropicModelList
+		if err := json.Unmarshal(respBody, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		for _, m := range page.Data {
+			model := gollmx.Model{ID: m.ID, Name: m.DisplayName, Provider: ProviderID}
+			if created, err := time.Parse(time.RFC3339, m.CreatedAt); err == nil {
+				model.ReleaseDate = created.Format("2006-01-02")
+			}
+			live = append(live, model)
+		}
+
+		if !page.HasMore || page.LastID == "" {
+			break
+		}
+		afterID = page.LastID
+	}
+	return gollmx.MergeModels(live, AnthropicModels), nil
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -137,7 +201,14 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
-	anthropicReq := c.convertRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
+	anthropicReq, err := c.convertRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(anthropicReq)
 	if err != nil {
@@ -151,7 +222,7 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -183,7 +254,14 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
-	anthropicReq := c.convertRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
+	anthropicReq, err := c.convertRequest(req)
+	if err != nil {
+		return nil, err
+	}
 	anthropicReq.Stream = true
 
 	body, err := json.Marshal(anthropicReq)
@@ -198,7 +276,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -220,6 +298,7 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 	defer body.Close()
 
 	var messageID string
+	var inputTokens int
 	var currentToolCall *gollmx.ToolCall
 
 	scanner := bufio.NewScanner(body)
@@ -244,6 +323,7 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 		case "message_start":
 			if event.Message != nil {
 				messageID = event.Message.ID
+				inputTokens = event.Message.Usage.InputTokens
 			}
 		case "content_block_start":
 			if event.ContentBlock != nil && event.ContentBlock.Type == "tool_use" {
@@ -289,13 +369,17 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 					ID:           messageID,
 					Provider:     ProviderID,
 					Model:        model,
-					FinishReason: event.Delta.StopReason,
+					FinishReason: convertStopReason(event.Delta.StopReason),
 				}
 				if event.Usage != nil {
+					// Input tokens are reported in message_start
+					if event.Usage.InputTokens > 0 {
+						inputTokens = event.Usage.InputTokens
+					}
 					chunk.Usage = gollmx.Usage{
-						PromptTokens:     event.Usage.InputTokens,
+						PromptTokens:     inputTokens,
 						CompletionTokens: event.Usage.OutputTokens,
-						TotalTokens:      event.Usage.InputTokens + event.Usage.OutputTokens,
+						TotalTokens:      inputTokens + event.Usage.OutputTokens,
 					}
 				}
 				ch <- chunk
@@ -303,7 +387,17 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 		case "message_stop":
 			// Stream complete
 		case "error":
-			ch <- gollmx.StreamChunk{Error: fmt.Errorf("stream error")}
+			apiErr := &gollmx.APIError{
+				Type:     gollmx.ErrorTypeServer,
+				Provider: ProviderID,
+				Message:  "stream error",
+			}
+			if event.Error != nil {
+				apiErr.Message = event.Error.Message
+				apiErr.Code = event.Error.Type
+				apiErr.Retryable = event.Error.Type == "overloaded_error"
+			}
+			ch <- gollmx.StreamChunk{Error: apiErr}
 			return
 		}
 	}
@@ -418,13 +512,17 @@ func (c *Client) handleError(err error, statusCode int, body []byte) error {
 	return apiErr
 }
 
-func (c *Client) convertRequest(req *gollmx.ChatRequest) *anthropicRequest {
+func (c *Client) convertRequest(req *gollmx.ChatRequest) (*anthropicRequest, error) {
 	var systemPrompt string
 	messages := make([]anthropicMessage, 0, len(req.Messages))
 
 	for _, m := range req.Messages {
 		if m.Role == gollmx.RoleSystem {
-			systemPrompt = m.Content.(string)
+			text, err := textContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			systemPrompt = text
 			continue
 		}
 
@@ -434,22 +532,35 @@ func (c *Client) convertRequest(req *gollmx.ChatRequest) *anthropicRequest {
 
 		// Handle tool results
 		if m.Role == gollmx.RoleTool {
+			content, err := convertContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
 			msg.Role = "user"
 			msg.Content = []anthropicContentBlock{
 				{
 					Type:      "tool_result",
 					ToolUseID: m.ToolCallID,
-					Content:   m.Content.(string),
+					Content:   content,
 				},
 			}
 		} else if len(m.ToolCalls) > 0 {
 			// Assistant message with tool calls
 			blocks := make([]anthropicContentBlock, 0)
-			if content, ok := m.Content.(string); ok && content != "" {
-				blocks = append(blocks, anthropicContentBlock{
-					Type: "text",
-					Text: content,
-				})
+			switch content := m.Content.(type) {
+			case string:
+				if content != "" {
+					blocks = append(blocks, anthropicContentBlock{
+						Type: "text",
+						Text: content,
+					})
+				}
+			case []gollmx.ContentPart:
+				partBlocks, err := convertContentParts(content)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, partBlocks...)
 			}
 			for _, tc := range m.ToolCalls {
 				blocks = append(blocks, anthropicContentBlock{
@@ -461,7 +572,11 @@ func (c *Client) convertRequest(req *gollmx.ChatRequest) *anthropicRequest {
 			}
 			msg.Content = blocks
 		} else {
-			msg.Content = m.Content
+			content, err := convertContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			msg.Content = content
 		}
 
 		messages = append(messages, msg)
@@ -493,7 +608,120 @@ func (c *Client) convertRequest(req *gollmx.ChatRequest) *anthropicRequest {
 		}
 	}
 
-	return anthropicReq
+	return anthropicReq, nil
+}
+
+// convertContent converts gollmx message content (string or []ContentPart)
+// into Anthropic content: a plain string or a list of content blocks.
+func convertContent(content interface{}) (interface{}, error) {
+	switch v := content.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []gollmx.ContentPart:
+		return convertContentParts(v)
+	default:
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported message content type: %T", content))
+	}
+}
+
+// convertContentParts converts multimodal parts into Anthropic text and image blocks
+func convertContentParts(parts []gollmx.ContentPart) ([]anthropicContentBlock, error) {
+	blocks := make([]anthropicContentBlock, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			blocks = append(blocks, anthropicContentBlock{
+				Type: "text",
+				Text: part.Text,
+			})
+		case "image_url", "image_base64":
+			if part.ImageURL == nil {
+				return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+					fmt.Sprintf("%s content part is missing image_url", part.Type))
+			}
+			source, err := convertImageSource(part.ImageURL.URL)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, anthropicContentBlock{
+				Type:   "image",
+				Source: source,
+			})
+		default:
+			return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				fmt.Sprintf("unsupported content part type: %s", part.Type))
+		}
+	}
+	return blocks, nil
+}
+
+// supportedImageTypes lists the media types accepted by the Messages API
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// convertImageSource builds an Anthropic image source from a data URL,
+// an http(s) URL, or a raw base64 payload.
+func convertImageSource(url string) (*anthropicImageSource, error) {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return &anthropicImageSource{Type: "url", URL: url}, nil
+	}
+
+	mediaType, data, ok := gollmx.ParseDataURL(url)
+	if !ok {
+		if strings.HasPrefix(url, "data:") {
+			return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				"image data URL must be base64 encoded")
+		}
+		// Treat anything else as raw base64 image data
+		data = url
+	}
+
+	// The API rejects images whose bytes don't match the declared type,
+	// so prefer the sniffed type and fall back to the declared one
+	if sniffed := gollmx.DetectBase64MediaType(data); supportedImageTypes[sniffed] {
+		mediaType = sniffed
+	}
+	if !supportedImageTypes[mediaType] {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported image media type: %s", mediaType))
+	}
+
+	return &anthropicImageSource{
+		Type:      "base64",
+		MediaType: mediaType,
+		Data:      data,
+	}, nil
+}
+
+// textContent extracts plain text from message content, for places such as
+// the system prompt where Anthropic only accepts text.
+func textContent(content interface{}) (string, error) {
+	switch v := content.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []gollmx.ContentPart:
+		var sb strings.Builder
+		for _, part := range v {
+			if part.Type != "text" {
+				return "", gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+					fmt.Sprintf("system prompt only supports text content, got %s", part.Type))
+			}
+			sb.WriteString(part.Text)
+		}
+		return sb.String(), nil
+	default:
+		return "", gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported message content type: %T", content))
+	}
 }
 
 func (c *Client) convertResponse(resp *anthropicResponse) *gollmx.ChatResponse {
@@ -523,12 +751,7 @@ func (c *Client) convertResponse(resp *anthropicResponse) *gollmx.ChatResponse {
 		ToolCalls: toolCalls,
 	}
 
-	finishReason := resp.StopReason
-	if finishReason == "end_turn" {
-		finishReason = "stop"
-	} else if finishReason == "tool_use" {
-		finishReason = "tool_calls"
-	}
+	finishReason := convertStopReason(resp.StopReason)
 
 	return &gollmx.ChatResponse{
 		ID:       resp.ID,
@@ -550,3 +773,25 @@ func (c *Client) convertResponse(resp *anthropicResponse) *gollmx.ChatResponse {
 		Raw: resp,
 	}
 }
+
+// convertStopReason maps an Anthropic stop reason to the OpenAI-style
+// finish reasons used across providers
+func convertStopReason(stopReason string) string {
+	switch stopReason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return stopReason
+	}
+}
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -295,3 +296,143 @@ func TestChatWithSystemPrompt(t *testing.T) {
 		t.Fatalf("chat failed: %v", err)
 	}
 }
+
+func TestConvertRequestImageContent(t *testing.T) {
+	client, _ := New()
+	anthropicClient := client.(*Client)
+
+	png := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+
+	req, err := anthropicClient.convertRequest(&gollmx.ChatRequest{
+		Model: "claude-3-5-sonnet-20241022",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+				gollmx.TextContent("What is in these images?"),
+				gollmx.ImageURLContent("https://example.com/cat.jpg", ""),
+				// Declared media type is wrong; sniffing should correct it
+				gollmx.ImageURLContent("data:image/jpeg;base64,"+png, ""),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertRequest failed: %v", err)
+	}
+
+	blocks, ok := req.Messages[0].Content.([]anthropicContentBlock)
+	if !ok {
+		t.Fatalf("expected content blocks, got %T", req.Messages[0].Content)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(blocks))
+	}
+
+	if blocks[0].Type != "text" || blocks[0].Text != "What is in these images?" {
+		t.Errorf("unexpected text block: %+v", blocks[0])
+	}
+
+	if blocks[1].Type != "image" || blocks[1].Source == nil {
+		t.Fatalf("expected image block, got %+v", blocks[1])
+	}
+	if blocks[1].Source.Type != "url" || blocks[1].Source.URL != "https://example.com/cat.jpg" {
+		t.Errorf("unexpected url source: %+v", blocks[1].Source)
+	}
+
+	if blocks[2].Source == nil || blocks[2].Source.Type != "base64" {
+		t.Fatalf("expected base64 source, got %+v", blocks[2].Source)
+	}
+	if blocks[2].Source.MediaType != "image/png" {
+		t.Errorf("expected sniffed media type 'image/png', got '%s'", blocks[2].Source.MediaType)
+	}
+	if blocks[2].Source.Data != png {
+		t.Error("expected data URL payload to be passed through")
+	}
+}
+
+func TestConvertRequestToolResultWithImage(t *testing.T) {
+	client, _ := New()
+	anthropicClient := client.(*Client)
+
+	png := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+
+	req, err := anthropicClient.convertRequest(&gollmx.ChatRequest{
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleTool, ToolCallID: "toolu_1", Content: []gollmx.ContentPart{
+				gollmx.TextContent("screenshot captured"),
+				{Type: "image_base64", ImageURL: &gollmx.ImageURL{URL: png}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertRequest failed: %v", err)
+	}
+
+	blocks := req.Messages[0].Content.([]anthropicContentBlock)
+	if blocks[0].Type != "tool_result" || blocks[0].ToolUseID != "toolu_1" {
+		t.Fatalf("unexpected tool_result block: %+v", blocks[0])
+	}
+
+	inner, ok := blocks[0].Content.([]anthropicContentBlock)
+	if !ok || len(inner) != 2 {
+		t.Fatalf("expected 2 nested blocks, got %#v", blocks[0].Content)
+	}
+	if inner[1].Type != "image" || inner[1].Source.MediaType != "image/png" {
+		t.Errorf("unexpected nested image block: %+v", inner[1])
+	}
+}
+
+func TestConvertRequestInvalidContent(t *testing.T) {
+	client, _ := New()
+	anthropicClient := client.(*Client)
+
+	_, err := anthropicClient.convertRequest(&gollmx.ChatRequest{
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+				gollmx.ImageURLContent("data:application/pdf;base64,JVBERi0xLjQK", ""),
+			}},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error for non-image data")
+	}
+
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok {
+		t.Fatalf("expected APIError, got %T", err)
+	}
+	if apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid request error, got %s", apiErr.Type)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/models" {
+			t.Errorf("expected GET /models, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("expected the API key header, got %q", r.Header.Get("x-api-key"))
+		}
+		if r.URL.Query().Get("after_id") == "" {
+			w.Write([]byte(`{"data":[{"type":"model","id":"claude-3-5-sonnet-20241022","display_name":"Claude 3.5 Sonnet (New)","created_at":"2024-10-22T00:00:00Z"}],"has_more":true,"last_id":"claude-3-5-sonnet-20241022"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"type":"model","id":"claude-new-model","display_name":"Claude New","created_at":"2025-05-14T00:00:00Z"}],"has_more":false}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithAPIKey("test-key"), gollmx.WithBaseURL(server.URL))
+	models, err := client.(gollmx.ModelLister).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+	if models[0].Name != "Claude 3.5 Sonnet" || models[0].InputPrice != 3.00 || models[0].ReleaseDate != "2024-10-22" {
+		t.Errorf("expected static metadata with the live release date, got %+v", models[0])
+	}
+	if models[1].ID != "claude-new-model" || models[1].Name != "Claude New" {
+		t.Errorf("expected the live-only model, got %+v", models[1])
+	}
+}
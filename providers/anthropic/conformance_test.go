@@ -0,0 +1,14 @@
+package anthropic
+
+import (
+	"testing"
+
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+func TestConformance(t *testing.T) {
+	server := gollmxtest.NewAnthropicServer()
+	defer server.Close()
+
+	gollmxtest.RunConformance(t, New, server)
+}
@@ -32,14 +32,15 @@ type anthropicContentBlock struct {
 	Name      string                 `json:"name,omitempty"`
 	Input     json.RawMessage        `json:"input,omitempty"`
 	ToolUseID string                 `json:"tool_use_id,omitempty"`
-	Content   string                 `json:"content,omitempty"`
+	Content   interface{}            `json:"content,omitempty"` // string or []anthropicContentBlock (tool_result)
 	Source    *anthropicImageSource  `json:"source,omitempty"`
 }
 
 type anthropicImageSource struct {
-	Type      string `json:"type"`
-	MediaType string `json:"media_type"`
-	Data      string `json:"data"`
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 type anthropicTool struct {
@@ -84,6 +85,7 @@ type anthropicStreamEvent struct {
 	ContentBlock *anthropicContentBlock `json:"content_block,omitempty"`
 	Delta        *anthropicDelta        `json:"delta,omitempty"`
 	Usage        *anthropicUsage        `json:"usage,omitempty"`
+	Error        *anthropicError        `json:"error,omitempty"`
 }
 
 type anthropicDelta struct {
@@ -94,6 +96,18 @@ type anthropicDelta struct {
 	StopSequence string `json:"stop_sequence,omitempty"`
 }
 
+type anthropicModelList struct {
+	Data    []anthropicModel `json:"data"`
+	HasMore bool             `json:"has_more"`
+	LastID  string           `json:"last_id"`
+}
+
+type anthropicModel struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	CreatedAt   string `json:"created_at"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
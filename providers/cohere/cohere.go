@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	gollmx "github.com/onlyhyde/gollm-x"
@@ -19,10 +21,21 @@ const (
 	ProviderName   = "Cohere"
 	DefaultBaseURL = "https://api.cohere.ai/v1"
 	DefaultModel   = "command-r-plus"
+
+	DefaultRerankModel = "rerank-v3.5"
+)
+
+// API versions selectable with SetOption(OptionAPIVersion, ...)
+const (
+	APIVersionV1 = "v1" // Legacy chat API (message + chat_history + preamble)
+	APIVersionV2 = "v2" // OpenAI-style messages with native tool calls and citations
+
+	OptionAPIVersion = "api_version"
 )
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, CohereModels)
 }
 
 // Client implements the gollmx.LLM interface for Cohere
@@ -30,6 +43,7 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
 }
 
 // New creates a new Cohere client
@@ -37,7 +51,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 	config := gollmx.DefaultConfig()
 	config.Apply(opts...)
 
-	baseURL := config.BaseURL
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
 	if baseURL == "" {
 		baseURL = DefaultBaseURL
 	}
@@ -45,7 +59,8 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 	client := &Client{
 		config:  config,
 		baseURL: baseURL,
-		options: make(map[string]interface{}),
+		options: map[string]interface{}{OptionAPIVersion: APIVersionV2},
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -71,6 +86,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns the list of available models
 func (c *Client) Models() []gollmx.Model {
 	return CohereModels
@@ -86,11 +106,99 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from /v1/models,
+// merged with CohereModels for pricing. Features of models missing from
+// CohereModels are derived from their endpoints and reported features.
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	var live []gollmx.Model
+	pageToken := ""
+	for {
+		query := url.Values{"page_size": {"1000"}}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", c.endpoint(APIVersionV1, "/models?"+query.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		c.setHeaders(httpReq)
+
+		resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+		if err != nil {
+			return nil, c.handleError(err, 0, nil)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, c.handleError(nil, resp.StatusCode, respBody)
+		}
+
+		var page modelList
+		if err := json.Unmarshal(respBody, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		for _, m := range page.Models {
+			live = append(live, gollmx.Model{
+				ID:            m.Name,
+				Name:          m.Name,
+				Provider:      ProviderID,
+				ContextWindow: m.ContextLength,
+				Features:      m.features(),
+			})
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return gollmx.MergeModels(live, CohereModels), nil
+}
+
+// features converts a model's endpoints and reported features to gollmx
+// features
+func (m modelInfo) features() []gollmx.Feature {
+	var features []gollmx.Feature
+	for _, endpoint := range m.Endpoints {
+		switch endpoint {
+		case "chat":
+			features = append(features, gollmx.FeatureChat, gollmx.FeatureStreaming, gollmx.FeatureSystemPrompt)
+		case "generate":
+			features = append(features, gollmx.FeatureCompletion)
+		case "embed":
+			features = append(features, gollmx.FeatureEmbedding)
+		case "rerank":
+			features = append(features, gollmx.FeatureRerank)
+		}
+	}
+	for _, feature := range m.Features {
+		switch feature {
+		case "tools":
+			features = append(features, gollmx.FeatureTools)
+		case "vision":
+			features = append(features, gollmx.FeatureVision)
+		case "json_mode":
+			features = append(features, gollmx.FeatureJSON)
+		}
+	}
+	return features
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
 	case gollmx.FeatureChat, gollmx.FeatureStreaming, gollmx.FeatureTools,
-		gollmx.FeatureSystemPrompt, gollmx.FeatureEmbedding:
+		gollmx.FeatureSystemPrompt, gollmx.FeatureEmbedding, gollmx.FeatureRerank:
 		return true
 	}
 	return false
@@ -104,11 +212,16 @@ func (c *Client) Features() []gollmx.Feature {
 		gollmx.FeatureTools,
 		gollmx.FeatureSystemPrompt,
 		gollmx.FeatureEmbedding,
+		gollmx.FeatureRerank,
 	}
 }
 
 // SetOption sets a provider-specific option
 func (c *Client) SetOption(key string, value interface{}) error {
+	if key == OptionAPIVersion && value != APIVersionV1 && value != APIVersionV2 {
+		return gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported API version: %v", value))
+	}
 	c.options[key] = value
 	return nil
 }
@@ -119,6 +232,27 @@ func (c *Client) GetOption(key string) (interface{}, bool) {
 	return v, ok
 }
 
+// apiVersion returns the API version used for requests
+func (c *Client) apiVersion() string {
+	if v, ok := c.options[OptionAPIVersion].(string); ok {
+		return v
+	}
+	return APIVersionV2
+}
+
+// endpoint builds the URL for path under the given API version. A version
+// segment at the end of the base URL, as in DefaultBaseURL, is replaced.
+// Any other base URL, e.g. a gateway prefix, is used as is, so it must
+// route to the selected API version itself.
+func (c *Client) endpoint(version, path string) string {
+	for _, v := range []string{APIVersionV1, APIVersionV2} {
+		if root, ok := strings.CutSuffix(c.baseURL, "/"+v); ok {
+			return root + "/" + version + path
+		}
+	}
+	return c.baseURL + path
+}
+
 // =============================================================================
 // Chat
 // =============================================================================
@@ -132,21 +266,32 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
-	cohereReq := c.convertChatRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
+	if c.apiVersion() == APIVersionV2 {
+		return c.chatV2(ctx, req)
+	}
+
+	cohereReq, err := c.convertChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(cohereReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(APIVersionV1, "/chat"), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -178,7 +323,18 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
-	cohereReq := c.convertChatRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
+	if c.apiVersion() == APIVersionV2 {
+		return c.chatStreamV2(ctx, req)
+	}
+
+	cohereReq, err := c.convertChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
 	cohereReq.Stream = true
 
 	body, err := json.Marshal(cohereReq)
@@ -186,14 +342,14 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(APIVersionV1, "/chat"), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -214,6 +370,7 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 	defer close(ch)
 	defer body.Close()
 
+	var generationID string
 	scanner := bufio.NewScanner(body)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -232,11 +389,16 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 		}
 
 		switch event.EventType {
+		case "stream-start":
+			generationID = event.GenerationID
+			continue
 		case "text-generation":
 			gollmxChunk.Content = event.Text
+		case "tool-calls-generation":
+			gollmxChunk.ToolCalls = convertResponseToolCalls(event.ToolCalls, generationID)
 		case "stream-end":
 			if event.Response != nil {
-				gollmxChunk.FinishReason = string(event.Response.FinishReason)
+				gollmxChunk.FinishReason = convertFinishReason(string(event.Response.FinishReason))
 				if event.Response.Meta != nil && event.Response.Meta.Tokens != nil {
 					gollmxChunk.Usage = gollmx.Usage{
 						PromptTokens:     event.Response.Meta.Tokens.InputTokens,
@@ -305,10 +467,33 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		req.Model = "embed-english-v3.0"
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.FeatureEmbedding); err != nil {
+		return nil, err
+	}
+
+	version := c.apiVersion()
+
+	encoding := req.EncodingFormat
+	if encoding == "" {
+		encoding = gollmx.EmbedEncodingFloat
+	}
+
 	cohereReq := embedRequest{
 		Model:     req.Model,
 		Texts:     req.Input,
-		InputType: "search_document",
+		InputType: convertInputType(req.InputType),
+	}
+	switch {
+	case version == APIVersionV2:
+		if encoding == gollmx.EmbedEncodingBase64 {
+			return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				fmt.Sprintf("unsupported embedding encoding: %s", encoding))
+		}
+		cohereReq.EmbeddingTypes = []string{string(encoding)}
+		cohereReq.OutputDimension = req.Dimensions
+	case encoding != gollmx.EmbedEncodingFloat || req.Dimensions > 0:
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			"embedding encodings and dimensions require the v2 API")
 	}
 
 	body, err := json.Marshal(cohereReq)
@@ -316,14 +501,14 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/embed", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(version, "/embed"), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -338,17 +523,25 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		return nil, c.handleError(nil, resp.StatusCode, respBody)
 	}
 
-	var cohereResp embedResponse
-	if err := json.Unmarshal(respBody, &cohereResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	var vectors [][]float64
+	var meta embedMeta
+	if version == APIVersionV2 {
+		var cohereResp embedResponseV2
+		if err := json.Unmarshal(respBody, &cohereResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		vectors, meta = cohereResp.Embeddings.byType(encoding), cohereResp.Meta
+	} else {
+		var cohereResp embedResponse
+		if err := json.Unmarshal(respBody, &cohereResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		vectors, meta = cohereResp.Embeddings, cohereResp.Meta
 	}
 
-	embeddings := make([]gollmx.Embedding, len(cohereResp.Embeddings))
-	for i, emb := range cohereResp.Embeddings {
-		embeddings[i] = gollmx.Embedding{
-			Index:  i,
-			Vector: emb,
-		}
+	embeddings := make([]gollmx.Embedding, len(vectors))
+	for i, emb := range vectors {
+		embeddings[i] = gollmx.NewEmbedding(i, encoding, emb)
 	}
 
 	return &gollmx.EmbedResponse{
@@ -356,11 +549,107 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		Model:      req.Model,
 		Embeddings: embeddings,
 		Usage: gollmx.Usage{
-			TotalTokens: cohereResp.Meta.BilledUnits.InputTokens,
+			TotalTokens: meta.BilledUnits.InputTokens,
 		},
 	}, nil
 }
 
+// convertInputType maps an input type to Cohere's input_type, which v3 and
+// later models require. Types Cohere has no equivalent for embed as documents.
+func convertInputType(inputType gollmx.EmbedInputType) string {
+	switch inputType {
+	case gollmx.EmbedInputSearchQuery, gollmx.EmbedInputClassification, gollmx.EmbedInputClustering:
+		return string(inputType)
+	default:
+		return string(gollmx.EmbedInputSearchDocument)
+	}
+}
+
+// byType returns the embeddings of the requested type
+func (e embedsByType) byType(encoding gollmx.EmbedEncoding) [][]float64 {
+	switch encoding {
+	case gollmx.EmbedEncodingInt8:
+		return e.Int8
+	case gollmx.EmbedEncodingUint8:
+		return e.Uint8
+	case gollmx.EmbedEncodingBinary:
+		return e.Binary
+	case gollmx.EmbedEncodingUbinary:
+		return e.Ubinary
+	default:
+		return e.Float
+	}
+}
+
+// =============================================================================
+// Rerank
+// =============================================================================
+
+// Rerank orders documents by relevance to the query
+func (c *Client) Rerank(ctx context.Context, req *gollmx.RerankRequest) (*gollmx.RerankResponse, error) {
+	if req.Model == "" {
+		req.Model = DefaultRerankModel
+	}
+
+	cohereReq := rerankRequest{
+		Model:     req.Model,
+		Query:     req.Query,
+		Documents: req.Documents,
+		TopN:      req.TopN,
+	}
+
+	body, err := json.Marshal(cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(c.apiVersion(), "/rerank"), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	var cohereResp rerankResponse
+	if err := json.Unmarshal(respBody, &cohereResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	results := make([]gollmx.RerankResult, 0, len(cohereResp.Results))
+	for _, r := range cohereResp.Results {
+		result := gollmx.RerankResult{
+			Index: r.Index,
+			Score: r.RelevanceScore,
+		}
+		if r.Index >= 0 && r.Index < len(req.Documents) {
+			result.Document = req.Documents[r.Index]
+		}
+		results = append(results, result)
+	}
+
+	return &gollmx.RerankResponse{
+		Provider: ProviderID,
+		Model:    req.Model,
+		Results:  results,
+		Raw:      &cohereResp,
+	}, nil
+}
+
 // =============================================================================
 // Helpers
 // =============================================================================
@@ -416,7 +705,7 @@ func (c *Client) handleError(err error, statusCode int, body []byte) error {
 	return apiErr
 }
 
-func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *chatRequest {
+func (c *Client) convertChatRequest(req *gollmx.ChatRequest) (*chatRequest, error) {
 	cohereReq := &chatRequest{
 		Model:       req.Model,
 		MaxTokens:   req.MaxTokens,
@@ -425,32 +714,75 @@ func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *chatRequest {
 		StopSequences: req.Stop,
 	}
 
-	// Extract system message and chat history
+	// Tool results only carry the call ID, but Cohere wants the original call
+	calls := make(map[string]toolCall)
+
 	var chatHistory []chatMessage
-	for _, m := range req.Messages {
+	for _, m := range withToolCallIDs(req.Messages) {
 		switch m.Role {
 		case gollmx.RoleSystem:
-			cohereReq.Preamble = m.Content.(string)
+			text, err := textContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			cohereReq.Preamble = text
 		case gollmx.RoleUser:
-			if content, ok := m.Content.(string); ok {
-				// Last user message is the current message
-				cohereReq.Message = content
+			text, err := textContent(m.Content)
+			if err != nil {
+				return nil, err
 			}
 			chatHistory = append(chatHistory, chatMessage{
 				Role:    "USER",
-				Message: m.Content.(string),
+				Message: text,
 			})
 		case gollmx.RoleAssistant:
-			chatHistory = append(chatHistory, chatMessage{
+			text, err := textContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			msg := chatMessage{
 				Role:    "CHATBOT",
-				Message: m.Content.(string),
-			})
+				Message: text,
+			}
+			for _, tc := range m.ToolCalls {
+				call, err := convertToolCall(tc)
+				if err != nil {
+					return nil, err
+				}
+				calls[tc.ID] = call
+				msg.ToolCalls = append(msg.ToolCalls, call)
+			}
+			chatHistory = append(chatHistory, msg)
+		case gollmx.RoleTool:
+			result, err := convertToolResult(m, calls)
+			if err != nil {
+				return nil, err
+			}
+			// Consecutive tool results belong to the same turn
+			if n := len(chatHistory); n > 0 && chatHistory[n-1].Role == "TOOL" {
+				chatHistory[n-1].ToolResults = append(chatHistory[n-1].ToolResults, result)
+			} else {
+				chatHistory = append(chatHistory, chatMessage{
+					Role:        "TOOL",
+					ToolResults: []toolResult{result},
+				})
+			}
+		default:
+			return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				fmt.Sprintf("unsupported message role: %s", m.Role))
 		}
 	}
 
-	// Remove last user message from history (it's the current message)
-	if len(chatHistory) > 0 && chatHistory[len(chatHistory)-1].Role == "USER" {
-		chatHistory = chatHistory[:len(chatHistory)-1]
+	// The final user message or tool results form the current turn
+	if n := len(chatHistory); n > 0 {
+		switch last := chatHistory[n-1]; last.Role {
+		case "USER":
+			cohereReq.Message = last.Message
+			chatHistory = chatHistory[:n-1]
+		case "TOOL":
+			cohereReq.ToolResults = last.ToolResults
+			chatHistory = chatHistory[:n-1]
+		}
 	}
 
 	if len(chatHistory) > 0 {
@@ -488,11 +820,116 @@ func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *chatRequest {
 		}
 	}
 
-	return cohereReq
+	return cohereReq, nil
+}
+
+// textContent extracts plain text from message content. Cohere chat only
+// accepts text, so any non-text content part is rejected.
+func textContent(content interface{}) (string, error) {
+	switch v := content.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []gollmx.ContentPart:
+		var sb strings.Builder
+		for _, part := range v {
+			if part.Type != "text" {
+				return "", gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+					fmt.Sprintf("content part type %s is not supported by Cohere", part.Type))
+			}
+			sb.WriteString(part.Text)
+		}
+		return sb.String(), nil
+	default:
+		return "", gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported message content type: %T", content))
+	}
+}
+
+// convertToolCall converts a gollmx tool call into a Cohere call with parsed parameters
+func convertToolCall(tc gollmx.ToolCall) (toolCall, error) {
+	call := toolCall{
+		Name:       tc.Function.Name,
+		Parameters: map[string]interface{}{},
+	}
+	if tc.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(tc.Function.Arguments), &call.Parameters); err != nil {
+			return toolCall{}, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				fmt.Sprintf("tool call %s has invalid JSON arguments: %v", tc.ID, err))
+		}
+	}
+	return call, nil
+}
+
+// convertToolResult converts a tool message into a Cohere tool result.
+// Outputs must be JSON objects, so other content is wrapped as {"result": ...}.
+func convertToolResult(m gollmx.Message, calls map[string]toolCall) (toolResult, error) {
+	call, ok := calls[m.ToolCallID]
+	if !ok {
+		if m.Name == "" {
+			return toolResult{}, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+				fmt.Sprintf("tool result %s does not match any previous tool call", m.ToolCallID))
+		}
+		call = toolCall{Name: m.Name, Parameters: map[string]interface{}{}}
+	}
+
+	text, err := textContent(m.Content)
+	if err != nil {
+		return toolResult{}, err
+	}
+
+	var outputs []map[string]interface{}
+	var object map[string]interface{}
+	if json.Unmarshal([]byte(text), &object) == nil && object != nil {
+		outputs = []map[string]interface{}{object}
+	} else if json.Unmarshal([]byte(text), &outputs) != nil || outputs == nil {
+		outputs = []map[string]interface{}{{"result": text}}
+	}
+
+	return toolResult{Call: call, Outputs: outputs}, nil
+}
+
+// withToolCallIDs returns messages with an ID on every tool call and tool
+// result. IDs from the original response are kept. A missing call ID is
+// generated from the message and call index, so it is unique across the
+// conversation, and a tool result without an ID answers the next
+// unanswered call of the preceding assistant turn.
+func withToolCallIDs(messages []gollmx.Message) []gollmx.Message {
+	result := make([]gollmx.Message, len(messages))
+	var pending []string
+	for i, m := range messages {
+		switch m.Role {
+		case gollmx.RoleAssistant:
+			pending = nil
+			if len(m.ToolCalls) > 0 {
+				m.ToolCalls = append([]gollmx.ToolCall(nil), m.ToolCalls...)
+			}
+			for j := range m.ToolCalls {
+				if m.ToolCalls[j].ID == "" {
+					m.ToolCalls[j].ID = fmt.Sprintf("call_%d_%d", i, j)
+				}
+				pending = append(pending, m.ToolCalls[j].ID)
+			}
+		case gollmx.RoleTool:
+			if m.ToolCallID == "" && len(pending) > 0 {
+				m.ToolCallID = pending[0]
+			}
+			for j, id := range pending {
+				if id == m.ToolCallID {
+					pending = append(pending[:j:j], pending[j+1:]...)
+					break
+				}
+			}
+		}
+		result[i] = m
+	}
+	return result
 }
 
 func (c *Client) convertChatResponse(resp *chatResponse, model string) *gollmx.ChatResponse {
 	content := resp.Text
+	toolCalls := convertResponseToolCalls(resp.ToolCalls, resp.GenerationID)
 
 	chatResp := &gollmx.ChatResponse{
 		ID:       resp.GenerationID,
@@ -502,10 +939,11 @@ func (c *Client) convertChatResponse(resp *chatResponse, model string) *gollmx.C
 			{
 				Index: 0,
 				Message: gollmx.Message{
-					Role:    gollmx.RoleAssistant,
-					Content: content,
+					Role:      gollmx.RoleAssistant,
+					Content:   content,
+					ToolCalls: toolCalls,
 				},
-				FinishReason: string(resp.FinishReason),
+				FinishReason: convertFinishReason(string(resp.FinishReason)),
 			},
 		},
 		Raw: resp,
@@ -521,3 +959,52 @@ func (c *Client) convertChatResponse(resp *chatResponse, model string) *gollmx.C
 
 	return chatResp
 }
+
+// convertFinishReason maps Cohere finish reasons to gollmx finish reasons
+func convertFinishReason(reason string) string {
+	switch finishReason(reason) {
+	case FinishReasonComplete, FinishReasonStopSequence:
+		return "stop"
+	case FinishReasonMaxTokens:
+		return "length"
+	case FinishReasonToolCall:
+		return "tool_calls"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+// convertResponseToolCalls converts Cohere tool calls, which carry no IDs,
+// into gollmx tool calls. IDs combine the generation ID with the call's
+// position, so calls from different turns of a conversation do not clash.
+func convertResponseToolCalls(calls []toolCall, generationID string) []gollmx.ToolCall {
+	prefix := "call"
+	if generationID != "" {
+		prefix = generationID
+	}
+
+	var toolCalls []gollmx.ToolCall
+	for i, call := range calls {
+		args := []byte("{}")
+		if call.Parameters != nil {
+			args, _ = json.Marshal(call.Parameters)
+		}
+		toolCalls = append(toolCalls, gollmx.ToolCall{
+			ID:   fmt.Sprintf("%s_%d", prefix, i),
+			Type: "function",
+			Function: gollmx.FunctionCall{
+				Name:      call.Name,
+				Arguments: string(args),
+			},
+		})
+	}
+	return toolCalls
+}
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.Reranker     = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
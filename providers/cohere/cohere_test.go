@@ -0,0 +1,659 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func TestNew(t *testing.T) {
+	client, err := New()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if client.ID() != ProviderID {
+		t.Errorf("expected ID '%s', got '%s'", ProviderID, client.ID())
+	}
+
+	if client.BaseURL() != DefaultBaseURL {
+		t.Errorf("expected base URL '%s', got '%s'", DefaultBaseURL, client.BaseURL())
+	}
+
+	if v, _ := client.GetOption(OptionAPIVersion); v != APIVersionV2 {
+		t.Errorf("expected default API version '%s', got '%v'", APIVersionV2, v)
+	}
+}
+
+func TestSetAPIVersion(t *testing.T) {
+	client, _ := New()
+
+	if err := client.SetOption(OptionAPIVersion, APIVersionV1); err != nil {
+		t.Fatalf("failed to set API version: %v", err)
+	}
+	if v, _ := client.GetOption(OptionAPIVersion); v != APIVersionV1 {
+		t.Errorf("expected API version '%s', got '%v'", APIVersionV1, v)
+	}
+
+	if err := client.SetOption(OptionAPIVersion, "v3"); err == nil {
+		t.Error("expected error for unsupported API version")
+	}
+}
+
+func TestCustomBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(chatResponse{GenerationID: "gen_1", Text: "Hi", FinishReason: FinishReasonComplete})
+	}))
+	defer server.Close()
+
+	// A version at the end of the base URL is replaced by the selected one
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/cohere/v2"), gollmx.WithAPIKey("test"))
+	client.SetOption(OptionAPIVersion, APIVersionV1)
+
+	req := &gollmx.ChatRequest{
+		Model:    "command-r-plus",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+	}
+	if _, err := client.Chat(context.Background(), req); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	if len(paths) != 1 || paths[0] != "/cohere/v1/chat" {
+		t.Errorf("expected path /cohere/v1/chat, got %v", paths)
+	}
+}
+
+func TestUnversionedBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/embed") {
+			json.NewEncoder(w).Encode(embedResponseV2{Embeddings: embedsByType{Float: [][]float64{{0.1, 0.2}}}})
+			return
+		}
+		json.NewEncoder(w).Encode(chatResponseV2{
+			ID:           "chat_1",
+			FinishReason: "COMPLETE",
+			Message:      responseMessageV2{Role: "assistant", Content: []contentV2{{Type: "text", Text: "Hi"}}},
+		})
+	}))
+	defer server.Close()
+
+	// A gateway prefix without a version is used as is, as it was before
+	// the API version could be selected
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/cohere"), gollmx.WithAPIKey("test"))
+	ctx := context.Background()
+
+	_, err := client.Chat(ctx, &gollmx.ChatRequest{
+		Model:    "command-r-plus",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if _, err := client.Embed(ctx, &gollmx.EmbedRequest{Input: []string{"Hi"}}); err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+
+	expected := []string{"/cohere/chat", "/cohere/embed"}
+	if len(paths) != 2 || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+}
+
+func TestDefaultBaseURLUsesV2(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	if got := cohereClient.endpoint(APIVersionV2, "/chat"); got != "https://api.cohere.ai/v2/chat" {
+		t.Errorf("expected the v2 chat endpoint, got '%s'", got)
+	}
+}
+
+func TestConvertChatRequest(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	req, err := cohereClient.convertChatRequest(&gollmx.ChatRequest{
+		Model: "command-r-plus",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleSystem, Content: "You are helpful"},
+			{Role: gollmx.RoleUser, Content: "Hi"},
+			{Role: gollmx.RoleAssistant, Content: "Hello!"},
+			{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+				gollmx.TextContent("How are "),
+				gollmx.TextContent("you?"),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertChatRequest failed: %v", err)
+	}
+
+	if req.Preamble != "You are helpful" {
+		t.Errorf("unexpected preamble: %s", req.Preamble)
+	}
+	if req.Message != "How are you?" {
+		t.Errorf("unexpected message: %s", req.Message)
+	}
+	if len(req.ChatHistory) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(req.ChatHistory))
+	}
+	if req.ChatHistory[1].Role != "CHATBOT" || req.ChatHistory[1].Message != "Hello!" {
+		t.Errorf("unexpected history entry: %+v", req.ChatHistory[1])
+	}
+}
+
+func TestConvertChatRequestToolHistory(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	req, err := cohereClient.convertChatRequest(&gollmx.ChatRequest{
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: "Weather in Paris and Rome?"},
+			{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+				{ID: "call_0", Type: "function", Function: gollmx.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "call_1", Type: "function", Function: gollmx.FunctionCall{Name: "get_weather", Arguments: `{"city":"Rome"}`}},
+			}},
+			{Role: gollmx.RoleTool, ToolCallID: "call_0", Content: `{"temp":18}`},
+			{Role: gollmx.RoleTool, ToolCallID: "call_1", Content: "sunny"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertChatRequest failed: %v", err)
+	}
+
+	if req.Message != "" {
+		t.Errorf("expected empty message for tool turn, got %q", req.Message)
+	}
+	if len(req.ToolResults) != 2 {
+		t.Fatalf("expected 2 tool results, got %d", len(req.ToolResults))
+	}
+
+	first := req.ToolResults[0]
+	if first.Call.Name != "get_weather" || first.Call.Parameters["city"] != "Paris" {
+		t.Errorf("unexpected call: %+v", first.Call)
+	}
+	if first.Outputs[0]["temp"] != float64(18) {
+		t.Errorf("expected JSON object output, got %v", first.Outputs)
+	}
+	if req.ToolResults[1].Outputs[0]["result"] != "sunny" {
+		t.Errorf("expected wrapped text output, got %v", req.ToolResults[1].Outputs)
+	}
+
+	if len(req.ChatHistory) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(req.ChatHistory))
+	}
+	chatbot := req.ChatHistory[1]
+	if chatbot.Role != "CHATBOT" || len(chatbot.ToolCalls) != 2 {
+		t.Errorf("expected CHATBOT entry with tool calls, got %+v", chatbot)
+	}
+}
+
+func TestConvertChatRequestToolCallIDs(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	// Two tool turns whose calls and results carry no IDs
+	messages := []gollmx.Message{
+		{Role: gollmx.RoleUser, Content: "Weather and time in Paris?"},
+		{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+			{Type: "function", Function: gollmx.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		}},
+		{Role: gollmx.RoleTool, Content: "sunny"},
+		{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+			{Type: "function", Function: gollmx.FunctionCall{Name: "get_time", Arguments: `{"city":"Paris"}`}},
+		}},
+		{Role: gollmx.RoleTool, Content: "noon"},
+	}
+
+	withIDs := withToolCallIDs(messages)
+	first, second := withIDs[1].ToolCalls[0].ID, withIDs[3].ToolCalls[0].ID
+	if first == "" || first == second {
+		t.Errorf("expected unique generated IDs, got %q and %q", first, second)
+	}
+	if withIDs[2].ToolCallID != first || withIDs[4].ToolCallID != second {
+		t.Errorf("expected results to answer their turn's call, got %q and %q", withIDs[2].ToolCallID, withIDs[4].ToolCallID)
+	}
+	if messages[1].ToolCalls[0].ID != "" || messages[2].ToolCallID != "" {
+		t.Error("expected the request messages to be left unchanged")
+	}
+
+	req, err := cohereClient.convertChatRequest(&gollmx.ChatRequest{Messages: messages})
+	if err != nil {
+		t.Fatalf("convertChatRequest failed: %v", err)
+	}
+	if len(req.ToolResults) != 1 || req.ToolResults[0].Call.Name != "get_time" {
+		t.Errorf("expected the current result to answer get_time, got %+v", req.ToolResults)
+	}
+	if history := req.ChatHistory[2]; history.Role != "TOOL" || history.ToolResults[0].Call.Name != "get_weather" {
+		t.Errorf("expected the earlier result to answer get_weather, got %+v", history)
+	}
+}
+
+func TestConvertChatRequestUnsupportedContent(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	tests := []struct {
+		name string
+		msg  gollmx.Message
+	}{
+		{"image", gollmx.Message{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+			gollmx.ImageURLContent("https://example.com/cat.png", ""),
+		}}},
+		{"unknown type", gollmx.Message{Role: gollmx.RoleSystem, Content: 42}},
+		{"bad arguments", gollmx.Message{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+			{ID: "call_0", Function: gollmx.FunctionCall{Name: "f", Arguments: "{"}},
+		}}},
+		{"orphan tool result", gollmx.Message{Role: gollmx.RoleTool, ToolCallID: "missing", Content: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cohereClient.convertChatRequest(&gollmx.ChatRequest{
+				Messages: []gollmx.Message{tt.msg},
+			})
+			apiErr, ok := err.(*gollmx.APIError)
+			if !ok {
+				t.Fatalf("expected APIError, got %T (%v)", err, err)
+			}
+			if apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+				t.Errorf("expected invalid request error, got %s", apiErr.Type)
+			}
+		})
+	}
+}
+
+func TestChatV1ToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat" {
+			t.Errorf("expected path '/v1/chat', got '%s'", r.URL.Path)
+		}
+
+		response := chatResponse{
+			GenerationID: "gen_123",
+			FinishReason: FinishReasonComplete,
+			ToolCalls: []toolCall{
+				{Name: "get_weather", Parameters: map[string]interface{}{"city": "Paris"}},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/v1"), gollmx.WithAPIKey("test"))
+	client.SetOption(OptionAPIVersion, APIVersionV1)
+
+	resp, err := client.Chat(context.Background(), &gollmx.ChatRequest{
+		Model:    "command-r-plus",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "Weather?"}},
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	if resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish reason 'stop', got '%s'", resp.Choices[0].FinishReason)
+	}
+
+	toolCalls := resp.GetToolCalls()
+	if len(toolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(toolCalls))
+	}
+	if toolCalls[0].Function.Name != "get_weather" || toolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool call: %+v", toolCalls[0])
+	}
+	// IDs are unique to the generation, so later turns do not reuse them
+	if toolCalls[0].ID != "gen_123_0" {
+		t.Errorf("expected ID 'gen_123_0', got '%s'", toolCalls[0].ID)
+	}
+}
+
+func TestChatV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/chat" {
+			t.Errorf("expected path '/v2/chat', got '%s'", r.URL.Path)
+		}
+
+		var req chatRequestV2
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if len(req.Messages) != 2 || req.Messages[0].Role != "system" {
+			t.Errorf("expected system and user messages, got %+v", req.Messages)
+		}
+		if len(req.Documents) != 1 || req.Documents[0].ID != "faq-1" {
+			t.Errorf("expected documents to be forwarded, got %+v", req.Documents)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+			t.Errorf("expected json_object response format, got %+v", req.ResponseFormat)
+		}
+
+		response := chatResponseV2{
+			ID:           "chat_123",
+			FinishReason: "COMPLETE",
+			Message: responseMessageV2{
+				Role:    "assistant",
+				Content: []contentV2{{Type: "text", Text: "Returns are free within 30 days."}},
+				Citations: []citationV2{{
+					Start: 0,
+					End:   12,
+					Text:  "Returns are free",
+					Sources: []sourceV2{{
+						Type:     "document",
+						ID:       "faq-1",
+						Document: map[string]interface{}{"text": "Free returns for 30 days"},
+					}},
+				}},
+			},
+			Usage: &usageV2{Tokens: &tokens{InputTokens: 20, OutputTokens: 8}},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/v2"), gollmx.WithAPIKey("test"))
+
+	resp, err := client.Chat(context.Background(), &gollmx.ChatRequest{
+		Model: "command-r-plus",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleSystem, Content: "Answer from the documents"},
+			{Role: gollmx.RoleUser, Content: "What is the return policy?"},
+		},
+		Documents: []gollmx.Document{
+			{ID: "faq-1", Data: map[string]interface{}{"text": "Free returns for 30 days"}},
+		},
+		ResponseFormat: &gollmx.ResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	if resp.GetContent() != "Returns are free within 30 days." {
+		t.Errorf("unexpected content: %s", resp.GetContent())
+	}
+	if resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish reason 'stop', got '%s'", resp.Choices[0].FinishReason)
+	}
+	if resp.Usage.TotalTokens != 28 {
+		t.Errorf("expected 28 total tokens, got %d", resp.Usage.TotalTokens)
+	}
+
+	if len(resp.Citations) != 1 || resp.Citations[0].Sources[0] != "faq-1" {
+		t.Fatalf("unexpected citations: %+v", resp.Citations)
+	}
+	if len(resp.Documents) != 1 || resp.Documents[0].Data["text"] != "Free returns for 30 days" {
+		t.Errorf("unexpected documents: %+v", resp.Documents)
+	}
+}
+
+func TestChatV2ToolHistory(t *testing.T) {
+	client, _ := New()
+	cohereClient := client.(*Client)
+
+	req, err := cohereClient.convertChatRequestV2(&gollmx.ChatRequest{
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: "Weather in Paris?"},
+			{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+				{ID: "tc_1", Type: "function", Function: gollmx.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			}},
+			{Role: gollmx.RoleTool, ToolCallID: "tc_1", Content: `{"temp":18}`},
+			{Role: gollmx.RoleAssistant, ToolCalls: []gollmx.ToolCall{
+				{Type: "function", Function: gollmx.FunctionCall{Name: "get_time", Arguments: `{"city":"Paris"}`}},
+			}},
+			{Role: gollmx.RoleTool, Content: "noon"},
+		},
+		ToolChoice: "required",
+	})
+	if err != nil {
+		t.Fatalf("convertChatRequestV2 failed: %v", err)
+	}
+
+	if len(req.Messages) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(req.Messages))
+	}
+	if req.Messages[1].ToolCalls[0].ID != "tc_1" {
+		t.Errorf("expected tool call ID to be preserved, got %+v", req.Messages[1].ToolCalls)
+	}
+	if req.Messages[2].Role != "tool" || req.Messages[2].ToolCallID != "tc_1" {
+		t.Errorf("unexpected tool message: %+v", req.Messages[2])
+	}
+	// A call without an ID gets one unique to the conversation
+	generated := req.Messages[3].ToolCalls[0].ID
+	if generated == "" || generated == "tc_1" || req.Messages[4].ToolCallID != generated {
+		t.Errorf("expected a generated ID shared by the call and its result, got %+v and %+v", req.Messages[3], req.Messages[4])
+	}
+	if req.ToolChoice != "REQUIRED" {
+		t.Errorf("expected tool choice 'REQUIRED', got '%s'", req.ToolChoice)
+	}
+}
+
+func TestChatStreamV2(t *testing.T) {
+	events := []string{
+		`{"type":"message-start","id":"chat_1"}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Hello"}}}}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":" world"}}}}`,
+		`{"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"tc_1","type":"function","function":{"name":"lookup","arguments":""}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{\"q\":1}"}}}}}`,
+		`{"type":"tool-call-end","index":0}`,
+		`{"type":"citation-start","index":0,"delta":{"message":{"citations":{"start":0,"end":5,"text":"Hello","sources":[{"type":"document","id":"doc:0","document":{"text":"Hello there"}}]}}}}`,
+		`{"type":"citation-start","index":1,"delta":{"message":{"citations":{"start":6,"end":11,"text":"world","sources":[{"type":"document","id":"doc:0","document":{"text":"Hello there"}}]}}}}`,
+		`{"type":"message-end","delta":{"finish_reason":"TOOL_CALL","usage":{"tokens":{"input_tokens":5,"output_tokens":3}}}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			w.Write([]byte("event: x\ndata: " + e + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test"))
+
+	stream, err := client.ChatStream(context.Background(), &gollmx.ChatRequest{
+		Model:    "command-r-plus",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	if resp.GetContent() != "Hello world" {
+		t.Errorf("unexpected content: %q", resp.GetContent())
+	}
+	if resp.ID != "chat_1" {
+		t.Errorf("expected ID 'chat_1', got '%s'", resp.ID)
+	}
+	if resp.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish reason 'tool_calls', got '%s'", resp.Choices[0].FinishReason)
+	}
+	toolCalls := resp.GetToolCalls()
+	if len(toolCalls) != 1 || toolCalls[0].Function.Arguments != `{"q":1}` {
+		t.Errorf("unexpected tool calls: %+v", toolCalls)
+	}
+	if len(resp.Citations) != 2 || resp.Citations[0].Sources[0] != "doc:0" {
+		t.Errorf("unexpected citations: %+v", resp.Citations)
+	}
+	// Cited documents are reported once, as in non-streamed responses
+	if len(resp.Documents) != 1 || resp.Documents[0].Data["text"] != "Hello there" {
+		t.Errorf("unexpected documents: %+v", resp.Documents)
+	}
+	if resp.Usage.TotalTokens != 8 {
+		t.Errorf("expected 8 total tokens, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestEmbedV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/embed" {
+			t.Errorf("expected path '/v2/embed', got '%s'", r.URL.Path)
+		}
+
+		var req embedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.EmbeddingTypes) != 1 || req.EmbeddingTypes[0] != "float" {
+			t.Errorf("expected float embedding type, got %v", req.EmbeddingTypes)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(embedResponseV2{
+			Embeddings: embedsByType{Float: [][]float64{{0.1, 0.2}, {0.3, 0.4}}},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/v2"), gollmx.WithAPIKey("test"))
+
+	resp, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
+		Input: []string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1].Vector[1] != 0.4 {
+		t.Errorf("unexpected embeddings: %+v", resp.Embeddings)
+	}
+}
+
+func TestEmbedQuantized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.InputType != "search_query" {
+			t.Errorf("expected input_type 'search_query', got '%s'", req.InputType)
+		}
+		if len(req.EmbeddingTypes) != 1 || req.EmbeddingTypes[0] != "int8" {
+			t.Errorf("expected int8 embedding type, got %v", req.EmbeddingTypes)
+		}
+		if req.OutputDimension != 256 {
+			t.Errorf("expected output_dimension 256, got %d", req.OutputDimension)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"embeddings": {"int8": [[-128, 0, 127]]}}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test"))
+
+	resp, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
+		Input:          []string{"query"},
+		InputType:      gollmx.EmbedInputSearchQuery,
+		Dimensions:     256,
+		EncodingFormat: gollmx.EmbedEncodingInt8,
+	})
+	if err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+
+	emb := resp.Embeddings[0]
+	if len(emb.Int8) != 3 || emb.Int8[0] != -128 || emb.Int8[2] != 127 || emb.Vector != nil {
+		t.Errorf("expected int8 embedding [-128 0 127], got %+v", emb)
+	}
+}
+
+func TestRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/rerank" {
+			t.Errorf("expected path '/v2/rerank', got '%s'", r.URL.Path)
+		}
+
+		var req rerankRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != DefaultRerankModel || req.TopN != 1 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rerankResponse{
+			ID:      "rr_1",
+			Results: []rerankResult{{Index: 1, RelevanceScore: 0.92}},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL+"/v2"), gollmx.WithAPIKey("test"))
+
+	if !client.HasFeature(gollmx.FeatureRerank) {
+		t.Error("should support rerank feature")
+	}
+
+	reranker, ok := client.(gollmx.Reranker)
+	if !ok {
+		t.Fatal("client should implement gollmx.Reranker")
+	}
+
+	resp, err := reranker.Rerank(context.Background(), &gollmx.RerankRequest{
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany", "Paris is the capital of France"},
+		TopN:      1,
+	})
+	if err != nil {
+		t.Fatalf("rerank failed: %v", err)
+	}
+
+	if len(resp.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(resp.Results))
+	}
+	if resp.Results[0].Document != "Paris is the capital of France" || resp.Results[0].Score != 0.92 {
+		t.Errorf("unexpected result: %+v", resp.Results[0])
+	}
+}
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/models" {
+			t.Errorf("expected GET /v1/models, got %s %s", r.Method, r.URL.Path)
+		}
+		token := r.URL.Query().Get("page_token")
+		if token == "" {
+			w.Write([]byte(`{"models":[
+				{"name":"command-r","endpoints":["chat","generate"],"context_length":128000},
+				{"name":"command-a-03-2025","endpoints":["chat"],"features":["tools","json_mode"],"context_length":256000}
+			],"next_page_token":"a+b/c="}`))
+			return
+		}
+		if token != "a+b/c=" {
+			t.Errorf("expected the page token to be escaped, got %q", token)
+		}
+		w.Write([]byte(`{"models":[{"name":"rerank-v3.5","endpoints":["rerank"],"context_length":4096}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithAPIKey("test-key"), gollmx.WithBaseURL(server.URL+"/v2"))
+	models, err := client.(gollmx.ModelLister).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 3 {
+		t.Fatalf("expected 3 models, got %d", len(models))
+	}
+	if models[0].ID != "command-r" || models[0].InputPrice == 0 {
+		t.Errorf("expected command-r with static pricing, got %+v", models[0])
+	}
+	live := models[1]
+	if live.ContextWindow != 256000 || !live.SupportsFeature(gollmx.FeatureTools) || !live.SupportsFeature(gollmx.FeatureJSON) || !live.SupportsFeature(gollmx.FeatureChat) {
+		t.Errorf("expected features derived from endpoints and features, got %+v", live)
+	}
+}
@@ -0,0 +1,14 @@
+package cohere
+
+import (
+	"testing"
+
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+func TestConformance(t *testing.T) {
+	server := gollmxtest.NewCohereServer()
+	defer server.Close()
+
+	gollmxtest.RunConformance(t, New, server)
+}
@@ -18,6 +18,7 @@ var CohereModels = []gollmx.Model{
 			gollmx.FeatureChat,
 			gollmx.FeatureStreaming,
 			gollmx.FeatureTools,
+			gollmx.FeatureJSON,
 			gollmx.FeatureSystemPrompt,
 		},
 		ReleaseDate: "2024-04-04",
@@ -35,6 +36,7 @@ var CohereModels = []gollmx.Model{
 			gollmx.FeatureChat,
 			gollmx.FeatureStreaming,
 			gollmx.FeatureTools,
+			gollmx.FeatureJSON,
 			gollmx.FeatureSystemPrompt,
 		},
 		ReleaseDate: "2024-03-11",
@@ -121,4 +123,38 @@ var CohereModels = []gollmx.Model{
 		},
 		ReleaseDate: "2023-11-02",
 	},
+	// Rerank models
+	{
+		ID:            "rerank-v3.5",
+		Name:          "Rerank v3.5",
+		Provider:      ProviderID,
+		Description:   "Multilingual reranking model, billed per search",
+		ContextWindow: 4096,
+		Features: []gollmx.Feature{
+			gollmx.FeatureRerank,
+		},
+		ReleaseDate: "2024-12-02",
+	},
+	{
+		ID:            "rerank-english-v3.0",
+		Name:          "Rerank English v3.0",
+		Provider:      ProviderID,
+		Description:   "English reranking model, billed per search",
+		ContextWindow: 4096,
+		Features: []gollmx.Feature{
+			gollmx.FeatureRerank,
+		},
+		ReleaseDate: "2024-04-11",
+	},
+	{
+		ID:            "rerank-multilingual-v3.0",
+		Name:          "Rerank Multilingual v3.0",
+		Provider:      ProviderID,
+		Description:   "Multilingual reranking model, billed per search",
+		ContextWindow: 4096,
+		Features: []gollmx.Feature{
+			gollmx.FeatureRerank,
+		},
+		ReleaseDate: "2024-04-11",
+	},
 }
@@ -1,5 +1,7 @@
 package cohere
 
+import "encoding/json"
+
 // =============================================================================
 // Request Types
 // =============================================================================
@@ -15,11 +17,19 @@ type chatRequest struct {
 	StopSequences []string      `json:"stop_sequences,omitempty"`
 	Stream        bool          `json:"stream,omitempty"`
 	Tools         []tool        `json:"tools,omitempty"`
+	ToolResults   []toolResult  `json:"tool_results,omitempty"`
 }
 
 type chatMessage struct {
-	Role    string `json:"role"`
-	Message string `json:"message"`
+	Role        string       `json:"role"` // "USER", "CHATBOT", "SYSTEM", "TOOL"
+	Message     string       `json:"message,omitempty"`
+	ToolCalls   []toolCall   `json:"tool_calls,omitempty"`
+	ToolResults []toolResult `json:"tool_results,omitempty"`
+}
+
+type toolResult struct {
+	Call    toolCall                 `json:"call"`
+	Outputs []map[string]interface{} `json:"outputs"`
 }
 
 type tool struct {
@@ -35,9 +45,11 @@ type parameterDef struct {
 }
 
 type embedRequest struct {
-	Model     string   `json:"model"`
-	Texts     []string `json:"texts"`
-	InputType string   `json:"input_type"`
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types,omitempty"`  // Required by v2
+	OutputDimension int      `json:"output_dimension,omitempty"` // v2, embed-v4 and later
 }
 
 // =============================================================================
@@ -88,9 +100,11 @@ type toolCall struct {
 }
 
 type streamEvent struct {
-	EventType string        `json:"event_type"`
-	Text      string        `json:"text,omitempty"`
-	Response  *chatResponse `json:"response,omitempty"`
+	EventType    string        `json:"event_type"`
+	GenerationID string        `json:"generation_id,omitempty"`
+	Text         string        `json:"text,omitempty"`
+	ToolCalls    []toolCall    `json:"tool_calls,omitempty"`
+	Response     *chatResponse `json:"response,omitempty"`
 }
 
 type embedResponse struct {
@@ -104,6 +118,184 @@ type embedMeta struct {
 	BilledUnits billedUnits `json:"billed_units"`
 }
 
+type embedResponseV2 struct {
+	ID         string       `json:"id"`
+	Embeddings embedsByType `json:"embeddings"`
+	Meta       embedMeta    `json:"meta"`
+}
+
+// embedsByType holds one list of embeddings per requested type. Integer
+// types are decoded as floats and converted by convertEmbedding.
+type embedsByType struct {
+	Float   [][]float64 `json:"float,omitempty"`
+	Int8    [][]float64 `json:"int8,omitempty"`
+	Uint8   [][]float64 `json:"uint8,omitempty"`
+	Binary  [][]float64 `json:"binary,omitempty"`
+	Ubinary [][]float64 `json:"ubinary,omitempty"`
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type rerankResponse struct {
+	ID      string         `json:"id"`
+	Results []rerankResult `json:"results"`
+	Meta    *rerankMeta    `json:"meta,omitempty"`
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type rerankMeta struct {
+	BilledUnits *searchUnits `json:"billed_units,omitempty"`
+}
+
+type searchUnits struct {
+	SearchUnits int `json:"search_units"`
+}
+
+type modelList struct {
+	Models        []modelInfo `json:"models"`
+	NextPageToken string      `json:"next_page_token"`
+}
+
+type modelInfo struct {
+	Name          string   `json:"name"`
+	Endpoints     []string `json:"endpoints"`
+	Features      []string `json:"features"`
+	ContextLength int      `json:"context_length"`
+}
+
+// =============================================================================
+// V2 Chat Types
+// =============================================================================
+
+type chatRequestV2 struct {
+	Model          string            `json:"model"`
+	Messages       []messageV2       `json:"messages"`
+	Documents      []documentV2      `json:"documents,omitempty"`
+	MaxTokens      int               `json:"max_tokens,omitempty"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+	P              *float64          `json:"p,omitempty"`
+	StopSequences  []string          `json:"stop_sequences,omitempty"`
+	Stream         bool              `json:"stream,omitempty"`
+	Tools          []toolV2          `json:"tools,omitempty"`
+	ToolChoice     string            `json:"tool_choice,omitempty"` // "REQUIRED" or "NONE"
+	ResponseFormat *responseFormatV2 `json:"response_format,omitempty"`
+}
+
+type messageV2 struct {
+	Role       string       `json:"role"`              // "system", "user", "assistant", "tool"
+	Content    interface{}  `json:"content,omitempty"` // string or []contentV2
+	ToolCalls  []toolCallV2 `json:"tool_calls,omitempty"`
+	ToolCallID string       `json:"tool_call_id,omitempty"`
+	ToolPlan   string       `json:"tool_plan,omitempty"`
+}
+
+type contentV2 struct {
+	Type     string      `json:"type"` // "text", "image_url"
+	Text     string      `json:"text,omitempty"`
+	ImageURL *imageURLV2 `json:"image_url,omitempty"`
+}
+
+type imageURLV2 struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type documentV2 struct {
+	ID   string                 `json:"id,omitempty"`
+	Data map[string]interface{} `json:"data"`
+}
+
+type toolV2 struct {
+	Type     string     `json:"type"`
+	Function functionV2 `json:"function"`
+}
+
+type functionV2 struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type toolCallV2 struct {
+	ID       string         `json:"id,omitempty"`
+	Type     string         `json:"type,omitempty"`
+	Function functionCallV2 `json:"function"`
+}
+
+type functionCallV2 struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type responseFormatV2 struct {
+	Type       string          `json:"type"` // "text" or "json_object"
+	JSONSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+type chatResponseV2 struct {
+	ID           string            `json:"id"`
+	FinishReason string            `json:"finish_reason"`
+	Message      responseMessageV2 `json:"message"`
+	Usage        *usageV2          `json:"usage,omitempty"`
+}
+
+type responseMessageV2 struct {
+	Role      string       `json:"role"`
+	Content   []contentV2  `json:"content,omitempty"`
+	ToolPlan  string       `json:"tool_plan,omitempty"`
+	ToolCalls []toolCallV2 `json:"tool_calls,omitempty"`
+	Citations []citationV2 `json:"citations,omitempty"`
+}
+
+type citationV2 struct {
+	Start   int        `json:"start"`
+	End     int        `json:"end"`
+	Text    string     `json:"text"`
+	Sources []sourceV2 `json:"sources,omitempty"`
+}
+
+type sourceV2 struct {
+	Type       string                 `json:"type"` // "document" or "tool"
+	ID         string                 `json:"id"`
+	Document   map[string]interface{} `json:"document,omitempty"`
+	ToolOutput map[string]interface{} `json:"tool_output,omitempty"`
+}
+
+type usageV2 struct {
+	BilledUnits *billedUnits `json:"billed_units,omitempty"`
+	Tokens      *tokens      `json:"tokens,omitempty"`
+}
+
+// streamEventV2 is a server-sent event from the v2 chat stream
+type streamEventV2 struct {
+	Type  string         `json:"type"`
+	ID    string         `json:"id,omitempty"`
+	Index int            `json:"index"`
+	Delta *streamDeltaV2 `json:"delta,omitempty"`
+}
+
+type streamDeltaV2 struct {
+	Message      *streamMessageV2 `json:"message,omitempty"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	Usage        *usageV2         `json:"usage,omitempty"`
+}
+
+type streamMessageV2 struct {
+	Content   *contentV2  `json:"content,omitempty"`
+	ToolPlan  string      `json:"tool_plan,omitempty"`
+	ToolCalls *toolCallV2 `json:"tool_calls,omitempty"`
+	Citations *citationV2 `json:"citations,omitempty"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
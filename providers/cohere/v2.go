@@ -0,0 +1,433 @@
+package cohere
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// =============================================================================
+// V2 Chat
+// =============================================================================
+
+func (c *Client) chatV2(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.ChatResponse, error) {
+	cohereReq, err := c.convertChatRequestV2(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(APIVersionV2, "/chat"), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	var cohereResp chatResponseV2
+	if err := json.Unmarshal(respBody, &cohereResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return c.convertChatResponseV2(&cohereResp, req.Model), nil
+}
+
+func (c *Client) chatStreamV2(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.StreamReader, error) {
+	cohereReq, err := c.convertChatRequestV2(req)
+	if err != nil {
+		return nil, err
+	}
+	cohereReq.Stream = true
+
+	body, err := json.Marshal(cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(APIVersionV2, "/chat"), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	ch := make(chan gollmx.StreamChunk)
+	go c.readStreamV2(resp.Body, ch, req.Model)
+
+	return gollmx.NewStreamReader(ch), nil
+}
+
+func (c *Client) readStreamV2(body io.ReadCloser, ch chan gollmx.StreamChunk, model string) {
+	defer close(ch)
+	defer body.Close()
+
+	var messageID string
+	var currentToolCall *gollmx.ToolCall
+	seen := make(map[string]bool) // Documents already sent
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+
+		var event streamEventV2
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			ch <- gollmx.StreamChunk{Error: err}
+			return
+		}
+
+		chunk := gollmx.StreamChunk{
+			ID:       messageID,
+			Provider: ProviderID,
+			Model:    model,
+		}
+
+		var msg *streamMessageV2
+		if event.Delta != nil {
+			msg = event.Delta.Message
+		}
+
+		switch event.Type {
+		case "message-start":
+			messageID = event.ID
+			continue
+		case "content-delta":
+			if msg == nil || msg.Content == nil {
+				continue
+			}
+			chunk.Content = msg.Content.Text
+		case "tool-call-start":
+			if msg == nil || msg.ToolCalls == nil {
+				continue
+			}
+			currentToolCall = &gollmx.ToolCall{
+				ID:   msg.ToolCalls.ID,
+				Type: "function",
+				Function: gollmx.FunctionCall{
+					Name:      msg.ToolCalls.Function.Name,
+					Arguments: msg.ToolCalls.Function.Arguments,
+				},
+			}
+			continue
+		case "tool-call-delta":
+			if currentToolCall != nil && msg != nil && msg.ToolCalls != nil {
+				currentToolCall.Function.Arguments += msg.ToolCalls.Function.Arguments
+			}
+			continue
+		case "tool-call-end":
+			if currentToolCall == nil {
+				continue
+			}
+			chunk.ToolCalls = []gollmx.ToolCall{*currentToolCall}
+			currentToolCall = nil
+		case "citation-start":
+			if msg == nil || msg.Citations == nil {
+				continue
+			}
+			citation, docs := convertCitationV2(*msg.Citations)
+			chunk.Citations = []gollmx.Citation{citation}
+			for _, doc := range docs {
+				if !seen[doc.ID] {
+					seen[doc.ID] = true
+					chunk.Documents = append(chunk.Documents, doc)
+				}
+			}
+		case "message-end":
+			if event.Delta != nil {
+				chunk.FinishReason = convertFinishReason(event.Delta.FinishReason)
+				chunk.Usage = convertUsageV2(event.Delta.Usage)
+			}
+		default:
+			// content-start, content-end, tool-plan-delta, citation-end
+			continue
+		}
+
+		ch <- chunk
+	}
+
+	if err := scanner.Err(); err != nil {
+		ch <- gollmx.StreamChunk{Error: err}
+	}
+}
+
+// =============================================================================
+// V2 Conversion
+// =============================================================================
+
+func (c *Client) convertChatRequestV2(req *gollmx.ChatRequest) (*chatRequestV2, error) {
+	cohereReq := &chatRequestV2{
+		Model:         req.Model,
+		MaxTokens:     req.MaxTokens,
+		Temperature:   req.Temperature,
+		P:             req.TopP,
+		StopSequences: req.Stop,
+	}
+
+	// Cohere matches tool results to calls by ID
+	for _, m := range withToolCallIDs(req.Messages) {
+		msg, err := convertMessageV2(m)
+		if err != nil {
+			return nil, err
+		}
+		cohereReq.Messages = append(cohereReq.Messages, msg)
+	}
+
+	for _, doc := range req.Documents {
+		cohereReq.Documents = append(cohereReq.Documents, documentV2{
+			ID:   doc.ID,
+			Data: doc.Data,
+		})
+	}
+
+	for _, t := range req.Tools {
+		cohereReq.Tools = append(cohereReq.Tools, toolV2{
+			Type: "function",
+			Function: functionV2{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  t.Function.Parameters,
+			},
+		})
+	}
+
+	// Cohere only supports forcing or disabling tool use
+	if choice, ok := req.ToolChoice.(string); ok {
+		switch choice {
+		case "required":
+			cohereReq.ToolChoice = "REQUIRED"
+		case "none":
+			cohereReq.ToolChoice = "NONE"
+		}
+	}
+
+	if req.ResponseFormat != nil {
+		switch req.ResponseFormat.Type {
+		case "json_object":
+			cohereReq.ResponseFormat = &responseFormatV2{Type: "json_object"}
+		case "json_schema":
+			cohereReq.ResponseFormat = &responseFormatV2{Type: "json_object"}
+			if req.ResponseFormat.JSONSchema != nil {
+				cohereReq.ResponseFormat.JSONSchema = req.ResponseFormat.JSONSchema.Schema
+			}
+		}
+	}
+
+	return cohereReq, nil
+}
+
+func convertMessageV2(m gollmx.Message) (messageV2, error) {
+	msg := messageV2{
+		Role:       string(m.Role),
+		ToolCallID: m.ToolCallID,
+	}
+
+	switch m.Role {
+	case gollmx.RoleSystem, gollmx.RoleTool:
+		text, err := textContent(m.Content)
+		if err != nil {
+			return messageV2{}, err
+		}
+		msg.Content = text
+	case gollmx.RoleUser, gollmx.RoleAssistant:
+		content, err := convertContentV2(m.Content)
+		if err != nil {
+			return messageV2{}, err
+		}
+		msg.Content = content
+		for _, tc := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, toolCallV2{
+				ID:   tc.ID,
+				Type: "function",
+				Function: functionCallV2{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+		}
+	default:
+		return messageV2{}, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported message role: %s", m.Role))
+	}
+
+	return msg, nil
+}
+
+// convertContentV2 converts message content into a string or v2 content blocks
+func convertContentV2(content interface{}) (interface{}, error) {
+	switch v := content.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []gollmx.ContentPart:
+		blocks := make([]contentV2, 0, len(v))
+		for _, part := range v {
+			switch part.Type {
+			case "text":
+				blocks = append(blocks, contentV2{Type: "text", Text: part.Text})
+			case "image_url", "image_base64":
+				if part.ImageURL == nil {
+					continue
+				}
+				url := part.ImageURL.URL
+				if part.Type == "image_base64" && !strings.HasPrefix(url, "data:") {
+					url = fmt.Sprintf("data:%s;base64,%s", gollmx.DetectBase64MediaType(url), url)
+				}
+				blocks = append(blocks, contentV2{
+					Type:     "image_url",
+					ImageURL: &imageURLV2{URL: url, Detail: part.ImageURL.Detail},
+				})
+			default:
+				return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+					fmt.Sprintf("content part type %s is not supported by Cohere", part.Type))
+			}
+		}
+		return blocks, nil
+	default:
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported message content type: %T", content))
+	}
+}
+
+func (c *Client) convertChatResponseV2(resp *chatResponseV2, model string) *gollmx.ChatResponse {
+	var content strings.Builder
+	for _, block := range resp.Message.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	var toolCalls []gollmx.ToolCall
+	for _, tc := range resp.Message.ToolCalls {
+		toolCalls = append(toolCalls, gollmx.ToolCall{
+			ID:   tc.ID,
+			Type: "function",
+			Function: gollmx.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+
+	chatResp := &gollmx.ChatResponse{
+		ID:       resp.ID,
+		Provider: ProviderID,
+		Model:    model,
+		Created:  time.Now().Unix(),
+		Choices: []gollmx.Choice{
+			{
+				Index: 0,
+				Message: gollmx.Message{
+					Role:      gollmx.RoleAssistant,
+					Content:   content.String(),
+					ToolCalls: toolCalls,
+				},
+				FinishReason: convertFinishReason(resp.FinishReason),
+			},
+		},
+		Usage: convertUsageV2(resp.Usage),
+		Raw:   resp,
+	}
+
+	// Collect each cited document once, in order of first citation
+	seen := make(map[string]bool)
+	for _, cit := range resp.Message.Citations {
+		citation, docs := convertCitationV2(cit)
+		chatResp.Citations = append(chatResp.Citations, citation)
+		for _, doc := range docs {
+			if !seen[doc.ID] {
+				seen[doc.ID] = true
+				chatResp.Documents = append(chatResp.Documents, doc)
+			}
+		}
+	}
+
+	return chatResp
+}
+
+// convertCitationV2 converts a v2 citation, returning the documents it cites
+func convertCitationV2(cit citationV2) (gollmx.Citation, []gollmx.Document) {
+	citation := gollmx.Citation{
+		Start: cit.Start,
+		End:   cit.End,
+		Text:  cit.Text,
+	}
+
+	var docs []gollmx.Document
+	for _, src := range cit.Sources {
+		citation.Sources = append(citation.Sources, src.ID)
+		if src.Type == "document" {
+			docs = append(docs, gollmx.Document{ID: src.ID, Data: src.Document})
+		}
+	}
+
+	return citation, docs
+}
+
+func convertUsageV2(usage *usageV2) gollmx.Usage {
+	if usage == nil {
+		return gollmx.Usage{}
+	}
+
+	counts := usage.Tokens
+	if counts == nil && usage.BilledUnits != nil {
+		counts = &tokens{
+			InputTokens:  usage.BilledUnits.InputTokens,
+			OutputTokens: usage.BilledUnits.OutputTokens,
+		}
+	}
+	if counts == nil {
+		return gollmx.Usage{}
+	}
+
+	return gollmx.Usage{
+		PromptTokens:     counts.InputTokens,
+		CompletionTokens: counts.OutputTokens,
+		TotalTokens:      counts.InputTokens + counts.OutputTokens,
+	}
+}
@@ -0,0 +1,14 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+func TestConformance(t *testing.T) {
+	server := gollmxtest.NewGeminiServer()
+	defer server.Close()
+
+	gollmxtest.RunConformance(t, NewClient, server)
+}
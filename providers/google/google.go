@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -20,6 +21,7 @@ const (
 	ProviderID     = "google"
 	ProviderName   = "Google Gemini"
 	DefaultBaseURL = "https://generativelanguage.googleapis.com"
+	DefaultModel   = "gemini-1.5-flash"
 	ClientVersion  = "1.0.0"
 )
 
@@ -29,10 +31,12 @@ type Client struct {
 	httpClient *http.Client
 	baseURL    string
 	options    map[string]interface{}
+	models     *gollmx.ModelCache
 }
 
 func init() {
 	gollmx.Register(ProviderID, NewClient)
+	gollmx.RegisterModels(ProviderID, GeminiModels)
 }
 
 // NewClient creates a new Google Gemini client
@@ -59,9 +63,10 @@ func NewClient(opts ...gollmx.Option) (gollmx.LLM, error) {
 
 	return &Client{
 		config:     config,
-		httpClient: config.GetHTTPClient(),
+		httpClient: config.HTTPClientFor(ProviderID),
 		baseURL:    baseURL,
 		options:    make(map[string]interface{}),
+		models:     gollmx.NewModelCache(config.ModelCacheTTL),
 	}, nil
 }
 
@@ -71,6 +76,9 @@ func (c *Client) Name() string    { return ProviderName }
 func (c *Client) Version() string { return ClientVersion }
 func (c *Client) BaseURL() string { return c.baseURL }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config { return c.config }
+
 // Models returns all available Gemini models
 func (c *Client) Models() []gollmx.Model {
 	return GeminiModels
@@ -86,9 +94,96 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from models.list,
+// merged with GeminiModels for pricing. Features of models missing from
+// GeminiModels are derived from their supported generation methods.
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	var live []gollmx.Model
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {"1000"}, "key": {c.config.APIKey}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/v1beta/models?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		c.setHeaders(httpReq)
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, networkError(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var page geminiModelList
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, m := range page.Models {
+			live = append(live, gollmx.Model{
+				ID:            strings.TrimPrefix(m.Name, "models/"),
+				Name:          m.DisplayName,
+				Provider:      ProviderID,
+				Description:   m.Description,
+				ContextWindow: m.InputTokenLimit,
+				MaxOutput:     m.OutputTokenLimit,
+				Features:      m.features(),
+			})
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return gollmx.MergeModels(live, GeminiModels), nil
+}
+
+// features converts supported generation methods to gollmx features
+func (m geminiModel) features() []gollmx.Feature {
+	var features []gollmx.Feature
+	for _, method := range m.SupportedGenerationMethods {
+		switch method {
+		case "generateContent":
+			features = append(features, gollmx.FeatureChat, gollmx.FeatureStreaming, gollmx.FeatureSystemPrompt)
+		case "embedContent":
+			features = append(features, gollmx.FeatureEmbedding)
+		}
+	}
+	return features
+}
+
 // Chat sends a chat request to Gemini's generateContent API
 func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.ChatResponse, error) {
-	geminiReq := c.convertChatRequest(req)
+	if req.Model == "" {
+		req.Model = c.config.DefaultModel
+		if req.Model == "" {
+			req.Model = DefaultModel
+		}
+	}
+
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
+	geminiReq, err := c.convertChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -123,7 +218,21 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 // ChatStream sends a streaming chat request
 func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.StreamReader, error) {
-	geminiReq := c.convertChatRequest(req)
+	if req.Model == "" {
+		req.Model = c.config.DefaultModel
+		if req.Model == "" {
+			req.Model = DefaultModel
+		}
+	}
+
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
+	geminiReq, err := c.convertChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -140,7 +249,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, networkError(err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -191,6 +300,15 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		model = "text-embedding-004"
 	}
 
+	if err := c.config.CheckModel(c, model, gollmx.FeatureEmbedding); err != nil {
+		return nil, err
+	}
+
+	if req.EncodingFormat != "" && req.EncodingFormat != gollmx.EmbedEncodingFloat {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported embedding encoding: %s", req.EncodingFormat))
+	}
+
 	// Use batch embedding for multiple inputs
 	var requests []geminiEmbedRequest
 	for _, text := range req.Input {
@@ -199,6 +317,8 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 			Content: geminiEmbedContent{
 				Parts: []geminiPart{{Text: text}},
 			},
+			TaskType:             convertTaskType(req.InputType),
+			OutputDimensionality: req.Dimensions,
 		})
 	}
 
@@ -218,7 +338,7 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, networkError(err)
 	}
 	defer resp.Body.Close()
 
@@ -246,6 +366,24 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 	}, nil
 }
 
+// convertTaskType maps an input type to a Gemini embedding task type
+func convertTaskType(inputType gollmx.EmbedInputType) string {
+	switch inputType {
+	case gollmx.EmbedInputSearchDocument:
+		return "RETRIEVAL_DOCUMENT"
+	case gollmx.EmbedInputSearchQuery:
+		return "RETRIEVAL_QUERY"
+	case gollmx.EmbedInputClassification:
+		return "CLASSIFICATION"
+	case gollmx.EmbedInputClustering:
+		return "CLUSTERING"
+	case gollmx.EmbedInputSimilarity:
+		return "SEMANTIC_SIMILARITY"
+	default:
+		return ""
+	}
+}
+
 // HasFeature checks if the provider supports a feature
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -302,6 +440,14 @@ func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
 
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
 		if attempt > 0 {
+			if logger := c.config.GetLogger(); logger != nil {
+				logger.Warn("retrying llm request",
+					"provider", ProviderID,
+					"attempt", attempt,
+					"max_retries", c.config.MaxRetries,
+					"error", lastErr.Error(),
+				)
+			}
 			time.Sleep(c.config.RetryDelay * time.Duration(attempt))
 
 			// Clone request for retry
@@ -323,8 +469,8 @@ func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
 			continue
 		}
 
-		// Don't retry on success or client errors
-		if resp.StatusCode < 500 {
+		// Don't retry on success, client errors or the final attempt
+		if resp.StatusCode < 500 || attempt == c.config.MaxRetries {
 			return resp, nil
 		}
 
@@ -333,23 +479,31 @@ func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
 		lastErr = fmt.Errorf("server error: %d", resp.StatusCode)
 	}
 
-	return nil, lastErr
+	return nil, networkError(lastErr)
+}
+
+func networkError(err error) error {
+	return &gollmx.APIError{
+		Type:     gollmx.ErrorTypeNetwork,
+		Provider: ProviderID,
+		Message:  err.Error(),
+	}
 }
 
 func (c *Client) handleErrorResponse(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
 
-	var errResp geminiErrorResponse
-	if err := json.Unmarshal(body, &errResp); err != nil {
-		return gollmx.NewAPIError(gollmx.ErrorTypeUnknown, ProviderID, string(body))
-	}
-
 	apiErr := &gollmx.APIError{
 		Provider:   ProviderID,
 		StatusCode: resp.StatusCode,
-		Message:    errResp.Error.Message,
-		Code:       errResp.Error.Status,
-		Raw:        errResp,
+		Message:    string(body),
+	}
+
+	var errResp geminiErrorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Code = errResp.Error.Status
+		apiErr.Raw = errResp
 	}
 
 	switch resp.StatusCode {
@@ -360,6 +514,8 @@ func (c *Client) handleErrorResponse(resp *http.Response) error {
 		apiErr.Retryable = true
 	case 400:
 		apiErr.Type = gollmx.ErrorTypeInvalidRequest
+	case 404:
+		apiErr.Type = gollmx.ErrorTypeModelNotFound
 	case 500, 502, 503:
 		apiErr.Type = gollmx.ErrorTypeServer
 		apiErr.Retryable = true
@@ -370,7 +526,7 @@ func (c *Client) handleErrorResponse(resp *http.Response) error {
 	return apiErr
 }
 
-func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *geminiGenerateRequest {
+func (c *Client) convertChatRequest(ctx context.Context, req *gollmx.ChatRequest) (*geminiGenerateRequest, error) {
 	var contents []geminiContent
 	var systemInstruction *geminiContent
 
@@ -382,10 +538,16 @@ func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *geminiGenerateRequ
 					Parts: []geminiPart{{Text: content}},
 				}
 			}
-		case gollmx.RoleUser:
-			contents = append(contents, c.convertMessage("user", msg))
-		case gollmx.RoleAssistant:
-			contents = append(contents, c.convertMessage("model", msg))
+		case gollmx.RoleUser, gollmx.RoleAssistant:
+			role := "user"
+			if msg.Role == gollmx.RoleAssistant {
+				role = "model"
+			}
+			content, err := c.convertMessage(ctx, role, msg)
+			if err != nil {
+				return nil, err
+			}
+			contents = append(contents, content)
 		case gollmx.RoleTool:
 			// Tool results
 			if content, ok := msg.Content.(string); ok {
@@ -441,10 +603,10 @@ func (c *Client) convertChatRequest(req *gollmx.ChatRequest) *geminiGenerateRequ
 		geminiReq.Tools = c.convertTools(req.Tools)
 	}
 
-	return geminiReq
+	return geminiReq, nil
 }
 
-func (c *Client) convertMessage(role string, msg gollmx.Message) geminiContent {
+func (c *Client) convertMessage(ctx context.Context, role string, msg gollmx.Message) (geminiContent, error) {
 	var parts []geminiPart
 
 	switch content := msg.Content.(type) {
@@ -456,17 +618,15 @@ func (c *Client) convertMessage(role string, msg gollmx.Message) geminiContent {
 			switch part.Type {
 			case "text":
 				parts = append(parts, geminiPart{Text: part.Text})
-			case "image_url":
-				if part.ImageURL != nil {
-					// Gemini requires base64 inline data or file URI
-					// For URL, we'd need to fetch and convert
-					parts = append(parts, geminiPart{
-						InlineData: &geminiInlineData{
-							MimeType: "image/jpeg", // Would need to detect
-							Data:     part.ImageURL.URL,
-						},
-					})
+			case "image_url", "image_base64":
+				if part.ImageURL == nil {
+					continue
 				}
+				imagePart, err := c.convertImage(ctx, part)
+				if err != nil {
+					return geminiContent{}, err
+				}
+				parts = append(parts, imagePart)
 			}
 		}
 	}
@@ -481,7 +641,53 @@ func (c *Client) convertMessage(role string, msg gollmx.Message) geminiContent {
 		})
 	}
 
-	return geminiContent{Role: role, Parts: parts}
+	return geminiContent{Role: role, Parts: parts}, nil
+}
+
+// convertImage converts an image content part into inline data or a file reference.
+// Gemini cannot fetch arbitrary URLs, so remote images are downloaded by the media resolver.
+func (c *Client) convertImage(ctx context.Context, part gollmx.ContentPart) (geminiPart, error) {
+	url := part.ImageURL.URL
+
+	if part.Type == "image_base64" {
+		if _, _, ok := gollmx.ParseDataURL(url); !ok {
+			// Raw base64 payload without a data URL header
+			return geminiPart{
+				InlineData: &geminiInlineData{
+					MimeType: gollmx.DetectBase64MediaType(url),
+					Data:     url,
+				},
+			}, nil
+		}
+	}
+
+	media, err := c.config.MediaResolverFor(ProviderID).Resolve(ctx, url)
+	if err != nil {
+		if apiErr, ok := err.(*gollmx.APIError); ok && apiErr.Provider == "" {
+			apiErr.Provider = ProviderID
+		}
+		return geminiPart{}, err
+	}
+
+	if media.IsReference() {
+		mimeType := media.MediaType
+		if mimeType == "" {
+			mimeType = "image/jpeg" // fileData requires a MIME type; image parts default to JPEG
+		}
+		return geminiPart{
+			FileData: &geminiFileData{
+				MimeType: mimeType,
+				FileURI:  media.URI,
+			},
+		}, nil
+	}
+
+	return geminiPart{
+		InlineData: &geminiInlineData{
+			MimeType: media.MediaType,
+			Data:     media.Data,
+		},
+	}, nil
 }
 
 func (c *Client) convertTools(tools []gollmx.Tool) []geminiTool {
@@ -528,6 +734,10 @@ func (c *Client) convertChatResponse(model string, resp *geminiGenerateResponse)
 		}
 
 		finishReason := c.convertFinishReason(candidate.FinishReason)
+		// Gemini finishes function calls with STOP
+		if len(toolCalls) > 0 && finishReason == "stop" {
+			finishReason = "tool_calls"
+		}
 
 		choices = append(choices, gollmx.Choice{
 			Index: candidate.Index,
@@ -580,6 +790,7 @@ func (c *Client) processStream(resp *http.Response, model string, ch chan<- goll
 	defer resp.Body.Close()
 
 	reader := bufio.NewReader(resp.Body)
+	toolCallCount := 0
 
 	for {
 		line, err := reader.ReadString('\n')
@@ -606,34 +817,55 @@ func (c *Client) processStream(resp *http.Response, model string, ch chan<- goll
 		}
 
 		for _, candidate := range geminiResp.Candidates {
-			if candidate.Content == nil {
-				continue
+			chunk := gollmx.StreamChunk{
+				ID:       fmt.Sprintf("gemini-%d", time.Now().UnixNano()),
+				Provider: ProviderID,
+				Model:    model,
 			}
 
-			for _, part := range candidate.Content.Parts {
-				if part.Text != "" {
-					chunk := gollmx.StreamChunk{
-						ID:       fmt.Sprintf("gemini-%d", time.Now().UnixNano()),
-						Provider: ProviderID,
-						Model:    model,
-						Content:  part.Text,
-					}
-
-					if candidate.FinishReason != "" {
-						chunk.FinishReason = c.convertFinishReason(candidate.FinishReason)
+			if candidate.Content != nil {
+				for _, part := range candidate.Content.Parts {
+					chunk.Content += part.Text
+					if part.FunctionCall != nil {
+						chunk.ToolCalls = append(chunk.ToolCalls, gollmx.ToolCall{
+							ID:   fmt.Sprintf("call_%d", toolCallCount),
+							Type: "function",
+							Function: gollmx.FunctionCall{
+								Name:      part.FunctionCall.Name,
+								Arguments: string(part.FunctionCall.Args),
+							},
+						})
+						toolCallCount++
 					}
+				}
+			}
 
-					if geminiResp.UsageMetadata != nil {
-						chunk.Usage = gollmx.Usage{
-							PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
-							CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
-							TotalTokens:      geminiResp.UsageMetadata.TotalTokenCount,
-						}
-					}
+			if candidate.FinishReason != "" {
+				chunk.FinishReason = c.convertFinishReason(candidate.FinishReason)
+				if toolCallCount > 0 && chunk.FinishReason == "stop" {
+					chunk.FinishReason = "tool_calls"
+				}
+			}
 
-					ch <- chunk
+			if geminiResp.UsageMetadata != nil {
+				chunk.Usage = gollmx.Usage{
+					PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
+					CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
+					TotalTokens:      geminiResp.UsageMetadata.TotalTokenCount,
 				}
 			}
+
+			if chunk.Content == "" && len(chunk.ToolCalls) == 0 && chunk.FinishReason == "" && chunk.Usage == (gollmx.Usage{}) {
+				continue
+			}
+			ch <- chunk
 		}
 	}
 }
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
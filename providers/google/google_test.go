@@ -2,6 +2,7 @@ package google
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -259,6 +260,37 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestEmbedTaskTypeAndDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiBatchEmbedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, r := range req.Requests {
+			if r.TaskType != "RETRIEVAL_DOCUMENT" {
+				t.Errorf("expected taskType 'RETRIEVAL_DOCUMENT', got '%s'", r.TaskType)
+			}
+			if r.OutputDimensionality != 128 {
+				t.Errorf("expected outputDimensionality 128, got %d", r.OutputDimensionality)
+			}
+		}
+
+		json.NewEncoder(w).Encode(geminiBatchEmbedResponse{
+			Embeddings: []geminiEmbedding{{Values: []float64{0.1}}, {Values: []float64{0.2}}},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test-key"))
+
+	_, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
+		Input:      []string{"a", "b"},
+		InputType:  gollmx.EmbedInputSearchDocument,
+		Dimensions: 128,
+	})
+	if err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+}
+
 func TestComplete(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := geminiGenerateResponse{
@@ -296,3 +328,92 @@ func TestComplete(t *testing.T) {
 		t.Errorf("unexpected text: %s", resp.GetText())
 	}
 }
+
+func TestConvertChatRequestImages(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer imageServer.Close()
+
+	client, _ := NewClient(gollmx.WithAPIKey("test-key"))
+	googleClient := client.(*Client)
+
+	req, err := googleClient.convertChatRequest(context.Background(), &gollmx.ChatRequest{
+		Model: "gemini-1.5-pro",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+				gollmx.TextContent("Describe these"),
+				gollmx.ImageURLContent(imageServer.URL+"/cat.png", ""),
+				gollmx.ImageURLContent("gs://bucket/dog.jpg", ""),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertChatRequest failed: %v", err)
+	}
+
+	parts := req.Contents[0].Parts
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+
+	inline := parts[1].InlineData
+	if inline == nil {
+		t.Fatal("expected remote image to be inlined")
+	}
+	if inline.MimeType != "image/png" {
+		t.Errorf("expected mime type 'image/png', got '%s'", inline.MimeType)
+	}
+	if inline.Data != base64.StdEncoding.EncodeToString(png) {
+		t.Error("expected inline data to be base64-encoded image bytes")
+	}
+
+	file := parts[2].FileData
+	if file == nil {
+		t.Fatal("expected gs:// image to be passed through as fileData")
+	}
+	if file.FileURI != "gs://bucket/dog.jpg" || file.MimeType != "image/jpeg" {
+		t.Errorf("unexpected fileData: %+v", file)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1beta/models" {
+			t.Errorf("expected GET /v1beta/models, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("expected the API key in the query, got %q", r.URL.Query().Get("key"))
+		}
+		pages++
+		token := r.URL.Query().Get("pageToken")
+		if token == "" {
+			w.Write([]byte(`{"models":[{"name":"models/gemini-1.5-pro","displayName":"Gemini 1.5 Pro","inputTokenLimit":2000000,"supportedGenerationMethods":["generateContent"]}],"nextPageToken":"a+b/c="}`))
+			return
+		}
+		if token != "a+b/c=" {
+			t.Errorf("expected the page token to be escaped, got %q", token)
+		}
+		w.Write([]byte(`{"models":[{"name":"models/text-embedding-005","displayName":"Text Embedding 005","inputTokenLimit":2048,"supportedGenerationMethods":["embedContent"]}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(gollmx.WithAPIKey("test-key"), gollmx.WithBaseURL(server.URL))
+	models, err := client.(gollmx.ModelLister).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pages != 2 || len(models) != 2 {
+		t.Fatalf("expected 2 models from 2 pages, got %d from %d", len(models), pages)
+	}
+	if models[0].ID != "gemini-1.5-pro" || models[0].InputPrice == 0 {
+		t.Errorf("expected gemini-1.5-pro with static pricing, got %+v", models[0])
+	}
+	if models[1].ID != "text-embedding-005" || models[1].ContextWindow != 2048 || !models[1].SupportsFeature(gollmx.FeatureEmbedding) {
+		t.Errorf("expected the live embedding model, got %+v", models[1])
+	}
+}
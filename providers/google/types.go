@@ -126,8 +126,10 @@ type geminiUsageMetadata struct {
 // =============================================================================
 
 type geminiEmbedRequest struct {
-	Model   string             `json:"model"`
-	Content geminiEmbedContent `json:"content"`
+	Model                string             `json:"model"`
+	Content              geminiEmbedContent `json:"content"`
+	TaskType             string             `json:"taskType,omitempty"`
+	OutputDimensionality int                `json:"outputDimensionality,omitempty"`
 }
 
 type geminiEmbedContent struct {
@@ -150,6 +152,24 @@ type geminiEmbedding struct {
 	Values []float64 `json:"values"`
 }
 
+// =============================================================================
+// Model Types
+// =============================================================================
+
+type geminiModelList struct {
+	Models        []geminiModel `json:"models"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+type geminiModel struct {
+	Name                       string   `json:"name"` // "models/gemini-1.5-pro"
+	DisplayName                string   `json:"displayName"`
+	Description                string   `json:"description"`
+	InputTokenLimit            int      `json:"inputTokenLimit"`
+	OutputTokenLimit           int      `json:"outputTokenLimit"`
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
@@ -0,0 +1,14 @@
+package groq
+
+import (
+	"testing"
+
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+func TestConformance(t *testing.T) {
+	server := gollmxtest.NewOpenAIServer()
+	defer server.Close()
+
+	gollmxtest.RunConformance(t, New, server)
+}
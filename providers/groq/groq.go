@@ -25,6 +25,7 @@ const (
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, GroqModels)
 }
 
 // Client implements the gollmx.LLM interface for Groq
@@ -32,6 +33,7 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
 }
 
 // New creates a new Groq client
@@ -48,6 +50,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 		config:  config,
 		baseURL: baseURL,
 		options: make(map[string]interface{}),
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -73,6 +76,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns the list of available models
 func (c *Client) Models() []gollmx.Model {
 	return GroqModels
@@ -88,6 +96,55 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from /models,
+// merged with GroqModels for pricing and features. Inactive models are
+// omitted.
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	var list modelList
+	if err := json.Unmarshal(respBody, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	live := make([]gollmx.Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		if m.Active != nil && !*m.Active {
+			continue
+		}
+		model := gollmx.Model{ID: m.ID, Name: m.ID, Provider: ProviderID, ContextWindow: m.ContextWindow}
+		if m.Created > 0 {
+			model.ReleaseDate = time.Unix(m.Created, 0).UTC().Format("2006-01-02")
+		}
+		live = append(live, model)
+	}
+	return gollmx.MergeModels(live, GroqModels), nil
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -134,6 +191,10 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
 	groqReq := c.convertChatRequest(req)
 
 	body, err := json.Marshal(groqReq)
@@ -148,7 +209,7 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -180,6 +241,10 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
 	groqReq := c.convertChatRequest(req)
 	groqReq.Stream = true
 
@@ -195,7 +260,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -479,3 +544,10 @@ func (c *Client) convertChatResponse(resp *chatResponse) *gollmx.ChatResponse {
 		Raw: resp,
 	}
 }
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
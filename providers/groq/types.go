@@ -105,6 +105,18 @@ type streamDelta struct {
 	ToolCalls []toolCall `json:"tool_calls,omitempty"`
 }
 
+type modelList struct {
+	Data []modelInfo `json:"data"`
+}
+
+type modelInfo struct {
+	ID            string `json:"id"`
+	Created       int64  `json:"created"`
+	OwnedBy       string `json:"owned_by"`
+	Active        *bool  `json:"active"`
+	ContextWindow int    `json:"context_window"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
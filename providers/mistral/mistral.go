@@ -25,6 +25,7 @@ const (
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, MistralModels)
 }
 
 // Client implements the gollmx.LLM interface for Mistral
@@ -32,6 +33,7 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
 }
 
 // New creates a new Mistral client
@@ -48,6 +50,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 		config:  config,
 		baseURL: baseURL,
 		options: make(map[string]interface{}),
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -73,6 +76,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns the list of available models
 func (c *Client) Models() []gollmx.Model {
 	return MistralModels
@@ -88,6 +96,77 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from /models,
+// merged with MistralModels for pricing. Features of models missing from
+// MistralModels are derived from their reported capabilities.
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	var list modelList
+	if err := json.Unmarshal(respBody, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	live := make([]gollmx.Model, len(list.Data))
+	for i, m := range list.Data {
+		live[i] = gollmx.Model{
+			ID:            m.ID,
+			Name:          m.Name,
+			Provider:      ProviderID,
+			Description:   m.Description,
+			ContextWindow: m.MaxContextLength,
+			Features:      m.Capabilities.features(m.ID),
+			Deprecated:    m.Deprecation != nil,
+		}
+		if m.Created > 0 {
+			live[i].ReleaseDate = time.Unix(m.Created, 0).UTC().Format("2006-01-02")
+		}
+	}
+	return gollmx.MergeModels(live, MistralModels), nil
+}
+
+// features converts reported capabilities to gollmx features
+func (mc modelCapabilities) features(id string) []gollmx.Feature {
+	var features []gollmx.Feature
+	if mc.CompletionChat {
+		features = append(features, gollmx.FeatureChat, gollmx.FeatureStreaming, gollmx.FeatureJSON, gollmx.FeatureSystemPrompt)
+	}
+	if mc.FunctionCalling {
+		features = append(features, gollmx.FeatureTools)
+	}
+	if mc.Vision {
+		features = append(features, gollmx.FeatureVision)
+	}
+	if strings.Contains(id, "embed") {
+		features = append(features, gollmx.FeatureEmbedding)
+	}
+	return features
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -135,6 +214,10 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
 	mistralReq := c.convertChatRequest(req)
 
 	body, err := json.Marshal(mistralReq)
@@ -149,7 +232,7 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -181,6 +264,10 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
 	mistralReq := c.convertChatRequest(req)
 	mistralReq.Stream = true
 
@@ -196,7 +283,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -324,9 +411,22 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		req.Model = "mistral-embed"
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.FeatureEmbedding); err != nil {
+		return nil, err
+	}
+
 	mistralReq := embedRequest{
-		Model: req.Model,
-		Input: req.Input,
+		Model:           req.Model,
+		Input:           req.Input,
+		OutputDimension: req.Dimensions,
+	}
+	switch req.EncodingFormat {
+	case "", gollmx.EmbedEncodingFloat:
+	case gollmx.EmbedEncodingInt8, gollmx.EmbedEncodingUint8, gollmx.EmbedEncodingBinary, gollmx.EmbedEncodingUbinary:
+		mistralReq.OutputDtype = string(req.EncodingFormat)
+	default:
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported embedding encoding: %s", req.EncodingFormat))
 	}
 
 	body, err := json.Marshal(mistralReq)
@@ -341,7 +441,7 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -363,10 +463,7 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 
 	embeddings := make([]gollmx.Embedding, len(mistralResp.Data))
 	for i, d := range mistralResp.Data {
-		embeddings[i] = gollmx.Embedding{
-			Index:  d.Index,
-			Vector: d.Embedding,
-		}
+		embeddings[i] = gollmx.NewEmbedding(d.Index, req.EncodingFormat, d.Embedding)
 	}
 
 	return &gollmx.EmbedResponse{
@@ -535,3 +632,10 @@ func (c *Client) convertChatResponse(resp *chatResponse) *gollmx.ChatResponse {
 		Raw: resp,
 	}
 }
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
@@ -53,8 +53,10 @@ type responseFormat struct {
 }
 
 type embedRequest struct {
-	Model string   `json:"model"`
-	Input []string `json:"input"`
+	Model           string   `json:"model"`
+	Input           []string `json:"input"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	OutputDtype     string   `json:"output_dtype,omitempty"` // float, int8, uint8, binary or ubinary
 }
 
 // =============================================================================
@@ -127,6 +129,26 @@ type embedUsage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
+type modelList struct {
+	Data []modelInfo `json:"data"`
+}
+
+type modelInfo struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	Created          int64             `json:"created"`
+	MaxContextLength int               `json:"max_context_length"`
+	Capabilities     modelCapabilities `json:"capabilities"`
+	Deprecation      *string           `json:"deprecation"`
+}
+
+type modelCapabilities struct {
+	CompletionChat  bool `json:"completion_chat"`
+	FunctionCalling bool `json:"function_calling"`
+	Vision          bool `json:"vision"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
@@ -0,0 +1,14 @@
+package ollama
+
+import (
+	"testing"
+
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+func TestConformance(t *testing.T) {
+	server := gollmxtest.NewOllamaServer()
+	defer server.Close()
+
+	gollmxtest.RunConformance(t, New, server)
+}
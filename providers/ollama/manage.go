@@ -0,0 +1,351 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// ModelManager manages the models stored on an Ollama server. Type-assert a
+// client for it:
+//
+//	if mm, ok := client.(ollama.ModelManager); ok {
+//		progress, err := mm.Pull(ctx, "llama3.2")
+//		...
+//	}
+type ModelManager interface {
+	// Pull downloads a model. Progress is streamed until the pull finishes;
+	// the channel is closed afterwards, and the last update carries Err if
+	// the pull failed.
+	Pull(ctx context.Context, model string) (<-chan ProgressResponse, error)
+	// Show returns a model's details, Modelfile, template and parameters
+	Show(ctx context.Context, model string) (*ShowResponse, error)
+	// Delete removes a model
+	Delete(ctx context.Context, model string) error
+	// Copy copies a model to a new name
+	Copy(ctx context.Context, source, destination string) error
+	// Create creates a model from a Modelfile, streaming progress like Pull
+	Create(ctx context.Context, model, modelfile string) (<-chan ProgressResponse, error)
+	// Running returns the models loaded in memory
+	Running(ctx context.Context) ([]RunningModel, error)
+	// Load loads a model into memory ahead of requests and keeps it loaded
+	// for keepAlive; a negative keepAlive keeps it loaded indefinitely
+	Load(ctx context.Context, model string, keepAlive time.Duration) error
+	// Unload removes a model from memory
+	Unload(ctx context.Context, model string) error
+}
+
+// Pull downloads a model from the Ollama library
+func (c *Client) Pull(ctx context.Context, model string) (<-chan ProgressResponse, error) {
+	return c.stream(ctx, "/api/pull", &PullRequest{Model: model, Stream: true})
+}
+
+// Show returns information about a local model
+func (c *Client) Show(ctx context.Context, model string) (*ShowResponse, error) {
+	respBody, status, err := c.post(ctx, "/api/show", &ShowRequest{Model: model})
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, c.handleError(nil, status, respBody)
+	}
+
+	var resp ShowResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Delete removes a local model
+func (c *Client) Delete(ctx context.Context, model string) error {
+	httpReq, err := c.newJSONRequest(ctx, "DELETE", "/api/delete", &DeleteRequest{Model: model})
+	if err != nil {
+		return err
+	}
+	return c.manage(c.do(httpReq))
+}
+
+// Copy copies a local model to a new name
+func (c *Client) Copy(ctx context.Context, source, destination string) error {
+	return c.manage(c.post(ctx, "/api/copy", &CopyRequest{Source: source, Destination: destination}))
+}
+
+// Create creates a model from a Modelfile. See ParseModelfile for the
+// supported instructions.
+func (c *Client) Create(ctx context.Context, model, modelfile string) (<-chan ProgressResponse, error) {
+	req, err := ParseModelfile(modelfile)
+	if err != nil {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID, err.Error())
+	}
+	req.Model = model
+	req.Stream = true
+	return c.stream(ctx, "/api/create", req)
+}
+
+// Running returns the models currently loaded in memory
+func (c *Client) Running(ctx context.Context) ([]RunningModel, error) {
+	respBody, status, err := c.get(ctx, "/api/ps")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, c.handleError(nil, status, respBody)
+	}
+
+	var resp ProcessResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return resp.Models, nil
+}
+
+// Load loads a model into memory by sending it an empty prompt
+func (c *Client) Load(ctx context.Context, model string, keepAlive time.Duration) error {
+	return c.load(ctx, model, keepAlive)
+}
+
+// Unload removes a model from memory
+func (c *Client) Unload(ctx context.Context, model string) error {
+	return c.load(ctx, model, 0)
+}
+
+func (c *Client) load(ctx context.Context, model string, d time.Duration) error {
+	respBody, status, err := c.post(ctx, "/api/generate", &GenerateRequest{Model: model, KeepAlive: keepAlive(d)})
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return c.handleError(nil, status, respBody)
+	}
+	return nil
+}
+
+// manage checks the result of a request that changes the local models, and
+// clears the ListModels cache on success
+func (c *Client) manage(respBody []byte, status int, err error) error {
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return c.handleError(nil, status, respBody)
+	}
+	c.models.Invalidate()
+	return nil
+}
+
+// stream posts payload and streams the progress updates of the response
+func (c *Client) stream(ctx context.Context, path string, payload interface{}) (<-chan ProgressResponse, error) {
+	httpReq, err := c.newJSONRequest(ctx, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	ch := make(chan ProgressResponse)
+	go c.readProgress(ctx, resp.Body, ch)
+	return ch, nil
+}
+
+func (c *Client) readProgress(ctx context.Context, body io.ReadCloser, ch chan<- ProgressResponse) {
+	defer close(ch)
+	defer body.Close()
+
+	send := func(p ProgressResponse) bool {
+		select {
+		case ch <- p:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var update struct {
+			ProgressResponse
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(line, &update); err != nil {
+			send(ProgressResponse{Err: err})
+			return
+		}
+
+		// Errors after the stream has started arrive as a final update
+		if update.Error != "" {
+			errType := gollmx.ErrorTypeServer
+			if strings.Contains(update.Error, "not found") || strings.Contains(update.Error, "does not exist") {
+				errType = gollmx.ErrorTypeModelNotFound
+			}
+			send(ProgressResponse{Err: gollmx.NewAPIError(errType, ProviderID, update.Error)})
+			return
+		}
+
+		if update.Status == "success" {
+			c.models.Invalidate()
+		}
+		if !send(update.ProgressResponse) {
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		send(ProgressResponse{Err: c.handleError(err, 0, nil)})
+	}
+}
+
+// =============================================================================
+// Modelfile
+// =============================================================================
+
+// ParseModelfile converts a Modelfile into a CreateRequest. It supports the
+// FROM, PARAMETER, TEMPLATE, SYSTEM, LICENSE and MESSAGE instructions, with
+// values optionally quoted or wrapped in """ to span lines. FROM must name
+// an existing model; creating from local GGUF or Safetensors files or with
+// ADAPTER requires uploading blobs, which is not supported.
+func ParseModelfile(modelfile string) (*CreateRequest, error) {
+	req := &CreateRequest{}
+	lines := strings.Split(strings.ReplaceAll(modelfile, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		instruction, args := cutSpace(line)
+		instruction = strings.ToUpper(instruction)
+
+		// PARAMETER and MESSAGE take a name before the value
+		var name string
+		if instruction == "PARAMETER" || instruction == "MESSAGE" {
+			name, args = cutSpace(args)
+		}
+
+		value, err := modelfileValue(args, lines, &i)
+		if err != nil {
+			return nil, fmt.Errorf("ollama: modelfile line %d: %w", lineNum, err)
+		}
+
+		switch instruction {
+		case "FROM":
+			if isLocalPath(value) {
+				return nil, fmt.Errorf("ollama: modelfile line %d: creating from local files is not supported", lineNum)
+			}
+			req.From = value
+		case "PARAMETER":
+			if name == "" {
+				return nil, fmt.Errorf("ollama: modelfile line %d: PARAMETER requires a name and value", lineNum)
+			}
+			if req.Parameters == nil {
+				req.Parameters = make(map[string]interface{})
+			}
+			if name == "stop" {
+				stops, _ := req.Parameters["stop"].([]string)
+				req.Parameters["stop"] = append(stops, value)
+			} else {
+				req.Parameters[name] = parameterValue(value)
+			}
+		case "TEMPLATE":
+			req.Template = value
+		case "SYSTEM":
+			req.System = value
+		case "LICENSE":
+			req.License = append(req.License, value)
+		case "MESSAGE":
+			if name != "system" && name != "user" && name != "assistant" {
+				return nil, fmt.Errorf("ollama: modelfile line %d: invalid message role %q", lineNum, name)
+			}
+			req.Messages = append(req.Messages, Message{Role: name, Content: value})
+		case "ADAPTER":
+			return nil, fmt.Errorf("ollama: modelfile line %d: ADAPTER is not supported", lineNum)
+		default:
+			return nil, fmt.Errorf("ollama: modelfile line %d: unknown instruction %q", lineNum, instruction)
+		}
+	}
+
+	if req.From == "" {
+		return nil, fmt.Errorf("ollama: modelfile has no FROM instruction")
+	}
+	return req, nil
+}
+
+// cutSpace splits s at its first run of whitespace
+func cutSpace(s string) (before, after string) {
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// modelfileValue unquotes an instruction value. A value opening with """
+// continues over the following lines until the closing """, advancing *i.
+func modelfileValue(value string, lines []string, i *int) (string, error) {
+	if rest, ok := strings.CutPrefix(value, `"""`); ok {
+		for !strings.Contains(rest, `"""`) {
+			*i++
+			if *i == len(lines) {
+				return "", fmt.Errorf(`unterminated """`)
+			}
+			rest += "\n" + lines[*i]
+		}
+		return rest[:strings.Index(rest, `"""`)], nil
+	}
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	}
+	return value, nil
+}
+
+// parameterValue converts a PARAMETER value to a number or boolean where it
+// parses as one
+func parameterValue(value string) interface{} {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return value
+}
+
+// isLocalPath reports whether a FROM value refers to a file rather than a
+// model name
+func isLocalPath(from string) bool {
+	return strings.HasPrefix(from, "/") || strings.HasPrefix(from, "./") ||
+		strings.HasPrefix(from, "../") || strings.HasPrefix(from, "~") ||
+		strings.HasSuffix(from, ".gguf") || strings.HasSuffix(from, ".safetensors")
+}
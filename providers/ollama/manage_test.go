@@ -0,0 +1,285 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func TestPull(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/pull" {
+			t.Errorf("expected POST /api/pull, got %s %s", r.Method, r.URL.Path)
+		}
+		var req PullRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "llama3.2" || !req.Stream {
+			t.Errorf("expected streaming pull of llama3.2, got %+v", req)
+		}
+		w.Write([]byte(`{"status":"pulling manifest"}
+{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":40}
+{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":100}
+{"status":"success"}
+`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	ch, err := client.(ModelManager).Pull(context.Background(), "llama3.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updates []ProgressResponse
+	for p := range ch {
+		if p.Err != nil {
+			t.Fatalf("unexpected error: %v", p.Err)
+		}
+		updates = append(updates, p)
+	}
+	if len(updates) != 4 {
+		t.Fatalf("expected 4 updates, got %d", len(updates))
+	}
+	if updates[1].Digest != "sha256:abc" || updates[1].Total != 100 || updates[1].Completed != 40 {
+		t.Errorf("expected download progress, got %+v", updates[1])
+	}
+	if updates[3].Status != "success" {
+		t.Errorf("expected 'success', got '%s'", updates[3].Status)
+	}
+}
+
+func TestPullStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"pulling manifest"}
+{"error":"pull model manifest: file does not exist"}
+`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	ch, err := client.(ModelManager).Pull(context.Background(), "no-such-model")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var last ProgressResponse
+	for p := range ch {
+		last = p
+	}
+	var apiErr *gollmx.APIError
+	if !errors.As(last.Err, &apiErr) || apiErr.Type != gollmx.ErrorTypeModelNotFound {
+		t.Errorf("expected model_not_found error, got %v", last.Err)
+	}
+}
+
+func TestShow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/show" {
+			t.Errorf("expected /api/show, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"modelfile":"FROM llama3.2",
+			"parameters":"num_ctx 4096",
+			"template":"{{ .Prompt }}",
+			"details":{"family":"llama","parameter_size":"3.2B"},
+			"model_info":{"llama.context_length":131072},
+			"capabilities":["completion","tools"]
+		}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	resp, err := client.(ModelManager).Show(context.Background(), "llama3.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Details.Family != "llama" || resp.Parameters != "num_ctx 4096" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(resp.Capabilities) != 2 || resp.ModelInfo["llama.context_length"] != float64(131072) {
+		t.Errorf("expected capabilities and model info, got %+v", resp)
+	}
+}
+
+func TestDeleteAndCopy(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models":[{"name":"llama3.2:latest"}]}`))
+		case "/api/delete":
+			var req DeleteRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Model != "llama3.2" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"model 'missing' not found"}`))
+			}
+		case "/api/copy":
+			var req CopyRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Source != "llama3.2" || req.Destination != "llama3.2-backup" {
+				t.Errorf("unexpected copy request: %+v", req)
+			}
+		}
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	mm := client.(ModelManager)
+	ctx := context.Background()
+
+	client.(gollmx.ModelLister).ListModels(ctx)
+	if err := mm.Copy(ctx, "llama3.2", "llama3.2-backup"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mm.Delete(ctx, "llama3.2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.(gollmx.ModelLister).ListModels(ctx)
+
+	err := mm.Delete(ctx, "missing")
+	var apiErr *gollmx.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != gollmx.ErrorTypeModelNotFound {
+		t.Errorf("expected model_not_found error, got %v", err)
+	}
+
+	expected := []string{"GET /api/tags", "POST /api/copy", "DELETE /api/delete", "GET /api/tags", "DELETE /api/delete"}
+	if len(requests) != len(expected) {
+		t.Fatalf("expected requests %v, got %v", expected, requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("expected requests %v, got %v", expected, requests)
+			break
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/create" {
+			t.Errorf("expected /api/create, got %s", r.URL.Path)
+		}
+		var req CreateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "mario" || req.From != "llama3.2" || req.System != "You are Mario." {
+			t.Errorf("unexpected create request: %+v", req)
+		}
+		w.Write([]byte(`{"status":"using existing layer"}
+{"status":"success"}
+`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	ch, err := client.(ModelManager).Create(context.Background(), "mario", "FROM llama3.2\nSYSTEM You are Mario.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var last ProgressResponse
+	for p := range ch {
+		last = p
+	}
+	if last.Err != nil || last.Status != "success" {
+		t.Errorf("expected success, got %+v", last)
+	}
+}
+
+func TestCreateInvalidModelfile(t *testing.T) {
+	client, _ := New(gollmx.WithBaseURL("http://localhost:0"))
+	_, err := client.(ModelManager).Create(context.Background(), "bad", "SYSTEM no base model")
+
+	var apiErr *gollmx.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid_request error, got %v", err)
+	}
+}
+
+func TestRunning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/ps" {
+			t.Errorf("expected GET /api/ps, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"models":[{"name":"llama3.2:latest","model":"llama3.2:latest","size":5137025024,"size_vram":5137025024,"expires_at":"2024-06-04T14:38:31Z","details":{"family":"llama"}}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	models, err := client.(ModelManager).Running(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 1 || models[0].Name != "llama3.2:latest" || models[0].SizeVRAM != 5137025024 || models[0].ExpiresAt.IsZero() {
+		t.Errorf("unexpected running models: %+v", models)
+	}
+}
+
+func TestParseModelfile(t *testing.T) {
+	req, err := ParseModelfile(`# A comment
+FROM llama3.2
+PARAMETER temperature 0.7
+PARAMETER num_ctx 4096
+PARAMETER stop "<|start_header_id|>"
+PARAMETER stop <|eot_id|>
+SYSTEM """You are a helpful assistant.
+Answer briefly."""
+TEMPLATE "{{ .Prompt }}"
+MESSAGE user Is the sky blue?
+MESSAGE assistant Yes.
+LICENSE MIT
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req.From != "llama3.2" {
+		t.Errorf("expected FROM llama3.2, got '%s'", req.From)
+	}
+	if req.Parameters["temperature"] != 0.7 || req.Parameters["num_ctx"] != 4096 {
+		t.Errorf("expected typed parameters, got %v", req.Parameters)
+	}
+	stops, _ := req.Parameters["stop"].([]string)
+	if len(stops) != 2 || stops[0] != "<|start_header_id|>" || stops[1] != "<|eot_id|>" {
+		t.Errorf("expected 2 stop sequences, got %v", req.Parameters["stop"])
+	}
+	if req.System != "You are a helpful assistant.\nAnswer briefly." {
+		t.Errorf("expected multi-line system prompt, got %q", req.System)
+	}
+	if req.Template != "{{ .Prompt }}" {
+		t.Errorf("expected unquoted template, got %q", req.Template)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != "user" || req.Messages[1].Content != "Yes." {
+		t.Errorf("unexpected messages: %+v", req.Messages)
+	}
+	if len(req.License) != 1 || req.License[0] != "MIT" {
+		t.Errorf("expected license, got %v", req.License)
+	}
+}
+
+func TestParseModelfileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelfile string
+	}{
+		{"no FROM", "SYSTEM hi"},
+		{"local file", "FROM ./model.gguf"},
+		{"adapter", "FROM llama3.2\nADAPTER ./lora.gguf"},
+		{"unknown instruction", "FROM llama3.2\nRUN something"},
+		{"bad role", "FROM llama3.2\nMESSAGE tool hi"},
+		{"unterminated", "FROM llama3.2\nSYSTEM \"\"\"never closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseModelfile(tt.modelfile); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
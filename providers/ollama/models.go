@@ -14,6 +14,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "llama3.1",
@@ -23,6 +30,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "llama3",
@@ -32,6 +46,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 
 	// Mistral series
@@ -43,6 +64,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "mixtral",
@@ -52,6 +80,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 
 	// Code models
@@ -63,6 +98,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "deepseek-coder",
@@ -72,6 +114,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "qwen2.5-coder",
@@ -81,6 +130,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 
 	// Vision models
@@ -92,6 +148,14 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureVision,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "llama3.2-vision",
@@ -101,6 +165,14 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureVision,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 
 	// Embedding models
@@ -112,6 +184,9 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     0,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureEmbedding,
+		},
 	},
 	{
 		ID:            "mxbai-embed-large",
@@ -121,6 +196,9 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     0,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureEmbedding,
+		},
 	},
 
 	// Other popular models
@@ -132,6 +210,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "gemma2",
@@ -141,6 +226,13 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 	{
 		ID:            "qwen2.5",
@@ -150,5 +242,12 @@ var defaultModels = []gollmx.Model{
 		MaxOutput:     4096,
 		InputPrice:    0,
 		OutputPrice:   0,
+		Features: []gollmx.Feature{
+			gollmx.FeatureChat,
+			gollmx.FeatureStreaming,
+			gollmx.FeatureCompletion,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
+		},
 	},
 }
@@ -6,9 +6,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	gollmx "github.com/onlyhyde/gollm-x"
@@ -19,10 +22,14 @@ const (
 	ProviderName   = "Ollama"
 	DefaultBaseURL = "http://localhost:11434"
 	DefaultModel   = "llama3.2"
+
+	// DefaultEmbedModel is used when an EmbedRequest has no model
+	DefaultEmbedModel = "nomic-embed-text"
 )
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, defaultModels)
 }
 
 // Client implements the gollmx.LLM interface for Ollama
@@ -30,6 +37,10 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
+
+	// Set once the server is known to lack /api/embed
+	legacyEmbed atomic.Bool
 }
 
 // New creates a new Ollama client
@@ -46,6 +57,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 		config:  config,
 		baseURL: baseURL,
 		options: make(map[string]interface{}),
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -71,6 +83,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns available models
 func (c *Client) Models() []gollmx.Model {
 	return defaultModels
@@ -86,6 +103,60 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models pulled locally, from /api/tags, merged with
+// the built-in metadata of common models
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	respBody, status, err := c.get(ctx, "/api/tags")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, c.handleError(nil, status, respBody)
+	}
+
+	var list ListModelsResponse
+	if err := json.Unmarshal(respBody, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	live := make([]gollmx.Model, len(list.Models))
+	for i, m := range list.Models {
+		live[i] = gollmx.Model{
+			ID:          m.Name,
+			Name:        m.Name,
+			Provider:    ProviderID,
+			Description: strings.Join(strings.Fields(m.Details.Family+" "+m.Details.ParameterSize+" "+m.Details.QuantizationLevel), " "),
+			Features:    m.Details.features(),
+		}
+		if !m.ModifiedAt.IsZero() {
+			live[i].ReleaseDate = m.ModifiedAt.UTC().Format("2006-01-02")
+		}
+	}
+	return gollmx.MergeModels(live, defaultModels), nil
+}
+
+// features infers a local model's features from its model families
+func (d Details) features() []gollmx.Feature {
+	families := append([]string{d.Family}, d.Families...)
+	for _, family := range families {
+		if strings.Contains(family, "bert") {
+			return []gollmx.Feature{gollmx.FeatureEmbedding}
+		}
+	}
+
+	features := []gollmx.Feature{gollmx.FeatureChat, gollmx.FeatureStreaming, gollmx.FeatureCompletion, gollmx.FeatureSystemPrompt}
+	for _, family := range families {
+		if family == "clip" || strings.HasSuffix(family, "vl") || strings.HasSuffix(family, "vision") {
+			return append(features, gollmx.FeatureVision)
+		}
+	}
+	return features
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -132,7 +203,14 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
-	ollamaReq := c.buildChatRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
+	ollamaReq, err := c.buildChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(ollamaReq)
 	if err != nil {
@@ -146,7 +224,7 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -178,7 +256,14 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
-	ollamaReq := c.buildChatRequest(req)
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
+	ollamaReq, err := c.buildChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	ollamaReq.Stream = true
 
 	body, err := json.Marshal(ollamaReq)
@@ -193,7 +278,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -235,12 +320,8 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 		}
 
 		if resp.Done {
-			chunk.FinishReason = "stop"
-			chunk.Usage = gollmx.Usage{
-				PromptTokens:     resp.PromptEvalCount,
-				CompletionTokens: resp.EvalCount,
-				TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
-			}
+			chunk.FinishReason = convertDoneReason(resp.DoneReason)
+			chunk.Usage = convertUsage(&resp)
 		}
 
 		ch <- chunk
@@ -251,114 +332,287 @@ func (c *Client) readStream(body io.ReadCloser, ch chan gollmx.StreamChunk, mode
 	}
 }
 
-// Complete performs a text completion request (uses chat internally)
+// Complete performs a text completion request with /api/generate.
+//
+// req.Extra may set the keys documented on Options. Pass ContextFrom of a
+// previous completion as ExtraContext to continue it without resending the
+// prompt, and set ExtraRaw to bypass the model's prompt template.
 func (c *Client) Complete(ctx context.Context, req *gollmx.CompletionRequest) (*gollmx.CompletionResponse, error) {
-	chatReq := &gollmx.ChatRequest{
-		Model: req.Model,
-		Messages: []gollmx.Message{
-			{Role: gollmx.RoleUser, Content: req.Prompt},
-		},
-		MaxTokens:   req.MaxTokens,
-		Temperature: req.Temperature,
-		TopP:        req.TopP,
-		Stop:        req.Stop,
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+		if model == "" {
+			model = DefaultModel
+		}
+	}
+
+	if err := c.config.CheckModel(c, model, gollmx.FeatureCompletion); err != nil {
+		return nil, err
+	}
+
+	genReq := &GenerateRequest{
+		Model:     model,
+		Prompt:    req.Prompt,
+		Stream:    false,
+		Format:    responseFormat(nil, req.Extra),
+		KeepAlive: keepAlive(req.Extra[ExtraKeepAlive]),
+	}
+	if raw, ok := req.Extra[ExtraRaw].(bool); ok {
+		genReq.Raw = raw
+	}
+	history, err := generateContext(req.Extra[ExtraContext])
+	if err != nil {
+		return nil, err
+	}
+	genReq.Context = history
+
+	options := modelOptions(req.Extra)
+	if req.Temperature != nil {
+		options["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		options["top_p"] = *req.TopP
+	}
+	if req.MaxTokens > 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	if len(options) > 0 {
+		genReq.Options = options
 	}
 
-	chatResp, err := c.Chat(ctx, chatReq)
+	respBody, status, err := c.post(ctx, "/api/generate", genReq)
 	if err != nil {
 		return nil, err
 	}
+	if status != http.StatusOK {
+		return nil, c.handleError(nil, status, respBody)
+	}
+
+	var resp GenerateResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
 
 	return &gollmx.CompletionResponse{
-		ID:       chatResp.ID,
+		ID:       fmt.Sprintf("ollama-%d", resp.CreatedAt.Unix()),
 		Provider: ProviderID,
-		Model:    chatResp.Model,
-		Created:  chatResp.Created,
+		Model:    resp.Model,
+		Created:  resp.CreatedAt.Unix(),
 		Choices: []gollmx.CompletionChoice{
 			{
 				Index:        0,
-				Text:         chatResp.GetContent(),
-				FinishReason: chatResp.Choices[0].FinishReason,
+				Text:         resp.Response,
+				FinishReason: convertDoneReason(resp.DoneReason),
 			},
 		},
-		Usage: chatResp.Usage,
+		Usage: convertGenerateUsage(&resp),
+		Raw:   &resp,
 	}, nil
 }
 
-// Embed performs an embedding request
+// Embed performs an embedding request using /api/embed, which embeds every
+// input in one call. Servers without /api/embed fall back to one
+// /api/embeddings call per input.
+//
+// req.Extra may set "truncate" (bool) and ExtraKeepAlive. Only float
+// encodings are supported.
 func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.EmbedResponse, error) {
-	if req.Model == "" {
-		req.Model = "nomic-embed-text"
+	if len(req.Input) == 0 {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID, "embedding input is empty")
 	}
-
-	ollamaReq := EmbedRequest{
-		Model:  req.Model,
-		Prompt: req.Input[0], // Ollama takes single prompt
+	if req.EncodingFormat != "" && req.EncodingFormat != gollmx.EmbedEncodingFloat {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported embedding encoding: %s", req.EncodingFormat))
 	}
 
-	body, err := json.Marshal(ollamaReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	model := req.Model
+	if model == "" {
+		model = DefaultEmbedModel
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/embeddings", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// /api/embed accepts generative models too, so no feature is required
+	if err := c.config.CheckModel(c, model); err != nil {
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	ollamaReq := EmbedRequest{
+		Model:      model,
+		Input:      req.Input,
+		Dimensions: req.Dimensions,
+		KeepAlive:  keepAlive(req.Extra[ExtraKeepAlive]),
+	}
+	if truncate, ok := req.Extra["truncate"].(bool); ok {
+		ollamaReq.Truncate = &truncate
+	}
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
-	if err != nil {
-		return nil, c.handleError(err, 0, nil)
+	if !c.legacyEmbed.Load() {
+		resp, err := c.embed(ctx, &ollamaReq)
+		if err != errEmbedUnsupported {
+			return resp, err
+		}
+		c.legacyEmbed.Store(true)
 	}
-	defer resp.Body.Close()
+	return c.embedEach(ctx, &ollamaReq)
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+// errEmbedUnsupported is returned by embed when the server predates /api/embed
+var errEmbedUnsupported = errors.New("ollama: /api/embed is not supported")
+
+// embed embeds all inputs with /api/embed
+func (c *Client) embed(ctx context.Context, ollamaReq *EmbedRequest) (*gollmx.EmbedResponse, error) {
+	respBody, statusCode, err := c.post(ctx, "/api/embed", ollamaReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	// Older servers answer unknown routes with a plain text 404, whereas a
+	// missing model is reported as a JSON error
+	if statusCode == http.StatusNotFound && !json.Valid(respBody) {
+		return nil, errEmbedUnsupported
+	}
+	if statusCode != http.StatusOK {
+		return nil, c.handleError(nil, statusCode, respBody)
 	}
 
 	var ollamaResp EmbedResponse
 	if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	if len(ollamaResp.Embeddings) != len(ollamaReq.Input) {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeServer, ProviderID,
+			fmt.Sprintf("expected %d embeddings, got %d", len(ollamaReq.Input), len(ollamaResp.Embeddings)))
+	}
+
+	embeddings := make([]gollmx.Embedding, len(ollamaResp.Embeddings))
+	for i, vector := range ollamaResp.Embeddings {
+		embeddings[i] = gollmx.Embedding{Index: i, Vector: vector}
+	}
+
+	model := ollamaResp.Model
+	if model == "" {
+		model = ollamaReq.Model
+	}
 
 	return &gollmx.EmbedResponse{
-		Provider: ProviderID,
-		Model:    req.Model,
-		Embeddings: []gollmx.Embedding{
-			{
-				Index:  0,
-				Vector: ollamaResp.Embedding,
-			},
+		Provider:   ProviderID,
+		Model:      model,
+		Embeddings: embeddings,
+		Usage: gollmx.Usage{
+			PromptTokens: ollamaResp.PromptEvalCount,
+			TotalTokens:  ollamaResp.PromptEvalCount,
+			LoadDuration: time.Duration(ollamaResp.LoadDuration),
 		},
 	}, nil
 }
 
+// embedEach embeds inputs one at a time with the legacy /api/embeddings
+// endpoint, which does not support truncation or dimensions and reports no
+// usage
+func (c *Client) embedEach(ctx context.Context, ollamaReq *EmbedRequest) (*gollmx.EmbedResponse, error) {
+	embeddings := make([]gollmx.Embedding, 0, len(ollamaReq.Input))
+	for i, input := range ollamaReq.Input {
+		respBody, statusCode, err := c.post(ctx, "/api/embeddings", EmbeddingsRequest{
+			Model:     ollamaReq.Model,
+			Prompt:    input,
+			KeepAlive: ollamaReq.KeepAlive,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if statusCode != http.StatusOK {
+			return nil, c.handleError(nil, statusCode, respBody)
+		}
+
+		var ollamaResp EmbeddingsResponse
+		if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		embeddings = append(embeddings, gollmx.Embedding{
+			Index:  i,
+			Vector: ollamaResp.Embedding,
+		})
+	}
+
+	return &gollmx.EmbedResponse{
+		Provider:   ProviderID,
+		Model:      ollamaReq.Model,
+		Embeddings: embeddings,
+	}, nil
+}
+
+// post sends a JSON request and returns the response body and status
+func (c *Client) post(ctx context.Context, path string, payload interface{}) ([]byte, int, error) {
+	httpReq, err := c.newJSONRequest(ctx, "POST", path, payload)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.do(httpReq)
+}
+
+// newJSONRequest creates a request with payload encoded as the JSON body
+func (c *Client) newJSONRequest(ctx context.Context, method, path string, payload interface{}) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	return httpReq, nil
+}
+
+// get sends a GET request and returns the response body and status
+func (c *Client) get(ctx context.Context, path string) ([]byte, int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.do(httpReq)
+}
+
+// do sends a request and returns the response body and status
+func (c *Client) do(httpReq *http.Request) ([]byte, int, error) {
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, 0, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return respBody, resp.StatusCode, nil
+}
+
 // buildChatRequest converts gollmx.ChatRequest to Ollama format
-func (c *Client) buildChatRequest(req *gollmx.ChatRequest) *ChatRequest {
+func (c *Client) buildChatRequest(ctx context.Context, req *gollmx.ChatRequest) (*ChatRequest, error) {
 	messages := make([]Message, len(req.Messages))
 	for i, m := range req.Messages {
-		content, _ := m.Content.(string)
-		messages[i] = Message{
-			Role:    string(m.Role),
-			Content: content,
+		msg, err := c.convertMessage(ctx, m)
+		if err != nil {
+			return nil, err
 		}
+		messages[i] = msg
 	}
 
 	ollamaReq := &ChatRequest{
-		Model:    req.Model,
-		Messages: messages,
-		Stream:   false,
+		Model:     req.Model,
+		Messages:  messages,
+		Stream:    false,
+		Format:    responseFormat(req.ResponseFormat, req.Extra),
+		KeepAlive: keepAlive(req.Extra[ExtraKeepAlive]),
 	}
 
 	// Set options
-	options := make(map[string]interface{})
+	options := modelOptions(req.Extra)
 	if req.Temperature != nil {
 		options["temperature"] = *req.Temperature
 	}
@@ -375,7 +629,61 @@ func (c *Client) buildChatRequest(req *gollmx.ChatRequest) *ChatRequest {
 		ollamaReq.Options = options
 	}
 
-	return ollamaReq
+	return ollamaReq, nil
+}
+
+// convertMessage converts a gollmx message, inlining any images as base64
+func (c *Client) convertMessage(ctx context.Context, m gollmx.Message) (Message, error) {
+	msg := Message{Role: string(m.Role)}
+
+	switch content := m.Content.(type) {
+	case string:
+		msg.Content = content
+	case []gollmx.ContentPart:
+		var text strings.Builder
+		for _, part := range content {
+			switch part.Type {
+			case "text":
+				text.WriteString(part.Text)
+			case "image_url", "image_base64":
+				if part.ImageURL == nil {
+					continue
+				}
+				image, err := c.resolveImage(ctx, part)
+				if err != nil {
+					return Message{}, err
+				}
+				msg.Images = append(msg.Images, image)
+			}
+		}
+		msg.Content = text.String()
+	}
+
+	return msg, nil
+}
+
+// resolveImage returns the raw base64 data Ollama expects for an image part
+func (c *Client) resolveImage(ctx context.Context, part gollmx.ContentPart) (string, error) {
+	url := part.ImageURL.URL
+	if part.Type == "image_base64" {
+		if _, data, ok := gollmx.ParseDataURL(url); ok {
+			return data, nil
+		}
+		return url, nil
+	}
+
+	media, err := c.config.MediaResolverFor(ProviderID).Resolve(ctx, url)
+	if err != nil {
+		if apiErr, ok := err.(*gollmx.APIError); ok && apiErr.Provider == "" {
+			apiErr.Provider = ProviderID
+		}
+		return "", err
+	}
+	if media.IsReference() {
+		return "", gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("file references are not supported by Ollama: %s", media.URI))
+	}
+	return media.Data, nil
 }
 
 // convertResponse converts Ollama response to gollmx format
@@ -392,14 +700,10 @@ func (c *Client) convertResponse(resp *ChatResponse) *gollmx.ChatResponse {
 					Role:    gollmx.Role(resp.Message.Role),
 					Content: resp.Message.Content,
 				},
-				FinishReason: "stop",
+				FinishReason: convertDoneReason(resp.DoneReason),
 			},
 		},
-		Usage: gollmx.Usage{
-			PromptTokens:     resp.PromptEvalCount,
-			CompletionTokens: resp.EvalCount,
-			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
-		},
+		Usage: convertUsage(resp),
 	}
 }
 
@@ -418,6 +722,11 @@ func (c *Client) handleError(err error, statusCode int, body []byte) error {
 		Message:    string(body),
 	}
 
+	var errResp ErrorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	}
+
 	switch statusCode {
 	case 401:
 		apiErr.Type = gollmx.ErrorTypeAuth
@@ -438,3 +747,44 @@ func (c *Client) handleError(err error, statusCode int, body []byte) error {
 
 	return apiErr
 }
+
+// convertDoneReason maps an Ollama done reason to a finish reason
+func convertDoneReason(reason string) string {
+	if reason == "length" {
+		return "length"
+	}
+	return "stop"
+}
+
+// convertUsage converts Ollama token counts and timings (in nanoseconds)
+func convertUsage(resp *ChatResponse) gollmx.Usage {
+	return gollmx.Usage{
+		PromptTokens:       resp.PromptEvalCount,
+		CompletionTokens:   resp.EvalCount,
+		TotalTokens:        resp.PromptEvalCount + resp.EvalCount,
+		LoadDuration:       time.Duration(resp.LoadDuration),
+		PromptEvalDuration: time.Duration(resp.PromptEvalDuration),
+		EvalDuration:       time.Duration(resp.EvalDuration),
+	}
+}
+
+// convertGenerateUsage converts the token counts and timings of a generate
+// response
+func convertGenerateUsage(resp *GenerateResponse) gollmx.Usage {
+	return gollmx.Usage{
+		PromptTokens:       resp.PromptEvalCount,
+		CompletionTokens:   resp.EvalCount,
+		TotalTokens:        resp.PromptEvalCount + resp.EvalCount,
+		LoadDuration:       time.Duration(resp.LoadDuration),
+		PromptEvalDuration: time.Duration(resp.PromptEvalDuration),
+		EvalDuration:       time.Duration(resp.EvalDuration),
+	}
+}
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+	_ ModelManager        = (*Client)(nil)
+)
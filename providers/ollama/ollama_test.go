@@ -211,16 +211,57 @@ func TestChatError(t *testing.T) {
 	}
 }
 
+func TestChatUnsupportedFeature(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{Model: "llava:13b", Message: Message{Role: "assistant", Content: "A cat."}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithCapabilityChecks(true))
+	messages := []gollmx.Message{
+		{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+			gollmx.TextContent("What is this?"),
+			gollmx.ImageURLContent("data:image/png;base64,aW1hZ2U=", ""),
+		}},
+	}
+
+	_, err := client.Chat(context.Background(), &gollmx.ChatRequest{Model: "llama3:8b", Messages: messages})
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok || apiErr.Type != gollmx.ErrorTypeUnsupportedFeature {
+		t.Fatalf("expected unsupported_feature error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request to be sent, got %d", requests)
+	}
+
+	if _, err := client.Chat(context.Background(), &gollmx.ChatRequest{Model: "llava:13b", Messages: messages}); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	// Checks are off by default, e.g. for custom models with wrong metadata
+	unchecked, _ := New(gollmx.WithBaseURL(server.URL))
+	if _, err := unchecked.Chat(context.Background(), &gollmx.ChatRequest{Model: "llama3:8b", Messages: messages}); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
 func TestComplete(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		response := ChatResponse{
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("expected path '/api/generate', got '%s'", r.URL.Path)
+		}
+
+		response := GenerateResponse{
 			Model:     "llama3.2",
 			CreatedAt: time.Now(),
-			Message: Message{
-				Role:    "assistant",
-				Content: "Completed text",
-			},
-			Done: true,
+			Response:  "Completed text",
+			Done:      true,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -246,12 +287,26 @@ func TestComplete(t *testing.T) {
 
 func TestEmbed(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/api/embeddings" {
-			t.Errorf("expected path '/api/embeddings', got '%s'", r.URL.Path)
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("expected path '/api/embed', got '%s'", r.URL.Path)
+		}
+
+		var req EmbedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Input) != 2 {
+			t.Errorf("expected 2 inputs in one request, got %d", len(req.Input))
+		}
+		if req.Truncate == nil || *req.Truncate {
+			t.Error("expected truncate=false")
+		}
+		if req.KeepAlive != "10m" {
+			t.Errorf("expected keep_alive '10m', got %v", req.KeepAlive)
 		}
 
 		response := EmbedResponse{
-			Embedding: []float64{0.1, 0.2, 0.3, 0.4, 0.5},
+			Model:           req.Model,
+			Embeddings:      [][]float64{{0.1, 0.2, 0.3, 0.4, 0.5}, {0.5, 0.4, 0.3, 0.2, 0.1}},
+			PromptEvalCount: 6,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -263,20 +318,112 @@ func TestEmbed(t *testing.T) {
 
 	resp, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
 		Model: "nomic-embed-text",
-		Input: []string{"Hello world"},
+		Input: []string{"Hello world", "Goodbye world"},
+		Extra: map[string]interface{}{"truncate": false, "keep_alive": "10m"},
 	})
 
 	if err != nil {
 		t.Fatalf("embed failed: %v", err)
 	}
 
-	if len(resp.Embeddings) != 1 {
-		t.Fatalf("expected 1 embedding, got %d", len(resp.Embeddings))
+	if len(resp.Embeddings) != 2 {
+		t.Fatalf("expected 2 embeddings, got %d", len(resp.Embeddings))
 	}
 
 	if len(resp.Embeddings[0].Vector) != 5 {
 		t.Errorf("expected 5 dimensions, got %d", len(resp.Embeddings[0].Vector))
 	}
+
+	if resp.Embeddings[1].Index != 1 || resp.Embeddings[1].Vector[0] != 0.5 {
+		t.Errorf("unexpected second embedding: %+v", resp.Embeddings[1])
+	}
+
+	if resp.Usage.PromptTokens != 6 {
+		t.Errorf("expected 6 prompt tokens, got %d", resp.Usage.PromptTokens)
+	}
+}
+
+func TestEmbedLegacyFallback(t *testing.T) {
+	var embedCalls, legacyCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/embed":
+			embedCalls++
+			http.NotFound(w, r)
+		case "/api/embeddings":
+			legacyCalls++
+			var req EmbeddingsRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(EmbeddingsResponse{Embedding: []float64{float64(len(req.Prompt))}})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	req := &gollmx.EmbedRequest{Input: []string{"a", "bb", "ccc"}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Embed(context.Background(), req)
+		if err != nil {
+			t.Fatalf("embed failed: %v", err)
+		}
+		if len(resp.Embeddings) != 3 || resp.Embeddings[2].Vector[0] != 3 {
+			t.Fatalf("expected one embedding per input, got %+v", resp.Embeddings)
+		}
+	}
+
+	if embedCalls != 1 {
+		t.Errorf("expected /api/embed to be tried once, got %d", embedCalls)
+	}
+	if legacyCalls != 6 {
+		t.Errorf("expected 6 legacy calls, got %d", legacyCalls)
+	}
+}
+
+func TestEmbedModelNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("expected no fallback, got request to '%s'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model \"missing\" not found, try pulling it first"}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	_, err := client.Embed(context.Background(), &gollmx.EmbedRequest{Model: "missing", Input: []string{"a"}})
+
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok || apiErr.Type != gollmx.ErrorTypeModelNotFound {
+		t.Errorf("expected model_not_found error, got %v", err)
+	}
+}
+
+func TestEmbedWithChatModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(EmbedResponse{Model: "llama3", Embeddings: [][]float64{{0.1, 0.2}}})
+	}))
+	defer server.Close()
+
+	// /api/embed accepts chat models, even with capability checks enabled
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithCapabilityChecks(true))
+	resp, err := client.Embed(context.Background(), &gollmx.EmbedRequest{Model: "llama3", Input: []string{"hello"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Embeddings) != 1 {
+		t.Errorf("expected 1 embedding, got %d", len(resp.Embeddings))
+	}
+}
+
+func TestEmbedEmptyInput(t *testing.T) {
+	client, _ := New()
+	_, err := client.Embed(context.Background(), &gollmx.EmbedRequest{})
+
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok || apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid_request error, got %v", err)
+	}
 }
 
 func TestBuildChatRequest(t *testing.T) {
@@ -298,7 +445,10 @@ func TestBuildChatRequest(t *testing.T) {
 		Stop:        []string{"END"},
 	}
 
-	ollamaReq := ollamaClient.buildChatRequest(req)
+	ollamaReq, err := ollamaClient.buildChatRequest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("buildChatRequest failed: %v", err)
+	}
 
 	if ollamaReq.Model != "llama3.2" {
 		t.Errorf("expected model 'llama3.2', got '%s'", ollamaReq.Model)
@@ -316,3 +466,244 @@ func TestBuildChatRequest(t *testing.T) {
 		t.Errorf("expected num_predict 100, got %v", ollamaReq.Options["num_predict"])
 	}
 }
+
+func TestBuildChatRequestImages(t *testing.T) {
+	client, _ := New()
+	ollamaClient := client.(*Client)
+
+	req, err := ollamaClient.buildChatRequest(context.Background(), &gollmx.ChatRequest{
+		Model: "llava",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleUser, Content: []gollmx.ContentPart{
+				gollmx.TextContent("What is this?"),
+				gollmx.ImageURLContent("data:image/png;base64,iVBORw0KGgo=", ""),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildChatRequest failed: %v", err)
+	}
+
+	msg := req.Messages[0]
+	if msg.Content != "What is this?" {
+		t.Errorf("unexpected content: %s", msg.Content)
+	}
+	if len(msg.Images) != 1 || msg.Images[0] != "iVBORw0KGgo=" {
+		t.Errorf("expected raw base64 image, got %v", msg.Images)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/tags" {
+			t.Errorf("expected GET /api/tags, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"models":[
+			{"name":"llama3.2:latest","modified_at":"2024-10-01T12:00:00Z","details":{"family":"llama","parameter_size":"3.2B","quantization_level":"Q4_K_M"}},
+			{"name":"nomic-embed-text:latest","details":{"family":"nomic-bert","families":["nomic-bert"]}},
+			{"name":"my-vision:7b","details":{"family":"llama","families":["llama","clip"]}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	models, err := client.(gollmx.ModelLister).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 3 {
+		t.Fatalf("expected only the 3 local models, got %d", len(models))
+	}
+	if models[0].ID != "llama3.2:latest" || models[0].ContextWindow != 128000 || models[0].ReleaseDate != "2024-10-01" {
+		t.Errorf("expected llama3.2:latest with built-in metadata, got %+v", models[0])
+	}
+	if !models[0].SupportsFeature(gollmx.FeatureChat) || models[0].SupportsFeature(gollmx.FeatureEmbedding) {
+		t.Errorf("expected a chat model, got %v", models[0].Features)
+	}
+	if !models[1].SupportsFeature(gollmx.FeatureEmbedding) || models[1].SupportsFeature(gollmx.FeatureChat) {
+		t.Errorf("expected an embedding-only model, got %v", models[1].Features)
+	}
+	if models[2].Description != "llama" || !models[2].SupportsFeature(gollmx.FeatureVision) || models[2].SupportsFeature(gollmx.FeatureEmbedding) {
+		t.Errorf("expected an unknown vision model described by family, got %+v", models[2])
+	}
+}
+
+func TestChatOptions(t *testing.T) {
+	var req map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(ChatResponse{Model: "llama3.2", Message: Message{Role: "assistant", Content: "{}"}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	keep := 30 * time.Minute
+	seed := 42
+	temp := 0.2
+	_, err := client.Chat(context.Background(), &gollmx.ChatRequest{
+		Model:       "llama3.2",
+		Messages:    []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+		Temperature: &temp,
+		ResponseFormat: &gollmx.ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &gollmx.JSONSchema{Name: "reply", Schema: json.RawMessage(`{"type":"object"}`)},
+		},
+		Extra: Options{KeepAlive: &keep, NumCtx: 8192, Seed: &seed, Options: map[string]interface{}{"num_gpu": 1}}.Extra(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req["keep_alive"] != "30m0s" {
+		t.Errorf("expected keep_alive '30m0s', got %v", req["keep_alive"])
+	}
+	format, _ := req["format"].(map[string]interface{})
+	if format["type"] != "object" {
+		t.Errorf("expected the JSON schema as format, got %v", req["format"])
+	}
+	options, _ := req["options"].(map[string]interface{})
+	if options["num_ctx"] != float64(8192) || options["seed"] != float64(42) || options["num_gpu"] != float64(1) || options["temperature"] != 0.2 {
+		t.Errorf("unexpected options: %v", options)
+	}
+}
+
+func TestChatJSONFormat(t *testing.T) {
+	var req ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(ChatResponse{Model: "llama3.2", Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	client.Chat(context.Background(), &gollmx.ChatRequest{
+		Messages:       []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hi"}},
+		ResponseFormat: &gollmx.ResponseFormat{Type: "json_object"},
+		Extra:          map[string]interface{}{ExtraKeepAlive: time.Duration(-1)},
+	})
+
+	if req.Format != "json" {
+		t.Errorf("expected format 'json', got %v", req.Format)
+	}
+	if req.KeepAlive != float64(-1) {
+		t.Errorf("expected keep_alive -1 to keep the model loaded, got %v", req.KeepAlive)
+	}
+}
+
+func TestCompleteContext(t *testing.T) {
+	var requests []GenerateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		json.NewEncoder(w).Encode(GenerateResponse{
+			Model:           "llama3.2",
+			Response:        "Paris",
+			Done:            true,
+			DoneReason:      "stop",
+			Context:         append(req.Context, len(requests)),
+			PromptEvalCount: 5,
+			EvalCount:       1,
+		})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	first, err := client.Complete(ctx, &gollmx.CompletionRequest{Model: "llama3.2", Prompt: "Capital of France?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ContextFrom(first); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected context [1], got %v", got)
+	}
+	if first.Usage.TotalTokens != 6 || first.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", first)
+	}
+
+	_, err = client.Complete(ctx, &gollmx.CompletionRequest{
+		Model:  "llama3.2",
+		Prompt: "And Germany?",
+		Extra:  Options{Context: ContextFrom(first), Raw: true}.Extra(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests[1].Context) != 1 || requests[1].Context[0] != 1 || !requests[1].Raw {
+		t.Errorf("expected the previous context in raw mode, got %+v", requests[1])
+	}
+	if requests[0].Stream || requests[0].Raw {
+		t.Errorf("expected a non-streaming templated request, got %+v", requests[0])
+	}
+}
+
+func TestCompleteContextFromJSON(t *testing.T) {
+	var got []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		got = req.Context
+		json.NewEncoder(w).Encode(GenerateResponse{Model: "llama3.2", Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	// A context stored as JSON decodes to []interface{} of float64
+	var extra map[string]interface{}
+	data, _ := json.Marshal(Options{Context: []int{1, 2, 3}}.Extra())
+	json.Unmarshal(data, &extra)
+
+	if _, err := client.Complete(ctx, &gollmx.CompletionRequest{Model: "llama3.2", Prompt: "Hi", Extra: extra}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("expected context [1 2 3], got %v", got)
+	}
+
+	for _, bad := range []interface{}{"1,2,3", []interface{}{1.5}, []string{"1"}} {
+		_, err := client.Complete(ctx, &gollmx.CompletionRequest{
+			Model:  "llama3.2",
+			Prompt: "Hi",
+			Extra:  map[string]interface{}{ExtraContext: bad},
+		})
+		apiErr, ok := err.(*gollmx.APIError)
+		if !ok || apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+			t.Errorf("expected invalid_request error for %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadUnload(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("expected path '/api/generate', got '%s'", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		json.NewEncoder(w).Encode(GenerateResponse{Model: "llama3.2", Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL))
+	mm := client.(ModelManager)
+	if err := mm.Load(context.Background(), "llama3.2", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mm.Unload(context.Background(), "llama3.2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests[0]["keep_alive"] != "1h0m0s" || requests[0]["prompt"] != "" {
+		t.Errorf("expected an empty prompt kept alive for 1h, got %v", requests[0])
+	}
+	if requests[1]["keep_alive"] != "0s" {
+		t.Errorf("expected keep_alive '0s' to unload, got %v", requests[1])
+	}
+}
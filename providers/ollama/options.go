@@ -0,0 +1,202 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// Extra keys for Ollama-specific options, set in the Extra map of a chat,
+// completion or embedding request directly or with Options.Extra
+const (
+	// ExtraKeepAlive is how long the model stays loaded after the request: a
+	// time.Duration, duration string or seconds. Negative keeps it loaded
+	// indefinitely and zero unloads it immediately.
+	ExtraKeepAlive = "keep_alive"
+	// ExtraNumCtx is the context window size in tokens (int)
+	ExtraNumCtx = "num_ctx"
+	// ExtraSeed makes sampling reproducible (int)
+	ExtraSeed = "seed"
+	// ExtraFormat is "json" or a JSON schema, and overrides ResponseFormat
+	ExtraFormat = "format"
+	// ExtraOptions holds other model parameters, e.g. "num_gpu" or
+	// "repeat_penalty" (map[string]interface{})
+	ExtraOptions = "options"
+	// ExtraRaw sends a completion prompt without the model's template (bool)
+	ExtraRaw = "raw"
+	// ExtraContext continues a completion from the context returned by a
+	// previous one: a []int from ContextFrom, or any numeric slice such as
+	// the []interface{} of float64 it becomes after a JSON round trip
+	ExtraContext = "context"
+)
+
+// Options are typed Ollama-specific request options:
+//
+//	req.Extra = ollama.Options{NumCtx: 8192, Seed: &seed}.Extra()
+type Options struct {
+	KeepAlive *time.Duration
+	NumCtx    int
+	Seed      *int
+	Format    interface{}
+	Options   map[string]interface{}
+
+	// Completion only
+	Raw     bool
+	Context []int
+}
+
+// Extra returns the options as a request Extra map
+func (o Options) Extra() map[string]interface{} {
+	extra := make(map[string]interface{})
+	if o.KeepAlive != nil {
+		extra[ExtraKeepAlive] = *o.KeepAlive
+	}
+	if o.NumCtx > 0 {
+		extra[ExtraNumCtx] = o.NumCtx
+	}
+	if o.Seed != nil {
+		extra[ExtraSeed] = *o.Seed
+	}
+	if o.Format != nil {
+		extra[ExtraFormat] = o.Format
+	}
+	if len(o.Options) > 0 {
+		extra[ExtraOptions] = o.Options
+	}
+	if o.Raw {
+		extra[ExtraRaw] = true
+	}
+	if len(o.Context) > 0 {
+		extra[ExtraContext] = o.Context
+	}
+	return extra
+}
+
+// ContextFrom returns the context of a completion, which continues the
+// conversation when passed back as ExtraContext. It is nil if resp did not
+// come from the Ollama client.
+func ContextFrom(resp *gollmx.CompletionResponse) []int {
+	if resp == nil {
+		return nil
+	}
+	if raw, ok := resp.Raw.(*GenerateResponse); ok {
+		return raw.Context
+	}
+	return nil
+}
+
+// modelOptions returns the model options set in extra: ExtraOptions plus
+// num_ctx and seed. Callers add the request's sampling parameters.
+func modelOptions(extra map[string]interface{}) map[string]interface{} {
+	options := make(map[string]interface{})
+	if m, ok := extra[ExtraOptions].(map[string]interface{}); ok {
+		for k, v := range m {
+			options[k] = v
+		}
+	}
+	if v, ok := extra[ExtraNumCtx]; ok {
+		options["num_ctx"] = v
+	}
+	if v, ok := extra[ExtraSeed]; ok {
+		options["seed"] = v
+	}
+	return options
+}
+
+// generateContext converts an ExtraContext value to token IDs. A value that
+// is not a slice of whole numbers is an error rather than being dropped,
+// which would silently restart the conversation.
+func generateContext(v interface{}) ([]int, error) {
+	switch c := v.(type) {
+	case nil:
+		return nil, nil
+	case []int:
+		return c, nil
+	case []int32:
+		return convertContext(c)
+	case []int64:
+		return convertContext(c)
+	case []float64:
+		return convertContext(c)
+	case []interface{}:
+		return convertContext(c)
+	}
+	return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+		fmt.Sprintf("%s must be a numeric slice, got %T", ExtraContext, v))
+}
+
+func convertContext[T any](values []T) ([]int, error) {
+	out := make([]int, len(values))
+	for i, v := range values {
+		var f float64
+		switch n := any(v).(type) {
+		case int:
+			f = float64(n)
+		case int32:
+			f = float64(n)
+		case int64:
+			f = float64(n)
+		case float64:
+			f = n
+		case json.Number:
+			var err error
+			if f, err = n.Float64(); err != nil {
+				return nil, invalidContext(i, v)
+			}
+		default:
+			return nil, invalidContext(i, v)
+		}
+		if f != math.Trunc(f) {
+			return nil, invalidContext(i, v)
+		}
+		out[i] = int(f)
+	}
+	return out, nil
+}
+
+func invalidContext(i int, v interface{}) error {
+	return gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+		fmt.Sprintf("%s[%d] is not a token ID: %v", ExtraContext, i, v))
+}
+
+// keepAlive converts a keep-alive value to the form Ollama accepts
+func keepAlive(v interface{}) interface{} {
+	switch d := v.(type) {
+	case *time.Duration:
+		if d == nil {
+			return nil
+		}
+		return keepAlive(*d)
+	case time.Duration:
+		if d < 0 {
+			return -1
+		}
+		return d.String()
+	}
+	return v
+}
+
+// responseFormat returns the format for a request: ExtraFormat if set,
+// otherwise "json" for a json_object ResponseFormat or the schema of a
+// json_schema one
+func responseFormat(rf *gollmx.ResponseFormat, extra map[string]interface{}) interface{} {
+	if f, ok := extra[ExtraFormat]; ok {
+		return f
+	}
+	if rf == nil {
+		return nil
+	}
+	switch rf.Type {
+	case "json_object":
+		return "json"
+	case "json_schema":
+		if rf.JSONSchema != nil && len(rf.JSONSchema.Schema) > 0 {
+			return rf.JSONSchema.Schema
+		}
+		return "json"
+	}
+	return nil
+}
@@ -4,11 +4,12 @@ import "time"
 
 // ChatRequest represents an Ollama chat request
 type ChatRequest struct {
-	Model    string                 `json:"model"`
-	Messages []Message              `json:"messages"`
-	Stream   bool                   `json:"stream"`
-	Options  map[string]interface{} `json:"options,omitempty"`
-	Format   string                 `json:"format,omitempty"`
+	Model     string                 `json:"model"`
+	Messages  []Message              `json:"messages"`
+	Stream    bool                   `json:"stream"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	Format    interface{}            `json:"format,omitempty"`     // "json" or a JSON schema
+	KeepAlive interface{}            `json:"keep_alive,omitempty"` // Duration string or seconds
 }
 
 // Message represents a chat message
@@ -20,52 +21,85 @@ type Message struct {
 
 // ChatResponse represents an Ollama chat response
 type ChatResponse struct {
-	Model           string    `json:"model"`
-	CreatedAt       time.Time `json:"created_at"`
-	Message         Message   `json:"message"`
-	Done            bool      `json:"done"`
-	TotalDuration   int64     `json:"total_duration,omitempty"`
-	LoadDuration    int64     `json:"load_duration,omitempty"`
-	PromptEvalCount int       `json:"prompt_eval_count,omitempty"`
-	EvalCount       int       `json:"eval_count,omitempty"`
-	EvalDuration    int64     `json:"eval_duration,omitempty"`
+	Model              string    `json:"model"`
+	CreatedAt          time.Time `json:"created_at"`
+	Message            Message   `json:"message"`
+	Done               bool      `json:"done"`
+	DoneReason         string    `json:"done_reason,omitempty"`
+	TotalDuration      int64     `json:"total_duration,omitempty"`
+	LoadDuration       int64     `json:"load_duration,omitempty"`
+	PromptEvalCount    int       `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64     `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int       `json:"eval_count,omitempty"`
+	EvalDuration       int64     `json:"eval_duration,omitempty"`
 }
 
 // GenerateRequest represents an Ollama generate request
 type GenerateRequest struct {
-	Model   string                 `json:"model"`
-	Prompt  string                 `json:"prompt"`
-	Stream  bool                   `json:"stream"`
-	Options map[string]interface{} `json:"options,omitempty"`
-	System  string                 `json:"system,omitempty"`
-	Context []int                  `json:"context,omitempty"`
+	Model     string                 `json:"model"`
+	Prompt    string                 `json:"prompt"`
+	Stream    bool                   `json:"stream"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	System    string                 `json:"system,omitempty"`
+	Context   []int                  `json:"context,omitempty"`
+	Raw       bool                   `json:"raw,omitempty"`
+	Format    interface{}            `json:"format,omitempty"`     // "json" or a JSON schema
+	KeepAlive interface{}            `json:"keep_alive,omitempty"` // Duration string or seconds
 }
 
 // GenerateResponse represents an Ollama generate response
 type GenerateResponse struct {
-	Model           string    `json:"model"`
-	CreatedAt       time.Time `json:"created_at"`
-	Response        string    `json:"response"`
-	Done            bool      `json:"done"`
-	Context         []int     `json:"context,omitempty"`
-	TotalDuration   int64     `json:"total_duration,omitempty"`
-	LoadDuration    int64     `json:"load_duration,omitempty"`
-	PromptEvalCount int       `json:"prompt_eval_count,omitempty"`
-	EvalCount       int       `json:"eval_count,omitempty"`
-	EvalDuration    int64     `json:"eval_duration,omitempty"`
-}
-
-// EmbedRequest represents an Ollama embedding request
+	Model              string    `json:"model"`
+	CreatedAt          time.Time `json:"created_at"`
+	Response           string    `json:"response"`
+	Done               bool      `json:"done"`
+	DoneReason         string    `json:"done_reason,omitempty"`
+	Context            []int     `json:"context,omitempty"`
+	TotalDuration      int64     `json:"total_duration,omitempty"`
+	LoadDuration       int64     `json:"load_duration,omitempty"`
+	PromptEvalCount    int       `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64     `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int       `json:"eval_count,omitempty"`
+	EvalDuration       int64     `json:"eval_duration,omitempty"`
+}
+
+// EmbedRequest represents an Ollama /api/embed request
 type EmbedRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
+	Model      string                 `json:"model"`
+	Input      []string               `json:"input"`
+	Truncate   *bool                  `json:"truncate,omitempty"`
+	Dimensions int                    `json:"dimensions,omitempty"`
+	KeepAlive  interface{}            `json:"keep_alive,omitempty"` // Duration string or seconds
+	Options    map[string]interface{} `json:"options,omitempty"`
 }
 
-// EmbedResponse represents an Ollama embedding response
+// EmbedResponse represents an Ollama /api/embed response
 type EmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float64 `json:"embeddings"`
+	TotalDuration   int64       `json:"total_duration,omitempty"`
+	LoadDuration    int64       `json:"load_duration,omitempty"`
+	PromptEvalCount int         `json:"prompt_eval_count,omitempty"`
+}
+
+// EmbeddingsRequest represents a request to the legacy /api/embeddings
+// endpoint, which embeds a single prompt
+type EmbeddingsRequest struct {
+	Model     string      `json:"model"`
+	Prompt    string      `json:"prompt"`
+	KeepAlive interface{} `json:"keep_alive,omitempty"`
+}
+
+// EmbeddingsResponse represents a legacy /api/embeddings response
+type EmbeddingsResponse struct {
 	Embedding []float64 `json:"embedding"`
 }
 
+// ErrorResponse represents an Ollama error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
 // ListModelsResponse represents the response from listing models
 type ListModelsResponse struct {
 	Models []ModelInfo `json:"models"`
@@ -88,3 +122,82 @@ type Details struct {
 	ParameterSize     string   `json:"parameter_size"`
 	QuantizationLevel string   `json:"quantization_level"`
 }
+
+// PullRequest represents an Ollama /api/pull request
+type PullRequest struct {
+	Model    string `json:"model"`
+	Insecure bool   `json:"insecure,omitempty"`
+	Stream   bool   `json:"stream"`
+}
+
+// ProgressResponse is a status update streamed by /api/pull and /api/create.
+// Err is set on the last update if the operation failed.
+type ProgressResponse struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Err       error  `json:"-"`
+}
+
+// ShowRequest represents an Ollama /api/show request
+type ShowRequest struct {
+	Model   string `json:"model"`
+	Verbose bool   `json:"verbose,omitempty"`
+}
+
+// ShowResponse represents an Ollama /api/show response
+type ShowResponse struct {
+	License      string                 `json:"license,omitempty"`
+	Modelfile    string                 `json:"modelfile,omitempty"`
+	Parameters   string                 `json:"parameters,omitempty"`
+	Template     string                 `json:"template,omitempty"`
+	System       string                 `json:"system,omitempty"`
+	Details      Details                `json:"details"`
+	Messages     []Message              `json:"messages,omitempty"`
+	ModelInfo    map[string]interface{} `json:"model_info,omitempty"`
+	Capabilities []string               `json:"capabilities,omitempty"`
+	ModifiedAt   time.Time              `json:"modified_at,omitempty"`
+}
+
+// DeleteRequest represents an Ollama /api/delete request
+type DeleteRequest struct {
+	Model string `json:"model"`
+}
+
+// CopyRequest represents an Ollama /api/copy request
+type CopyRequest struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// CreateRequest represents an Ollama /api/create request
+type CreateRequest struct {
+	Model      string                 `json:"model"`
+	From       string                 `json:"from,omitempty"`
+	Adapters   map[string]string      `json:"adapters,omitempty"` // File name to blob digest
+	Template   string                 `json:"template,omitempty"`
+	License    []string               `json:"license,omitempty"`
+	System     string                 `json:"system,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Messages   []Message              `json:"messages,omitempty"`
+	Quantize   string                 `json:"quantize,omitempty"`
+	Stream     bool                   `json:"stream"`
+}
+
+// ProcessResponse represents an Ollama /api/ps response
+type ProcessResponse struct {
+	Models []RunningModel `json:"models"`
+}
+
+// RunningModel represents a model loaded in memory
+type RunningModel struct {
+	Name          string    `json:"name"`
+	Model         string    `json:"model"`
+	Size          int64     `json:"size"`
+	Digest        string    `json:"digest"`
+	Details       Details   `json:"details"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	SizeVRAM      int64     `json:"size_vram"`
+	ContextLength int       `json:"context_length,omitempty"`
+}
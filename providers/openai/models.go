@@ -116,6 +116,8 @@ var OpenAIModels = []gollmx.Model{
 			gollmx.FeatureStreaming,
 			gollmx.FeatureVision,
 			gollmx.FeatureTools,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
 		},
 		ReleaseDate: "2024-12-05",
 	},
@@ -131,6 +133,8 @@ var OpenAIModels = []gollmx.Model{
 		Features: []gollmx.Feature{
 			gollmx.FeatureChat,
 			gollmx.FeatureStreaming,
+			gollmx.FeatureJSON,
+			gollmx.FeatureSystemPrompt,
 		},
 		ReleaseDate: "2024-09-12",
 	},
@@ -5,9 +5,12 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -24,6 +27,7 @@ const (
 
 func init() {
 	gollmx.Register(ProviderID, New)
+	gollmx.RegisterModels(ProviderID, OpenAIModels)
 }
 
 // Client implements the gollmx.LLM interface for OpenAI
@@ -31,6 +35,7 @@ type Client struct {
 	config  *gollmx.Config
 	baseURL string
 	options map[string]interface{}
+	models  *gollmx.ModelCache
 }
 
 // New creates a new OpenAI client
@@ -47,6 +52,7 @@ func New(opts ...gollmx.Option) (gollmx.LLM, error) {
 		config:  config,
 		baseURL: baseURL,
 		options: make(map[string]interface{}),
+		models:  gollmx.NewModelCache(config.ModelCacheTTL),
 	}
 
 	return client, nil
@@ -72,6 +78,11 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// Config returns the client configuration
+func (c *Client) Config() *gollmx.Config {
+	return c.config
+}
+
 // Models returns the list of available models
 func (c *Client) Models() []gollmx.Model {
 	return OpenAIModels
@@ -87,6 +98,50 @@ func (c *Client) GetModel(id string) (*gollmx.Model, error) {
 	return nil, gollmx.NewAPIError(gollmx.ErrorTypeModelNotFound, ProviderID, fmt.Sprintf("model not found: %s", id))
 }
 
+// ListModels returns the models available to the API key from /models,
+// merged with OpenAIModels for pricing and features
+func (c *Client) ListModels(ctx context.Context) ([]gollmx.Model, error) {
+	return c.models.Get(ctx, c.fetchModels)
+}
+
+func (c *Client) fetchModels(ctx context.Context) ([]gollmx.Model, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
+	if err != nil {
+		return nil, c.handleError(err, 0, nil)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleError(nil, resp.StatusCode, respBody)
+	}
+
+	var list openAIModelList
+	if err := json.Unmarshal(respBody, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	live := make([]gollmx.Model, len(list.Data))
+	for i, m := range list.Data {
+		live[i] = gollmx.Model{ID: m.ID, Name: m.ID, Provider: ProviderID}
+		if m.Created > 0 {
+			live[i].ReleaseDate = time.Unix(m.Created, 0).UTC().Format("2006-01-02")
+		}
+	}
+	return gollmx.MergeModels(live, OpenAIModels), nil
+}
+
 // HasFeature checks if a feature is supported
 func (c *Client) HasFeature(feature gollmx.Feature) bool {
 	switch feature {
@@ -137,6 +192,10 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, false)...); err != nil {
+		return nil, err
+	}
+
 	openAIReq := c.convertChatRequest(req)
 
 	body, err := json.Marshal(openAIReq)
@@ -151,7 +210,7 @@ func (c *Client) Chat(ctx context.Context, req *gollmx.ChatRequest) (*gollmx.Cha
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -183,6 +242,10 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 		}
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.ChatFeatures(req, true)...); err != nil {
+		return nil, err
+	}
+
 	openAIReq := c.convertChatRequest(req)
 	openAIReq.Stream = true
 	openAIReq.StreamOptions = &streamOptions{IncludeUsage: true}
@@ -199,7 +262,7 @@ func (c *Client) ChatStream(ctx context.Context, req *gollmx.ChatRequest) (*goll
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -328,9 +391,22 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 		req.Model = "text-embedding-3-small"
 	}
 
+	if err := c.config.CheckModel(c, req.Model, gollmx.FeatureEmbedding); err != nil {
+		return nil, err
+	}
+
 	openAIReq := openAIEmbedRequest{
-		Model: req.Model,
-		Input: req.Input,
+		Model:      req.Model,
+		Input:      req.Input,
+		Dimensions: req.Dimensions,
+	}
+	switch req.EncodingFormat {
+	case "", gollmx.EmbedEncodingFloat:
+	case gollmx.EmbedEncodingBase64:
+		openAIReq.EncodingFormat = "base64"
+	default:
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, ProviderID,
+			fmt.Sprintf("unsupported embedding encoding: %s", req.EncodingFormat))
 	}
 
 	body, err := json.Marshal(openAIReq)
@@ -345,7 +421,7 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 
 	c.setHeaders(httpReq)
 
-	resp, err := c.config.GetHTTPClient().Do(httpReq)
+	resp, err := c.config.HTTPClientFor(ProviderID).Do(httpReq)
 	if err != nil {
 		return nil, c.handleError(err, 0, nil)
 	}
@@ -367,9 +443,13 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 
 	embeddings := make([]gollmx.Embedding, len(openAIResp.Data))
 	for i, d := range openAIResp.Data {
+		vector, err := decodeEmbedding(d.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode embedding: %w", err)
+		}
 		embeddings[i] = gollmx.Embedding{
 			Index:  d.Index,
-			Vector: d.Embedding,
+			Vector: vector,
 		}
 	}
 
@@ -388,6 +468,30 @@ func (c *Client) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.E
 // Helpers
 // =============================================================================
 
+// decodeEmbedding decodes an embedding returned as a float array or, with
+// encoding_format "base64", as little-endian float32 values
+func decodeEmbedding(raw json.RawMessage) ([]float64, error) {
+	var encoded string
+	if json.Unmarshal(raw, &encoded) != nil {
+		var vector []float64
+		err := json.Unmarshal(raw, &vector)
+		return vector, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("base64 embedding has %d bytes, not a multiple of 4", len(data))
+	}
+	vector := make([]float64, len(data)/4)
+	for i := range vector {
+		vector[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+	}
+	return vector, nil
+}
+
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
@@ -558,3 +662,10 @@ func (c *Client) convertChatResponse(resp *openAIChatResponse) *gollmx.ChatRespo
 		Raw: resp,
 	}
 }
+
+// Ensure Client implements optional interfaces
+var (
+	_ gollmx.LLM          = (*Client)(nil)
+	_ gollmx.ModelLister  = (*Client)(nil)
+	_ gollmx.Configurable = (*Client)(nil)
+)
@@ -89,6 +89,47 @@ func TestGetModelNotFound(t *testing.T) {
 	}
 }
 
+func TestListModels(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != "GET" || r.URL.Path != "/models" {
+			t.Errorf("expected GET /models, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("expected bearer auth, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"object":"list","data":[
+			{"id":"gpt-4o","object":"model","created":1715367049,"owned_by":"system"},
+			{"id":"ft:gpt-4o-mini:acme::abc123","object":"model","created":1726000000,"owned_by":"acme"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test-key"))
+	models, err := gollmx.ListModels(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+	if models[0].ID != "gpt-4o" || models[0].InputPrice != 2.50 || !models[0].SupportsFeature(gollmx.FeatureVision) {
+		t.Errorf("expected gpt-4o with static pricing and features, got %+v", models[0])
+	}
+	if models[1].ID != "ft:gpt-4o-mini:acme::abc123" || models[1].Provider != ProviderID || models[1].ReleaseDate != "2024-09-10" {
+		t.Errorf("expected the fine-tuned model from the live list, got %+v", models[1])
+	}
+
+	if _, err := client.(gollmx.ModelLister).ListModels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the model list to be cached, got %d requests", requests)
+	}
+}
+
 func TestHasFeature(t *testing.T) {
 	client, _ := New()
 
@@ -267,6 +308,66 @@ func TestChatWithSystemPrompt(t *testing.T) {
 	}
 }
 
+func TestChatUnsupportedFeature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to be sent")
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test"), gollmx.WithCapabilityChecks(true))
+
+	_, err := client.Chat(context.Background(), &gollmx.ChatRequest{
+		Model:    "text-embedding-3-small",
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: "Hello"}},
+	})
+
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok || apiErr.Type != gollmx.ErrorTypeUnsupportedFeature {
+		t.Fatalf("expected unsupported_feature error, got %v", err)
+	}
+	if apiErr.Provider != ProviderID || apiErr.Param != "model" {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestChatReasoningModel(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openAIChatResponse{
+			ID:      "chatcmpl-123",
+			Model:   "o1",
+			Choices: []openAIChoice{{Message: openAIMessageResp{Role: "assistant", Content: `{"answer":42}`}, FinishReason: "stop"}},
+		})
+	}))
+	defer server.Close()
+
+	// A system message with a JSON response format is sent as before, with
+	// or without capability checks
+	req := &gollmx.ChatRequest{
+		Model: "o1",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleSystem, Content: "Answer in JSON"},
+			{Role: gollmx.RoleUser, Content: "What is 6 x 7?"},
+		},
+		ResponseFormat: &gollmx.ResponseFormat{Type: "json_object"},
+	}
+	for _, checks := range []bool{false, true} {
+		client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test"), gollmx.WithCapabilityChecks(checks))
+		resp, err := client.Chat(context.Background(), req)
+		if err != nil {
+			t.Fatalf("checks=%v: chat failed: %v", checks, err)
+		}
+		if resp.GetContent() != `{"answer":42}` {
+			t.Errorf("checks=%v: unexpected content '%s'", checks, resp.GetContent())
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
 func TestChatWithTools(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req openAIChatRequest
@@ -437,7 +538,7 @@ func TestEmbed(t *testing.T) {
 		response := openAIEmbedResponse{
 			Object: "list",
 			Data: []openAIEmbedData{
-				{Object: "embedding", Index: 0, Embedding: []float64{0.1, 0.2, 0.3, 0.4, 0.5}},
+				{Object: "embedding", Index: 0, Embedding: json.RawMessage(`[0.1, 0.2, 0.3, 0.4, 0.5]`)},
 			},
 			Model: "text-embedding-3-small",
 			Usage: openAIEmbedUsage{PromptTokens: 5, TotalTokens: 5},
@@ -477,8 +578,8 @@ func TestEmbedMultipleInputs(t *testing.T) {
 		response := openAIEmbedResponse{
 			Object: "list",
 			Data: []openAIEmbedData{
-				{Object: "embedding", Index: 0, Embedding: []float64{0.1, 0.2, 0.3}},
-				{Object: "embedding", Index: 1, Embedding: []float64{0.4, 0.5, 0.6}},
+				{Object: "embedding", Index: 0, Embedding: json.RawMessage(`[0.1, 0.2, 0.3]`)},
+				{Object: "embedding", Index: 1, Embedding: json.RawMessage(`[0.4, 0.5, 0.6]`)},
 			},
 			Model: "text-embedding-3-small",
 			Usage: openAIEmbedUsage{PromptTokens: 10, TotalTokens: 10},
@@ -505,6 +606,56 @@ func TestEmbedMultipleInputs(t *testing.T) {
 	}
 }
 
+func TestEmbedDimensionsAndBase64(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIEmbedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Dimensions != 2 {
+			t.Errorf("expected dimensions 2, got %d", req.Dimensions)
+		}
+		if req.EncodingFormat != "base64" {
+			t.Errorf("expected encoding_format 'base64', got '%s'", req.EncodingFormat)
+		}
+
+		// 0.5 and -2 as little-endian float32
+		response := openAIEmbedResponse{
+			Data: []openAIEmbedData{{Index: 0, Embedding: json.RawMessage(`"AAAAPwAAAMA="`)}},
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, _ := New(gollmx.WithBaseURL(server.URL), gollmx.WithAPIKey("test"))
+
+	resp, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
+		Input:          []string{"Hello"},
+		Dimensions:     2,
+		EncodingFormat: gollmx.EmbedEncodingBase64,
+	})
+	if err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+
+	vector := resp.Embeddings[0].Vector
+	if len(vector) != 2 || vector[0] != 0.5 || vector[1] != -2 {
+		t.Errorf("expected [0.5 -2], got %v", vector)
+	}
+}
+
+func TestEmbedUnsupportedEncoding(t *testing.T) {
+	client, _ := New(gollmx.WithAPIKey("test"))
+
+	_, err := client.Embed(context.Background(), &gollmx.EmbedRequest{
+		Input:          []string{"Hello"},
+		EncodingFormat: gollmx.EmbedEncodingInt8,
+	})
+
+	apiErr, ok := err.(*gollmx.APIError)
+	if !ok || apiErr.Type != gollmx.ErrorTypeInvalidRequest {
+		t.Errorf("expected invalid_request error, got %v", err)
+	}
+}
+
 func TestChatWithOrgAndProject(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check org and project headers
@@ -584,3 +735,16 @@ func TestVersion(t *testing.T) {
 		t.Errorf("expected version '1.0.0', got '%s'", client.Version())
 	}
 }
+
+func TestRegisteredModels(t *testing.T) {
+	ref, err := gollmx.ResolveModel("gpt-4o")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.Provider != ProviderID {
+		t.Errorf("expected provider '%s', got '%s'", ProviderID, ref.Provider)
+	}
+	if _, ok := gollmx.DefaultModelRegistry.Lookup(ref); !ok {
+		t.Error("expected the built-in metadata to be registered")
+	}
+}
@@ -68,8 +68,10 @@ type openAIJSONSchema struct {
 }
 
 type openAIEmbedRequest struct {
-	Model string   `json:"model"`
-	Input []string `json:"input"`
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format,omitempty"` // "float" or "base64"
 }
 
 // =============================================================================
@@ -132,9 +134,9 @@ type openAIEmbedResponse struct {
 }
 
 type openAIEmbedData struct {
-	Object    string    `json:"object"`
-	Index     int       `json:"index"`
-	Embedding []float64 `json:"embedding"`
+	Object    string          `json:"object"`
+	Index     int             `json:"index"`
+	Embedding json.RawMessage `json:"embedding"` // Array of floats, or a base64 string
 }
 
 type openAIEmbedUsage struct {
@@ -142,6 +144,16 @@ type openAIEmbedUsage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
+type openAIModelList struct {
+	Data []openAIModel `json:"data"`
+}
+
+type openAIModel struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
 // =============================================================================
 // Error Types
 // =============================================================================
@@ -0,0 +1,312 @@
+package rag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Chunk is a piece of a document that is embedded and retrieved on its own
+type Chunk struct {
+	ID         string                 `json:"id"`          // DocumentID#Index
+	DocumentID string                 `json:"document_id"` // ID of the source document
+	Index      int                    `json:"index"`       // Position within the document
+	Text       string                 `json:"text"`
+	Start      int                    `json:"start"` // Byte offset in the document text
+	End        int                    `json:"end"`   // Byte offset after the chunk
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Chunker splits document text into chunks. Chunkers fill in Text, Start,
+// End and any chunker-specific Metadata; the Pipeline sets the rest.
+type Chunker interface {
+	Chunk(text string) []Chunk
+}
+
+// ChunkerFunc adapts a function to the Chunker interface
+type ChunkerFunc func(text string) []Chunk
+
+// Chunk calls f(text)
+func (f ChunkerFunc) Chunk(text string) []Chunk {
+	return f(text)
+}
+
+// span is a byte range of a text
+type span struct {
+	start, end int
+}
+
+// newChunk returns the chunk for text[start:end] with surrounding whitespace
+// trimmed, or false if it is blank
+func newChunk(text string, start, end int) (Chunk, bool) {
+	s := text[start:end]
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	start += len(s) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	if trimmed == "" {
+		return Chunk{}, false
+	}
+	return Chunk{Text: trimmed, Start: start, End: start + len(trimmed)}, true
+}
+
+// =============================================================================
+// Fixed-Size Chunker
+// =============================================================================
+
+// FixedSizeChunker splits text into chunks of at most Size characters,
+// breaking at whitespace where possible. Consecutive chunks share up to
+// Overlap characters.
+type FixedSizeChunker struct {
+	Size    int
+	Overlap int
+}
+
+// NewFixedSizeChunker creates a fixed-size chunker. Size defaults to 1000
+// characters, and overlap is capped at half the size.
+func NewFixedSizeChunker(size, overlap int) *FixedSizeChunker {
+	if size <= 0 {
+		size = 1000
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap > size/2 {
+		overlap = size / 2
+	}
+	return &FixedSizeChunker{Size: size, Overlap: overlap}
+}
+
+// Chunk splits text into fixed-size chunks
+func (c *FixedSizeChunker) Chunk(text string) []Chunk {
+	return c.chunkSpan(text, span{0, len(text)})
+}
+
+// chunkSpan splits text[s.start:s.end], reporting offsets in text
+func (c *FixedSizeChunker) chunkSpan(text string, s span) []Chunk {
+	// Byte offset of each character, plus the end of the span
+	offsets := make([]int, 0, s.end-s.start+1)
+	for i := s.start; i < s.end; {
+		offsets = append(offsets, i)
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	n := len(offsets)
+	offsets = append(offsets, s.end)
+
+	var chunks []Chunk
+	for start := 0; start < n; {
+		end := start + c.Size
+		if end >= n {
+			end = n
+		} else {
+			// Break after the last whitespace in the second half of the window
+			for i := end; i > start+c.Size/2; i-- {
+				r, _ := utf8.DecodeRuneInString(text[offsets[i-1]:])
+				if unicode.IsSpace(r) {
+					end = i
+					break
+				}
+			}
+		}
+
+		if chunk, ok := newChunk(text, offsets[start], offsets[end]); ok {
+			chunks = append(chunks, chunk)
+		}
+		if end == n {
+			break
+		}
+		start = max(end-c.Overlap, start+1)
+	}
+	return chunks
+}
+
+// =============================================================================
+// Sentence Chunker
+// =============================================================================
+
+// SentenceChunker groups whole sentences into chunks of at most MaxSize
+// characters. Consecutive chunks repeat the last Overlap sentences. A
+// sentence longer than MaxSize is split like FixedSizeChunker.
+type SentenceChunker struct {
+	MaxSize int
+	Overlap int
+}
+
+// NewSentenceChunker creates a sentence chunker. MaxSize defaults to 1000
+// characters.
+func NewSentenceChunker(maxSize, overlap int) *SentenceChunker {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	return &SentenceChunker{MaxSize: maxSize, Overlap: overlap}
+}
+
+// Chunk splits text into chunks of whole sentences
+func (c *SentenceChunker) Chunk(text string) []Chunk {
+	return c.chunkSpan(text, span{0, len(text)})
+}
+
+// chunkSpan splits text[s.start:s.end], reporting offsets in text
+func (c *SentenceChunker) chunkSpan(text string, s span) []Chunk {
+	sentences := splitSentences(text, s)
+
+	var chunks []Chunk
+	for i := 0; i < len(sentences); {
+		// A sentence that does not fit on its own is split by size
+		if length(text, sentences[i]) > c.MaxSize {
+			chunks = append(chunks, NewFixedSizeChunker(c.MaxSize, 0).chunkSpan(text, sentences[i])...)
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < len(sentences) && length(text, span{sentences[i].start, sentences[j].end}) <= c.MaxSize {
+			j++
+		}
+		if chunk, ok := newChunk(text, sentences[i].start, sentences[j-1].end); ok {
+			chunks = append(chunks, chunk)
+		}
+		if j == len(sentences) {
+			break
+		}
+		i = max(j-c.Overlap, i+1)
+	}
+	return chunks
+}
+
+// splitSentences returns the sentences of text[s.start:s.end]. A sentence
+// ends after '.', '!' or '?' followed by whitespace, or at a blank line.
+func splitSentences(text string, s span) []span {
+	var sentences []span
+	start := s.start
+	for i := s.start; i < s.end; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		next := i + size
+
+		end := -1
+		switch {
+		case (r == '.' || r == '!' || r == '?') && next < s.end:
+			if n, _ := utf8.DecodeRuneInString(text[next:]); unicode.IsSpace(n) {
+				end = next
+			}
+		case r == '\n' && strings.HasPrefix(text[next:s.end], "\n"):
+			end = next
+		}
+
+		if end >= 0 {
+			if strings.TrimSpace(text[start:end]) != "" {
+				sentences = append(sentences, span{start, end})
+			}
+			start = end
+		}
+		i = next
+	}
+	if strings.TrimSpace(text[start:s.end]) != "" {
+		sentences = append(sentences, span{start, s.end})
+	}
+	return sentences
+}
+
+// length returns the number of characters in text[s.start:s.end]
+func length(text string, s span) int {
+	return utf8.RuneCountInString(text[s.start:s.end])
+}
+
+// =============================================================================
+// Markdown Chunker
+// =============================================================================
+
+// MarkdownChunker splits markdown at headings so chunks do not span
+// sections. Each chunk's "heading" metadata holds the path of headings above
+// it, e.g. "Install > Linux". Sections longer than MaxSize are split like
+// RecursiveChunker, with Overlap. Headings inside fenced code blocks are
+// ignored.
+type MarkdownChunker struct {
+	MaxSize int
+	Overlap int
+	Length  LengthFunc // Measures MaxSize and Overlap; defaults to Characters
+}
+
+// NewMarkdownChunker creates a markdown chunker. MaxSize defaults to 1000
+// characters.
+func NewMarkdownChunker(maxSize int) *MarkdownChunker {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	return &MarkdownChunker{MaxSize: maxSize, Length: Characters}
+}
+
+// Chunk splits markdown text into chunks by section
+func (c *MarkdownChunker) Chunk(text string) []Chunk {
+	sections := NewRecursiveChunker(c.MaxSize, c.Overlap)
+	if c.Length != nil {
+		sections.Length = c.Length
+	}
+
+	var chunks []Chunk
+	var headings []string // Heading text by level - 1
+	section := span{}
+	path := ""
+
+	flush := func(end int) {
+		section.end = end
+		for _, chunk := range sections.chunkSpan(text, section) {
+			if path != "" {
+				chunk.Metadata = map[string]interface{}{"heading": path}
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	inFence := false
+	for pos := 0; pos < len(text); {
+		lineEnd := strings.IndexByte(text[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text)
+		} else {
+			lineEnd += pos + 1
+		}
+		line := strings.TrimSpace(text[pos:lineEnd])
+
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inFence = !inFence
+		} else if level, title, ok := markdownHeading(line); ok && !inFence {
+			flush(pos)
+			if level > len(headings) {
+				headings = append(headings, make([]string, level-len(headings))...)
+			}
+			headings = append(headings[:level-1], title)
+			path = joinHeadings(headings)
+			section.start = pos
+		}
+		pos = lineEnd
+	}
+	flush(len(text))
+	return chunks
+}
+
+// markdownHeading parses an ATX heading such as "## Install"
+func markdownHeading(line string) (level int, title string, ok bool) {
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	title = strings.TrimSpace(strings.TrimRight(line[level:], "#"))
+	return level, title, true
+}
+
+// joinHeadings joins the non-empty headings of a path
+func joinHeadings(headings []string) string {
+	var parts []string
+	for _, h := range headings {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
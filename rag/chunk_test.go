@@ -0,0 +1,120 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// checkOffsets verifies that every chunk's text is found at its offsets
+func checkOffsets(t *testing.T, text string, chunks []Chunk) {
+	t.Helper()
+	for i, c := range chunks {
+		if text[c.Start:c.End] != c.Text {
+			t.Errorf("chunk %d: expected text at %d-%d to be %q, got %q", i, c.Start, c.End, c.Text, text[c.Start:c.End])
+		}
+	}
+}
+
+func TestFixedSizeChunker(t *testing.T) {
+	text := strings.Repeat("lorem ipsum dolor sit amet ", 20)
+	chunks := NewFixedSizeChunker(50, 10).Chunk(text)
+
+	if len(chunks) < 10 {
+		t.Fatalf("expected at least 10 chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c.Text); n > 50 {
+			t.Errorf("chunk %d: expected at most 50 characters, got %d", i, n)
+		}
+		if strings.HasPrefix(c.Text, " ") || strings.HasSuffix(c.Text, " ") {
+			t.Errorf("chunk %d: expected trimmed text, got %q", i, c.Text)
+		}
+	}
+	if chunks[1].Start >= chunks[0].End {
+		t.Errorf("expected consecutive chunks to overlap, got %d-%d and %d-%d", chunks[0].Start, chunks[0].End, chunks[1].Start, chunks[1].End)
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestFixedSizeChunkerMultibyte(t *testing.T) {
+	text := strings.Repeat("日本語", 10)
+	chunks := NewFixedSizeChunker(7, 0).Chunk(text)
+
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks of 7 characters or fewer, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if !utf8.ValidString(c.Text) {
+			t.Errorf("chunk %d: expected valid UTF-8, got %q", i, c.Text)
+		}
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestSentenceChunker(t *testing.T) {
+	text := "First sentence here. Second one! Is this the third? Fourth.\n\nA new paragraph"
+	chunks := NewSentenceChunker(40, 0).Chunk(text)
+
+	want := []string{"First sentence here. Second one!", "Is this the third? Fourth.", "A new paragraph"}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.Text != want[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, want[i], c.Text)
+		}
+	}
+	checkOffsets(t, text, chunks)
+
+	overlapping := NewSentenceChunker(40, 1).Chunk(text)
+	if !strings.HasPrefix(overlapping[1].Text, "Second one!") {
+		t.Errorf("expected the second chunk to repeat the last sentence, got %q", overlapping[1].Text)
+	}
+}
+
+func TestSentenceChunkerLongSentence(t *testing.T) {
+	text := "Short. " + strings.Repeat("word ", 30) + "end."
+	chunks := NewSentenceChunker(40, 0).Chunk(text)
+
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c.Text); n > 40 {
+			t.Errorf("chunk %d: expected at most 40 characters, got %d", i, n)
+		}
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestMarkdownChunker(t *testing.T) {
+	text := `Intro text.
+
+# Install
+
+Run the installer.
+
+## Linux
+
+Use the package manager.
+
+` + "```sh\n# not a heading\napt install tool\n```" + `
+
+# Usage
+
+Run it.
+`
+	chunks := NewMarkdownChunker(1000).Chunk(text)
+
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 sections, got %d: %+v", len(chunks), chunks)
+	}
+	headings := []interface{}{nil, "Install", "Install > Linux", "Usage"}
+	for i, c := range chunks {
+		if c.Metadata["heading"] != headings[i] {
+			t.Errorf("chunk %d: expected heading %v, got %v", i, headings[i], c.Metadata["heading"])
+		}
+	}
+	if !strings.Contains(chunks[2].Text, "# not a heading") {
+		t.Errorf("expected the code block to stay in its section, got %q", chunks[2].Text)
+	}
+	checkOffsets(t, text, chunks)
+}
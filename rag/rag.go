@@ -0,0 +1,420 @@
+// Package rag implements retrieval-augmented generation: documents are split
+// into chunks, embedded and stored, and the chunks most similar to a
+// question are added to the prompt with numbered citations.
+//
+//	pipeline, err := rag.New(embedder, nil, chat,
+//		rag.WithEmbedModel("text-embedding-3-small"),
+//		rag.WithChunker(rag.NewMarkdownChunker(800)),
+//	)
+//	_, err = pipeline.Index(ctx, rag.Document{ID: "handbook", Text: handbook})
+//
+//	resp, err := pipeline.Ask(ctx, "How many vacation days do I get?")
+//	fmt.Println(resp.GetContent())
+//	for _, src := range resp.Cited {
+//		fmt.Printf("[%d] %s\n", src.N, src.DocumentID)
+//	}
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/onlyhyde/gollm-x/vector"
+)
+
+// Document is a text to index
+type Document struct {
+	ID       string                 `json:"id"`
+	Text     string                 `json:"text"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"` // Copied to every chunk
+}
+
+// PromptData is passed to the prompt template
+type PromptData struct {
+	Question string
+	Sources  []Source
+}
+
+// DefaultPromptTemplate renders the retrieved sources into a system prompt
+// that asks the model to cite them by number
+var DefaultPromptTemplate = template.Must(template.New("rag").Parse(`Answer the user's question using the numbered sources below. Cite the sources you use by their number in square brackets, e.g. [1]. If the sources do not contain the answer, say that you don't know.
+
+Sources:
+{{range .Sources}}
+[{{.N}}] {{.Text}}
+{{end}}`))
+
+// Config holds pipeline settings
+type Config struct {
+	EmbedModel string             // Model used to embed chunks and questions
+	ChatModel  string             // Model used when a request does not set one
+	Chunker    Chunker            // Splits documents into chunks
+	TopK       int                // Number of chunks to retrieve
+	MinScore   float64            // Chunks scoring below this are dropped
+	Prompt     *template.Template // Renders PromptData into the system prompt
+	EmbedOpts  []gollmx.EmbedBatchOption
+}
+
+// DefaultConfig returns the default pipeline configuration
+func DefaultConfig() Config {
+	return Config{
+		Chunker: NewSentenceChunker(1000, 1),
+		TopK:    4,
+		Prompt:  DefaultPromptTemplate,
+	}
+}
+
+// Option is a function that configures a Pipeline
+type Option func(*Config)
+
+// WithEmbedModel sets the embedding model
+func WithEmbedModel(model string) Option {
+	return func(c *Config) {
+		c.EmbedModel = model
+	}
+}
+
+// WithChatModel sets the chat model used when a request does not set one
+func WithChatModel(model string) Option {
+	return func(c *Config) {
+		c.ChatModel = model
+	}
+}
+
+// WithChunker sets how documents are split into chunks
+func WithChunker(chunker Chunker) Option {
+	return func(c *Config) {
+		c.Chunker = chunker
+	}
+}
+
+// WithTopK sets the number of chunks retrieved for each question
+func WithTopK(k int) Option {
+	return func(c *Config) {
+		c.TopK = k
+	}
+}
+
+// WithMinScore drops retrieved chunks whose score is below min. Only use it
+// with stores whose scores are similarities.
+func WithMinScore(min float64) Option {
+	return func(c *Config) {
+		c.MinScore = min
+	}
+}
+
+// WithPromptTemplate sets the template that renders PromptData into the
+// system prompt
+func WithPromptTemplate(tmpl *template.Template) Option {
+	return func(c *Config) {
+		c.Prompt = tmpl
+	}
+}
+
+// WithEmbedBatchOptions configures the EmbedBatcher used to index documents
+func WithEmbedBatchOptions(opts ...gollmx.EmbedBatchOption) Option {
+	return func(c *Config) {
+		c.EmbedOpts = append(c.EmbedOpts, opts...)
+	}
+}
+
+// Pipeline indexes documents and answers questions from them
+type Pipeline struct {
+	embedder gollmx.LLM
+	store    Store
+	chat     gollmx.LLM
+	batcher  *gollmx.EmbedBatcher
+	config   Config
+}
+
+// Response is a chat response annotated with the sources it was given
+type Response struct {
+	*gollmx.ChatResponse
+	Sources []Source `json:"sources"` // Sources in the prompt, numbered from 1
+	Cited   []Source `json:"cited"`   // Sources cited in the answer by [n]
+}
+
+// New creates a pipeline that embeds with embedder, stores chunks in store
+// and answers with chat. A nil store uses a new MemoryStore.
+func New(embedder gollmx.LLM, store Store, chat gollmx.LLM, opts ...Option) (*Pipeline, error) {
+	if embedder == nil || chat == nil {
+		return nil, errors.New("rag: embedder and chat clients are required")
+	}
+	if !embedder.HasFeature(gollmx.FeatureEmbedding) {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, embedder.ID(), "rag requires an embedder that supports embeddings")
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.Chunker == nil {
+		config.Chunker = DefaultConfig().Chunker
+	}
+	if config.TopK <= 0 {
+		config.TopK = DefaultConfig().TopK
+	}
+	if config.Prompt == nil {
+		config.Prompt = DefaultPromptTemplate
+	}
+	if store == nil {
+		store = NewMemoryStore(nil)
+	}
+
+	return &Pipeline{
+		embedder: embedder,
+		store:    store,
+		chat:     chat,
+		batcher:  gollmx.NewEmbedBatcher(embedder, config.EmbedOpts...),
+		config:   config,
+	}, nil
+}
+
+// Store returns the pipeline's store
+func (p *Pipeline) Store() Store {
+	return p.store
+}
+
+// Index chunks, embeds and stores documents, replacing any chunks previously
+// indexed for the same document IDs. It returns the stored chunks.
+func (p *Pipeline) Index(ctx context.Context, docs ...Document) ([]Chunk, error) {
+	var chunks []Chunk
+	var ids []string
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return nil, errors.New("rag: document ID is required")
+		}
+		ids = append(ids, doc.ID)
+
+		for i, chunk := range p.config.Chunker.Chunk(doc.Text) {
+			chunk.ID = fmt.Sprintf("%s#%d", doc.ID, i)
+			chunk.DocumentID = doc.ID
+			chunk.Index = i
+			chunk.Metadata = mergeMetadata(doc.Metadata, chunk.Metadata)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	if len(chunks) == 0 {
+		return nil, p.store.DeleteDocuments(ctx, ids...)
+	}
+
+	texts := Inputs(chunks)
+	resp, err := p.batcher.Embed(ctx, &gollmx.EmbedRequest{
+		Model:     p.config.EmbedModel,
+		Input:     texts,
+		InputType: gollmx.EmbedInputSearchDocument,
+	})
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := embeddingVectors(resp, len(texts))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.store.DeleteDocuments(ctx, ids...); err != nil {
+		return nil, err
+	}
+	if err := p.store.Add(ctx, chunks, vectors); err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// Delete removes documents from the store
+func (p *Pipeline) Delete(ctx context.Context, documentIDs ...string) error {
+	return p.store.DeleteDocuments(ctx, documentIDs...)
+}
+
+// Retrieve returns the chunks most relevant to query, numbered from 1. A
+// non-empty filter restricts the search to chunks with matching metadata.
+func (p *Pipeline) Retrieve(ctx context.Context, query string, filter map[string]interface{}) ([]Source, error) {
+	resp, err := p.embedder.Embed(ctx, &gollmx.EmbedRequest{
+		Model:     p.config.EmbedModel,
+		Input:     []string{query},
+		InputType: gollmx.EmbedInputSearchQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := embeddingVectors(resp, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := p.store.Search(ctx, vectors[0], p.config.TopK, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]Source, 0, len(found))
+	for _, src := range found {
+		if p.config.MinScore != 0 && src.Score < p.config.MinScore {
+			continue
+		}
+		src.N = len(sources) + 1
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+// Ask answers a single question from the indexed documents
+func (p *Pipeline) Ask(ctx context.Context, question string) (*Response, error) {
+	return p.Chat(ctx, &gollmx.ChatRequest{
+		Messages: []gollmx.Message{{Role: gollmx.RoleUser, Content: question}},
+	})
+}
+
+// Chat retrieves sources for the last user message of req and answers it
+// with them in the system prompt
+func (p *Pipeline) Chat(ctx context.Context, req *gollmx.ChatRequest) (*Response, error) {
+	question, ok := lastUserText(req.Messages)
+	if !ok {
+		return nil, gollmx.NewAPIError(gollmx.ErrorTypeInvalidRequest, p.chat.ID(), "rag requires the last message to be a text user message")
+	}
+
+	sources, err := p.Retrieve(ctx, question, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.Generate(ctx, req, sources)
+}
+
+// Generate answers req with sources rendered into the system prompt. Use it
+// with Retrieve to filter or rework sources before answering. Sources are
+// renumbered from 1 in the order given.
+func (p *Pipeline) Generate(ctx context.Context, req *gollmx.ChatRequest, sources []Source) (*Response, error) {
+	sources = append([]Source(nil), sources...)
+	for i := range sources {
+		sources[i].N = i + 1
+	}
+
+	question, _ := lastUserText(req.Messages)
+	var prompt strings.Builder
+	if err := p.config.Prompt.Execute(&prompt, PromptData{Question: question, Sources: sources}); err != nil {
+		return nil, fmt.Errorf("rag: failed to render prompt: %w", err)
+	}
+
+	augmented := *req
+	augmented.Messages = withSystemPrompt(req.Messages, prompt.String())
+	if augmented.Model == "" {
+		augmented.Model = p.config.ChatModel
+	}
+
+	resp, err := p.chat.Chat(ctx, &augmented)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		ChatResponse: resp,
+		Sources:      sources,
+		Cited:        citedSources(resp.GetContent(), sources),
+	}, nil
+}
+
+// embeddingVectors returns the float32 vectors of resp in input order
+func embeddingVectors(resp *gollmx.EmbedResponse, n int) ([][]float32, error) {
+	if resp == nil || len(resp.Embeddings) != n {
+		return nil, fmt.Errorf("rag: expected %d embeddings", n)
+	}
+	// Place embeddings by index unless the indices are not a permutation
+	indexed := true
+	seen := make(map[int]bool, n)
+	for _, emb := range resp.Embeddings {
+		if emb.Index < 0 || emb.Index >= n || seen[emb.Index] {
+			indexed = false
+			break
+		}
+		seen[emb.Index] = true
+	}
+
+	vectors := make([][]float32, n)
+	for i, emb := range resp.Embeddings {
+		if len(emb.Vector) == 0 {
+			return nil, fmt.Errorf("rag: embedding %d has no float vector", i)
+		}
+		if indexed {
+			i = emb.Index
+		}
+		vectors[i] = vector.ToFloat32(emb.Vector)
+	}
+	return vectors, nil
+}
+
+// mergeMetadata returns doc metadata overridden by chunk metadata
+func mergeMetadata(doc, chunk map[string]interface{}) map[string]interface{} {
+	if len(doc) == 0 && len(chunk) == 0 {
+		return nil
+	}
+	merged := make(map[string]interface{}, len(doc)+len(chunk))
+	for k, v := range doc {
+		merged[k] = v
+	}
+	for k, v := range chunk {
+		merged[k] = v
+	}
+	return merged
+}
+
+// lastUserText returns the text of the last message if it is a user message
+// without non-text parts
+func lastUserText(messages []gollmx.Message) (string, bool) {
+	if len(messages) == 0 || messages[len(messages)-1].Role != gollmx.RoleUser {
+		return "", false
+	}
+	switch content := messages[len(messages)-1].Content.(type) {
+	case string:
+		return content, strings.TrimSpace(content) != ""
+	case []gollmx.ContentPart:
+		var sb strings.Builder
+		for _, part := range content {
+			if part.Type == "text" {
+				sb.WriteString(part.Text)
+			}
+		}
+		return sb.String(), strings.TrimSpace(sb.String()) != ""
+	}
+	return "", false
+}
+
+// withSystemPrompt adds prompt to the leading system message, or prepends a
+// system message if there is none
+func withSystemPrompt(messages []gollmx.Message, prompt string) []gollmx.Message {
+	out := make([]gollmx.Message, 0, len(messages)+1)
+	if len(messages) > 0 && messages[0].Role == gollmx.RoleSystem {
+		if text, ok := messages[0].Content.(string); ok {
+			system := messages[0]
+			system.Content = text + "\n\n" + prompt
+			return append(append(out, system), messages[1:]...)
+		}
+	}
+	out = append(out, gollmx.Message{Role: gollmx.RoleSystem, Content: prompt})
+	return append(out, messages...)
+}
+
+var citationPattern = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// citedSources returns the sources referenced by [n] or [n, m] markers in
+// text, in order of first citation
+func citedSources(text string, sources []Source) []Source {
+	seen := make(map[int]bool)
+	var cited []Source
+	for _, match := range citationPattern.FindAllStringSubmatch(text, -1) {
+		for _, field := range strings.Split(match[1], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(sources) || seen[n] {
+				continue
+			}
+			seen[n] = true
+			cited = append(cited, sources[n-1])
+		}
+	}
+	return cited
+}
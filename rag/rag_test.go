@@ -0,0 +1,182 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+	"github.com/onlyhyde/gollm-x/gollmxtest"
+)
+
+// keywordEmbedder embeds text by counting topic keywords
+type keywordEmbedder struct {
+	*gollmxtest.MockLLM
+	requests []*gollmx.EmbedRequest
+}
+
+var keywords = []string{"vacation", "password", "deploy"}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, req *gollmx.EmbedRequest) (*gollmx.EmbedResponse, error) {
+	e.requests = append(e.requests, req)
+	resp := &gollmx.EmbedResponse{Model: req.Model}
+	for i, input := range req.Input {
+		v := make([]float64, len(keywords)+1)
+		v[len(keywords)] = 0.1
+		for j, kw := range keywords {
+			v[j] = float64(strings.Count(strings.ToLower(input), kw))
+		}
+		resp.Embeddings = append(resp.Embeddings, gollmx.Embedding{Index: i, Vector: v})
+	}
+	return resp, nil
+}
+
+func newTestPipeline(t *testing.T, opts ...Option) (*Pipeline, *keywordEmbedder, *gollmxtest.MockLLM) {
+	t.Helper()
+	embedder := &keywordEmbedder{MockLLM: gollmxtest.NewMockLLM("embedder")}
+	chat := gollmxtest.NewMockLLM("chat")
+	pipeline, err := New(embedder, nil, chat, append([]Option{WithChunker(NewSentenceChunker(60, 0)), WithTopK(2)}, opts...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = pipeline.Index(context.Background(),
+		Document{ID: "hr", Text: "Employees get 25 vacation days. Vacation requests go to your manager.", Metadata: map[string]interface{}{"team": "hr"}},
+		Document{ID: "it", Text: "Reset your password in the portal. Deploy tools live in the wiki.", Metadata: map[string]interface{}{"team": "it"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return pipeline, embedder, chat
+}
+
+func TestPipelineIndex(t *testing.T) {
+	pipeline, embedder, _ := newTestPipeline(t)
+
+	store := pipeline.Store().(*MemoryStore)
+	if store.Len() != 4 {
+		t.Fatalf("expected 4 chunks, got %d", store.Len())
+	}
+	if embedder.requests[0].InputType != gollmx.EmbedInputSearchDocument {
+		t.Errorf("expected documents to be embedded as search documents, got %q", embedder.requests[0].InputType)
+	}
+
+	// Reindexing replaces the document's chunks
+	if _, err := pipeline.Index(context.Background(), Document{ID: "hr", Text: "Vacation policy moved."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.Len() != 3 {
+		t.Errorf("expected reindexing to leave 3 chunks, got %d", store.Len())
+	}
+
+	if err := pipeline.Delete(context.Background(), "it"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected 1 chunk after delete, got %d", store.Len())
+	}
+}
+
+func TestPipelineRetrieve(t *testing.T) {
+	pipeline, embedder, _ := newTestPipeline(t)
+	ctx := context.Background()
+
+	sources, err := pipeline.Retrieve(ctx, "How do I reset my password?", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sources) != 2 || sources[0].ID != "it#0" || sources[0].N != 1 {
+		t.Fatalf("expected it#0 as source 1, got %+v", sources)
+	}
+	if sources[0].Metadata["team"] != "it" {
+		t.Errorf("expected document metadata on the chunk, got %v", sources[0].Metadata)
+	}
+	if last := embedder.requests[len(embedder.requests)-1]; last.InputType != gollmx.EmbedInputSearchQuery {
+		t.Errorf("expected the question to be embedded as a search query, got %q", last.InputType)
+	}
+
+	sources, err = pipeline.Retrieve(ctx, "password", map[string]interface{}{"team": "hr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, src := range sources {
+		if src.DocumentID != "hr" {
+			t.Errorf("expected only hr chunks, got %s", src.ID)
+		}
+	}
+}
+
+func TestPipelineAsk(t *testing.T) {
+	pipeline, _, chat := newTestPipeline(t, WithChatModel("chat-model"))
+	chat.QueueText("You get 25 vacation days [1].")
+
+	resp, err := pipeline.Ask(context.Background(), "How many vacation days do I get?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := chat.LastChatRequest()
+	if req.Model != "chat-model" {
+		t.Errorf("expected the configured chat model, got %q", req.Model)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != gollmx.RoleSystem {
+		t.Fatalf("expected a system prompt before the question, got %+v", req.Messages)
+	}
+	if len(resp.Sources) != 2 || resp.Sources[0].DocumentID != "hr" || resp.Sources[1].DocumentID != "hr" {
+		t.Fatalf("expected both hr chunks as sources, got %+v", resp.Sources)
+	}
+	system := req.Messages[0].Content.(string)
+	if !strings.Contains(system, "[1] "+resp.Sources[0].Text) || !strings.Contains(system, "[2] "+resp.Sources[1].Text) {
+		t.Errorf("expected numbered sources in the system prompt, got %q", system)
+	}
+	if len(resp.Cited) != 1 || resp.Cited[0].ID != resp.Sources[0].ID {
+		t.Errorf("expected source 1 to be cited, got %+v", resp.Cited)
+	}
+}
+
+func TestPipelineChatKeepsSystemPrompt(t *testing.T) {
+	pipeline, _, chat := newTestPipeline(t, WithPromptTemplate(nil))
+	chat.QueueText("ok")
+
+	_, err := pipeline.Chat(context.Background(), &gollmx.ChatRequest{
+		Model: "override",
+		Messages: []gollmx.Message{
+			{Role: gollmx.RoleSystem, Content: "Be brief."},
+			{Role: gollmx.RoleUser, Content: "deploy?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := chat.LastChatRequest()
+	if req.Model != "override" {
+		t.Errorf("expected the request model to be kept, got %q", req.Model)
+	}
+	if len(req.Messages) != 2 || !strings.HasPrefix(req.Messages[0].Content.(string), "Be brief.\n\n") {
+		t.Errorf("expected the sources to be added to the existing system prompt, got %+v", req.Messages)
+	}
+
+	if _, err := pipeline.Chat(context.Background(), &gollmx.ChatRequest{
+		Messages: []gollmx.Message{{Role: gollmx.RoleAssistant, Content: "hi"}},
+	}); err == nil {
+		t.Error("expected an error when the last message is not from the user")
+	}
+}
+
+func TestNewRequiresEmbedding(t *testing.T) {
+	embedder := gollmxtest.NewMockLLM("embedder").SetFeatures(gollmx.FeatureChat)
+	if _, err := New(embedder, nil, embedder); err == nil {
+		t.Error("expected an error for an embedder without embedding support")
+	}
+}
+
+func TestCitedSources(t *testing.T) {
+	sources := []Source{{N: 1}, {N: 2}, {N: 3}}
+	sources[0].ID, sources[1].ID, sources[2].ID = "a", "b", "c"
+
+	cited := citedSources("See [3] and [1, 2]. Also [3] again and [9].", sources)
+	if len(cited) != 3 || cited[0].ID != "c" || cited[1].ID != "a" || cited[2].ID != "b" {
+		t.Errorf("expected c, a, b, got %+v", cited)
+	}
+}
@@ -0,0 +1,255 @@
+package rag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// LengthFunc measures text, e.g. in characters or tokens
+type LengthFunc func(text string) int
+
+// Characters returns the number of characters in text
+func Characters(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// EstimateTokens approximates the token count of text with
+// gollmx.EstimateTokens, the estimate EmbedBatcher uses
+func EstimateTokens(text string) int {
+	return gollmx.EstimateTokens(text)
+}
+
+// DefaultSeparators split by paragraph, line, sentence, word and finally
+// character
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " ", ""}
+
+// RecursiveChunker splits text at the first separator that produces pieces
+// of at most Size, splitting oversized pieces with the following separators,
+// then merges adjacent pieces back up to Size. Consecutive chunks share up
+// to Overlap of trailing pieces.
+type RecursiveChunker struct {
+	Size       int
+	Overlap    int
+	Separators []string   // Tried in order; "" splits between characters
+	Length     LengthFunc // Measures Size and Overlap; defaults to Characters
+	Metadata   map[string]interface{}
+}
+
+// NewRecursiveChunker creates a recursive chunker measuring characters with
+// DefaultSeparators. Size defaults to 1000, and overlap is capped at half
+// the size.
+func NewRecursiveChunker(size, overlap int) *RecursiveChunker {
+	if size <= 0 {
+		size = 1000
+	}
+	overlap = min(max(overlap, 0), size/2)
+	return &RecursiveChunker{Size: size, Overlap: overlap, Separators: DefaultSeparators, Length: Characters}
+}
+
+// NewTokenChunker creates a recursive chunker whose chunks fit the context
+// window of model, measured with count. A nil count uses EstimateTokens.
+// Lower Size to retrieve smaller passages.
+func NewTokenChunker(model gollmx.Model, count LengthFunc, overlap int) *RecursiveChunker {
+	size := model.ContextWindow
+	if size <= 0 {
+		size = 512
+	}
+	c := NewRecursiveChunker(size, overlap)
+	c.Length = count
+	if c.Length == nil {
+		c.Length = EstimateTokens
+	}
+	return c
+}
+
+// Chunk splits text recursively
+func (c *RecursiveChunker) Chunk(text string) []Chunk {
+	return c.chunkSpan(text, span{0, len(text)})
+}
+
+// chunkSpan splits text[s.start:s.end], reporting offsets in text
+func (c *RecursiveChunker) chunkSpan(text string, s span) []Chunk {
+	length := c.Length
+	if length == nil {
+		length = Characters
+	}
+	separators := c.Separators
+	if len(separators) == 0 {
+		separators = DefaultSeparators
+	}
+
+	var chunks []Chunk
+	for _, piece := range c.merge(c.split(text, s, separators, length)) {
+		if chunk, ok := newChunk(text, piece.start, piece.end); ok {
+			if len(c.Metadata) > 0 {
+				chunk.Metadata = mergeMetadata(c.Metadata, nil)
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// measured is a piece of text and its length
+type measured struct {
+	span
+	length int
+}
+
+// split breaks s into pieces of at most Size where the separators allow
+func (c *RecursiveChunker) split(text string, s span, separators []string, length LengthFunc) []measured {
+	n := length(text[s.start:s.end])
+	if n <= c.Size || len(separators) == 0 {
+		return []measured{{s, n}}
+	}
+
+	sep, rest := separators[0], separators[1:]
+	if sep != "" && !strings.Contains(text[s.start:s.end], sep) {
+		return c.split(text, s, rest, length)
+	}
+
+	var pieces []measured
+	for _, part := range splitAt(text, s, sep) {
+		pieces = append(pieces, c.split(text, part, rest, length)...)
+	}
+	return pieces
+}
+
+// splitAt splits s at each occurrence of sep. Separators such as "\nfunc "
+// that introduce a declaration start the following piece; other separators
+// end the preceding one. An empty sep splits between characters.
+func splitAt(text string, s span, sep string) []span {
+	var parts []span
+	if sep == "" {
+		for i := s.start; i < s.end; {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			parts = append(parts, span{i, i + size})
+			i += size
+		}
+		return parts
+	}
+
+	// Offset of the cut within the separator
+	cut := len(sep)
+	if strings.IndexFunc(sep, unicode.IsLetter) >= 0 {
+		cut = len(sep) - len(strings.TrimLeft(sep, "\n"))
+	}
+
+	start, from := s.start, s.start
+	for {
+		i := strings.Index(text[from:s.end], sep)
+		if i < 0 {
+			break
+		}
+		at := from + i + cut
+		if at > start {
+			parts = append(parts, span{start, at})
+			start = at
+		}
+		from += i + len(sep)
+	}
+	if start < s.end {
+		parts = append(parts, span{start, s.end})
+	}
+	return parts
+}
+
+// merge joins adjacent pieces into chunks of at most Size, starting each
+// chunk with up to Overlap of the previous chunk's trailing pieces
+func (c *RecursiveChunker) merge(pieces []measured) []span {
+	var chunks []span
+	var current []measured
+	total := 0
+
+	for _, piece := range pieces {
+		if len(current) > 0 && total+piece.length > c.Size {
+			chunks = append(chunks, span{current[0].start, current[len(current)-1].end})
+
+			// Keep trailing pieces for overlap, leaving room for the next piece
+			for len(current) > 0 && (total > c.Overlap || total+piece.length > c.Size) {
+				total -= current[0].length
+				current = current[1:]
+			}
+		}
+		current = append(current, piece)
+		total += piece.length
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, span{current[0].start, current[len(current)-1].end})
+	}
+	return chunks
+}
+
+// =============================================================================
+// Code Chunker
+// =============================================================================
+
+// Language is a programming language understood by NewCodeChunker
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageJavaScript Language = "javascript" // Also used for TypeScript
+	LanguageJava       Language = "java"
+	LanguageRust       Language = "rust"
+	LanguageC          Language = "c" // Also used for C++
+)
+
+// codeSeparators split before top-level declarations, then blocks, lines
+// and words
+var codeSeparators = map[Language][]string{
+	LanguageGo:         {"\nfunc ", "\ntype ", "\nvar ", "\nconst ", "\n\n", "\n", " ", ""},
+	LanguagePython:     {"\nclass ", "\ndef ", "\nasync def ", "\n    def ", "\n    async def ", "\n\n", "\n", " ", ""},
+	LanguageJavaScript: {"\nexport ", "\nfunction ", "\nclass ", "\nconst ", "\nlet ", "\ninterface ", "\ntype ", "\n\n", "\n", " ", ""},
+	LanguageJava:       {"\npublic ", "\nprivate ", "\nprotected ", "\nclass ", "\ninterface ", "\n    public ", "\n    private ", "\n    protected ", "\n\n", "\n", " ", ""},
+	LanguageRust:       {"\npub fn ", "\nfn ", "\npub struct ", "\nstruct ", "\nimpl ", "\nenum ", "\ntrait ", "\nmod ", "\n\n", "\n", " ", ""},
+	LanguageC:          {"\nstruct ", "\ntypedef ", "\nstatic ", "\nvoid ", "\nint ", "\nclass ", "\nnamespace ", "\n\n", "\n", " ", ""},
+}
+
+// CodeSeparators returns the separators NewCodeChunker uses for language,
+// or DefaultSeparators for an unknown language
+func CodeSeparators(language Language) []string {
+	if seps, ok := codeSeparators[language]; ok {
+		return append([]string(nil), seps...)
+	}
+	return append([]string(nil), DefaultSeparators...)
+}
+
+// NewCodeChunker creates a recursive chunker that keeps functions and types
+// of language together where they fit. Chunks carry a "language" metadata
+// entry.
+func NewCodeChunker(language Language, size, overlap int) *RecursiveChunker {
+	c := NewRecursiveChunker(size, overlap)
+	c.Separators = CodeSeparators(language)
+	c.Metadata = map[string]interface{}{"language": string(language)}
+	return c
+}
+
+// =============================================================================
+// Embedding Inputs
+// =============================================================================
+
+// Inputs returns the text of each chunk for EmbedRequest.Input
+func Inputs(chunks []Chunk) []string {
+	inputs := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		inputs[i] = chunk.Text
+	}
+	return inputs
+}
+
+// Batches groups chunks into batches within limits, such as those from
+// gollmx.DefaultEmbedLimits, so each batch's Inputs fit one EmbedRequest.
+// Batches are split like gollmx.SplitEmbedBatches, counting tokens with
+// count or EstimateTokens if nil.
+func Batches(chunks []Chunk, limits gollmx.EmbedLimits, count LengthFunc) [][]Chunk {
+	var batches [][]Chunk
+	for _, batch := range gollmx.SplitEmbedBatches(Inputs(chunks), limits, count) {
+		batches = append(batches, chunks[batch.Start:batch.End])
+	}
+	return batches
+}
@@ -0,0 +1,122 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func TestRecursiveChunker(t *testing.T) {
+	text := "First paragraph is short.\n\nSecond paragraph has two sentences. It is longer than the limit allows.\n\nThird."
+	chunks := NewRecursiveChunker(45, 0).Chunk(text)
+
+	want := []string{
+		"First paragraph is short.",
+		"Second paragraph has two sentences.",
+		"It is longer than the limit allows.\n\nThird.",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.Text != want[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, want[i], c.Text)
+		}
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestRecursiveChunkerOverlap(t *testing.T) {
+	text := strings.Repeat("alpha beta gamma delta ", 10)
+	chunks := NewRecursiveChunker(30, 12).Chunk(text)
+
+	for i := 1; i < len(chunks); i++ {
+		if chunks[i].Start >= chunks[i-1].End {
+			t.Errorf("chunk %d: expected overlap with the previous chunk, got %d-%d after %d-%d", i, chunks[i].Start, chunks[i].End, chunks[i-1].Start, chunks[i-1].End)
+		}
+		if n := Characters(chunks[i].Text); n > 30 {
+			t.Errorf("chunk %d: expected at most 30 characters, got %d", i, n)
+		}
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestTokenChunker(t *testing.T) {
+	words := func(s string) int { return len(strings.Fields(s)) }
+	model := gollmx.Model{ID: "tiny-embed", ContextWindow: 5}
+
+	chunks := NewTokenChunker(model, words, 0).Chunk("one two three four five six seven eight nine ten eleven")
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks of at most 5 words, got %d: %+v", len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if n := words(c.Text); n > 5 {
+			t.Errorf("chunk %d: expected at most 5 words, got %d", i, n)
+		}
+	}
+
+	if c := NewTokenChunker(gollmx.Model{}, nil, 0); c.Size != 512 || c.Length == nil {
+		t.Errorf("expected a 512 token default with EstimateTokens, got size %d", c.Size)
+	}
+}
+
+func TestCodeChunker(t *testing.T) {
+	text := `package demo
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+`
+	chunks := NewCodeChunker(LanguageGo, 45, 0).Chunk(text)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if !strings.HasPrefix(chunks[1].Text, "func Add") || !strings.HasPrefix(chunks[2].Text, "func Sub") {
+		t.Errorf("expected chunks to start at function declarations, got %q and %q", chunks[1].Text, chunks[2].Text)
+	}
+	if chunks[1].Metadata["language"] != "go" {
+		t.Errorf("expected language metadata, got %v", chunks[1].Metadata)
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestMarkdownChunkerSplitsLongSections(t *testing.T) {
+	text := "# Guide\n\n" + strings.Repeat("A sentence about setup. ", 10)
+	chunks := NewMarkdownChunker(80).Chunk(text)
+
+	if len(chunks) < 3 {
+		t.Fatalf("expected the long section to be split, got %d chunks", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Metadata["heading"] != "Guide" {
+			t.Errorf("chunk %d: expected heading Guide, got %v", i, c.Metadata["heading"])
+		}
+	}
+	checkOffsets(t, text, chunks)
+}
+
+func TestBatches(t *testing.T) {
+	chunks := NewFixedSizeChunker(10, 0).Chunk(strings.Repeat("abcdefghij", 7))
+	if len(chunks) != 7 {
+		t.Fatalf("expected 7 chunks, got %d", len(chunks))
+	}
+
+	batches := Batches(chunks, gollmx.EmbedLimits{MaxItems: 3}, nil)
+	if len(batches) != 3 || len(batches[0]) != 3 || len(batches[2]) != 1 {
+		t.Errorf("expected batches of 3, 3 and 1, got %d batches", len(batches))
+	}
+
+	batches = Batches(chunks, gollmx.EmbedLimits{MaxTokens: 20}, Characters)
+	if len(batches) != 4 {
+		t.Errorf("expected 4 batches of at most 20 characters, got %d", len(batches))
+	}
+	if inputs := Inputs(batches[0]); len(inputs) != 2 || inputs[0] != "abcdefghij" {
+		t.Errorf("expected 2 inputs in the first batch, got %v", inputs)
+	}
+}
@@ -0,0 +1,131 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/onlyhyde/gollm-x/vector"
+)
+
+// Source is a retrieved chunk and its similarity to the query
+type Source struct {
+	Chunk
+	Score float64 `json:"score"`
+	N     int     `json:"n,omitempty"` // Number used to cite the source; set by the Pipeline
+}
+
+// Store holds chunk embeddings for retrieval. Implement it to use an
+// external vector database.
+type Store interface {
+	// Add stores chunks with their embeddings, replacing chunks with the same ID
+	Add(ctx context.Context, chunks []Chunk, vectors [][]float32) error
+	// Search returns up to k chunks closest to query, closest first. A
+	// non-empty filter only returns chunks whose metadata has every given key
+	// and value.
+	Search(ctx context.Context, query []float32, k int, filter map[string]interface{}) ([]Source, error)
+	// DeleteDocuments removes every chunk of the given documents
+	DeleteDocuments(ctx context.Context, documentIDs ...string) error
+}
+
+// MemoryStore is a Store backed by an in-memory vector.Index
+type MemoryStore struct {
+	mu     sync.RWMutex
+	index  vector.Index
+	chunks map[string]Chunk
+	docs   map[string][]string // Chunk IDs by document ID
+}
+
+// NewMemoryStore creates a store backed by index. A nil index uses an exact
+// cosine vector.Flat index.
+func NewMemoryStore(index vector.Index) *MemoryStore {
+	if index == nil {
+		index = vector.NewFlat(vector.MetricCosine)
+	}
+	return &MemoryStore{
+		index:  index,
+		chunks: make(map[string]Chunk),
+		docs:   make(map[string][]string),
+	}
+}
+
+// Add stores chunks with their embeddings
+func (s *MemoryStore) Add(ctx context.Context, chunks []Chunk, vectors [][]float32) error {
+	if len(chunks) != len(vectors) {
+		return fmt.Errorf("rag: %d chunks but %d vectors", len(chunks), len(vectors))
+	}
+
+	items := make([]vector.Item, len(chunks))
+	for i, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(chunk.Metadata)+1)
+		for k, v := range chunk.Metadata {
+			metadata[k] = v
+		}
+		metadata["document_id"] = chunk.DocumentID
+		items[i] = vector.Item{ID: chunk.ID, Vector: vectors[i], Metadata: metadata}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.index.Add(items...); err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, ok := s.chunks[chunk.ID]; !ok {
+			s.docs[chunk.DocumentID] = append(s.docs[chunk.DocumentID], chunk.ID)
+		}
+		s.chunks[chunk.ID] = chunk
+	}
+	return nil
+}
+
+// Search returns up to k chunks closest to query. Chunk metadata can be
+// filtered on along with "document_id".
+func (s *MemoryStore) Search(ctx context.Context, query []float32, k int, filter map[string]interface{}) ([]Source, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var match vector.Filter
+	if len(filter) > 0 {
+		match = vector.Match(filter)
+	}
+	results, err := s.index.Search(query, k, match)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]Source, 0, len(results))
+	for _, r := range results {
+		if chunk, ok := s.chunks[r.ID]; ok {
+			sources = append(sources, Source{Chunk: chunk, Score: r.Score})
+		}
+	}
+	return sources, nil
+}
+
+// DeleteDocuments removes every chunk of the given documents
+func (s *MemoryStore) DeleteDocuments(ctx context.Context, documentIDs ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, docID := range documentIDs {
+		ids := s.docs[docID]
+		s.index.Delete(ids...)
+		for _, id := range ids {
+			delete(s.chunks, id)
+		}
+		delete(s.docs, docID)
+	}
+	return nil
+}
+
+// Len returns the number of stored chunks
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.chunks)
+}
+
+// Ensure MemoryStore implements Store
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,16 @@
+package gollmx
+
+// DefaultRedactHeaders are the credential headers used by the built-in
+// providers. Their values are never logged or recorded.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"Api-Key",
+	"OpenAI-Organization",
+	"OpenAI-Project",
+}
+
+// DefaultRedactQueryParams are the credential query parameters used by the
+// built-in providers. Their values are never logged or recorded.
+var DefaultRedactQueryParams = []string{"key", "api_key"}
@@ -0,0 +1,141 @@
+package gollmx
+
+import (
+	"context"
+	"sort"
+
+	"github.com/onlyhyde/gollm-x/internal/vecmath"
+)
+
+// Reranker is an optional interface for providers that can order documents
+// by relevance to a query. Use type assertion or AsReranker to access it:
+//
+//	if r, ok := client.(gollmx.Reranker); ok {
+//	    resp, err := r.Rerank(ctx, req)
+//	}
+type Reranker interface {
+	Rerank(ctx context.Context, req *RerankRequest) (*RerankResponse, error)
+}
+
+// AsReranker returns a Reranker for client. Wrapped clients are unwrapped to
+// find a native implementation; otherwise an EmbeddingReranker is returned if
+// the client supports embeddings. It returns nil if neither is available.
+func AsReranker(client LLM) Reranker {
+	for c := client; c != nil; {
+		if r, ok := c.(Reranker); ok {
+			return r
+		}
+		u, ok := c.(interface{ Unwrap() LLM })
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+
+	if client.HasFeature(FeatureEmbedding) {
+		return NewEmbeddingReranker(client, "")
+	}
+	return nil
+}
+
+// =============================================================================
+// Embedding Reranker
+// =============================================================================
+
+// EmbeddingReranker is a local fallback Reranker that scores documents by
+// cosine similarity between query and document embeddings
+type EmbeddingReranker struct {
+	client LLM
+	model  string
+}
+
+// NewEmbeddingReranker creates a reranker that embeds with client and model.
+// An empty model uses the provider's default embedding model.
+func NewEmbeddingReranker(client LLM, model string) *EmbeddingReranker {
+	return &EmbeddingReranker{
+		client: client,
+		model:  model,
+	}
+}
+
+// Rerank embeds the query and documents and orders the documents by
+// similarity to the query. The query and documents are embedded in separate
+// requests, as search queries and search documents, for models that embed
+// them differently.
+func (r *EmbeddingReranker) Rerank(ctx context.Context, req *RerankRequest) (*RerankResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = r.model
+	}
+
+	if len(req.Documents) == 0 {
+		return &RerankResponse{Provider: r.client.ID(), Model: model}, nil
+	}
+
+	queryResp, err := r.client.Embed(ctx, &EmbedRequest{
+		Model:     model,
+		Input:     []string{req.Query},
+		InputType: EmbedInputSearchQuery,
+		Extra:     req.Extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	query := embeddingVectors(queryResp, 1)[0]
+	if query == nil {
+		return nil, NewAPIError(ErrorTypeUnknown, r.client.ID(), "embedding response is missing the query vector")
+	}
+
+	docResp, err := r.client.Embed(ctx, &EmbedRequest{
+		Model:     model,
+		Input:     req.Documents,
+		InputType: EmbedInputSearchDocument,
+		Extra:     req.Extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	docs := embeddingVectors(docResp, len(req.Documents))
+
+	results := make([]RerankResult, len(req.Documents))
+	for i, doc := range req.Documents {
+		results[i] = RerankResult{Index: i, Document: doc}
+		// A missing or mismatched vector scores 0
+		if len(docs[i]) == len(query) {
+			results[i].Score = vecmath.Cosine(query, docs[i])
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if req.TopN > 0 && req.TopN < len(results) {
+		results = results[:req.TopN]
+	}
+
+	usage := docResp.Usage
+	usage.PromptTokens += queryResp.Usage.PromptTokens
+	usage.TotalTokens += queryResp.Usage.TotalTokens
+
+	return &RerankResponse{
+		Provider: r.client.ID(),
+		Model:    docResp.Model,
+		Results:  results,
+		Usage:    usage,
+	}, nil
+}
+
+// embeddingVectors returns the n vectors of resp in input order. Vectors
+// missing from the response are nil.
+func embeddingVectors(resp *EmbedResponse, n int) [][]float64 {
+	vectors := make([][]float64, n)
+	for _, emb := range resp.Embeddings {
+		if emb.Index >= 0 && emb.Index < n {
+			vectors[emb.Index] = emb.Vector
+		}
+	}
+	return vectors
+}
+
+// Ensure EmbeddingReranker implements Reranker interface
+var _ Reranker = (*EmbeddingReranker)(nil)
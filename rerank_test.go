@@ -0,0 +1,96 @@
+package gollmx
+
+import (
+	"context"
+	"testing"
+)
+
+// embedMockLLM is a mockLLM that returns fixed vectors for known inputs
+type embedMockLLM struct {
+	mockLLM
+	vectors    map[string][]float64
+	inputTypes []EmbedInputType
+}
+
+func (m *embedMockLLM) HasFeature(feature Feature) bool { return feature == FeatureEmbedding }
+
+func (m *embedMockLLM) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	m.inputTypes = append(m.inputTypes, req.InputType)
+	resp := &EmbedResponse{Provider: m.id, Model: "mock-embed"}
+	for i, text := range req.Input {
+		resp.Embeddings = append(resp.Embeddings, Embedding{Index: i, Vector: m.vectors[text]})
+	}
+	return resp, nil
+}
+
+func newEmbedMock() *embedMockLLM {
+	return &embedMockLLM{
+		mockLLM: mockLLM{id: "mock"},
+		vectors: map[string][]float64{
+			"pets":    {1, 0},
+			"cats":    {0.9, 0.1},
+			"stocks":  {0, 1},
+			"puppies": {0.7, 0.3},
+		},
+	}
+}
+
+func TestEmbeddingReranker(t *testing.T) {
+	client := newEmbedMock()
+	reranker := NewEmbeddingReranker(client, "")
+
+	resp, err := reranker.Rerank(context.Background(), &RerankRequest{
+		Query:     "pets",
+		Documents: []string{"stocks", "puppies", "cats"},
+		TopN:      2,
+	})
+	if err != nil {
+		t.Fatalf("rerank failed: %v", err)
+	}
+
+	// Asymmetric models embed queries and documents differently
+	if len(client.inputTypes) != 2 || client.inputTypes[0] != EmbedInputSearchQuery || client.inputTypes[1] != EmbedInputSearchDocument {
+		t.Errorf("expected a query and a document embed call, got %v", client.inputTypes)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.Results))
+	}
+	if resp.Results[0].Document != "cats" || resp.Results[0].Index != 2 {
+		t.Errorf("expected 'cats' first, got %+v", resp.Results[0])
+	}
+	if resp.Results[1].Document != "puppies" {
+		t.Errorf("expected 'puppies' second, got %+v", resp.Results[1])
+	}
+	if resp.Results[0].Score < resp.Results[1].Score {
+		t.Error("results should be ordered by descending score")
+	}
+}
+
+func TestAsReranker(t *testing.T) {
+	// Plain client without embeddings has no reranker
+	if AsReranker(&mockLLM{}) != nil {
+		t.Error("expected nil reranker for client without embeddings")
+	}
+
+	// Embedding-capable client falls back to the embedding reranker
+	client := newEmbedMock()
+	if _, ok := AsReranker(client).(*EmbeddingReranker); !ok {
+		t.Error("expected embedding reranker fallback")
+	}
+
+	// Native rerankers are found through wrappers
+	native := &rerankMockLLM{mockLLM: mockLLM{id: "native"}}
+	wrapped := WithRetry(NewRateLimitedClient(native, 600))
+	if AsReranker(wrapped) != Reranker(native) {
+		t.Error("expected native reranker to be found through wrappers")
+	}
+}
+
+// rerankMockLLM is a mockLLM that implements Reranker
+type rerankMockLLM struct {
+	mockLLM
+}
+
+func (m *rerankMockLLM) Rerank(ctx context.Context, req *RerankRequest) (*RerankResponse, error) {
+	return &RerankResponse{Provider: m.id}, nil
+}
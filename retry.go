@@ -2,6 +2,7 @@ package gollmx
 
 import (
 	"context"
+	"log/slog"
 	"math"
 	"math/rand"
 	"time"
@@ -15,6 +16,7 @@ type RetryConfig struct {
 	Multiplier     float64       // Multiplier for exponential backoff
 	Jitter         float64       // Random jitter factor (0-1)
 	RetryableTypes []ErrorType   // Error types that should be retried
+	Logger         *slog.Logger  // Logs each retry (nil = no logging)
 }
 
 // DefaultRetryConfig returns a sensible default retry configuration
@@ -79,6 +81,13 @@ func WithRetryableTypes(types ...ErrorType) RetryOption {
 	}
 }
 
+// WithRetryLogger logs each retry with the attempt, delay and error
+func WithRetryLogger(logger *slog.Logger) RetryOption {
+	return func(c *RetryConfig) {
+		c.Logger = logger
+	}
+}
+
 // Retryer handles retry logic with exponential backoff
 type Retryer struct {
 	config *RetryConfig
@@ -127,6 +136,7 @@ func (r *Retryer) Do(ctx context.Context, fn func() error) error {
 
 		// Calculate delay with exponential backoff and jitter
 		delay := r.calculateDelay(attempt, err)
+		r.logRetry(ctx, attempt, delay, err)
 
 		// Wait or return if context is cancelled
 		select {
@@ -171,6 +181,7 @@ func DoWithResult[T any](ctx context.Context, r *Retryer, fn func() (T, error))
 
 		// Calculate delay with exponential backoff and jitter
 		delay := r.calculateDelay(attempt, err)
+		r.logRetry(ctx, attempt, delay, err)
 
 		// Wait or return if context is cancelled
 		select {
@@ -184,6 +195,26 @@ func DoWithResult[T any](ctx context.Context, r *Retryer, fn func() (T, error))
 	return result, lastErr
 }
 
+// logRetry logs that a failed attempt will be retried after delay
+func (r *Retryer) logRetry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	if r.config.Logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.Int("attempt", attempt+1),
+		slog.Int("max_retries", r.config.MaxRetries),
+		slog.Duration("delay", delay),
+		slog.String("error", err.Error()),
+	}
+	if apiErr, ok := err.(*APIError); ok {
+		attrs = append(attrs,
+			slog.String("provider", apiErr.Provider),
+			slog.String("error_type", string(apiErr.Type)),
+		)
+	}
+	r.config.Logger.LogAttrs(ctx, slog.LevelWarn, "retrying llm request", attrs...)
+}
+
 // shouldRetry determines if an error should be retried
 func (r *Retryer) shouldRetry(err error) bool {
 	// Check if it's an APIError with Retryable flag
@@ -299,11 +330,18 @@ type RetryableClient struct {
 	retryer *Retryer
 }
 
-// WithRetry wraps an LLM client with retry logic
+// WithRetry wraps an LLM client with retry logic. Without WithRetryLogger,
+// retries are logged to the client's logger (see Config.GetLogger).
 func WithRetry(client LLM, opts ...RetryOption) *RetryableClient {
+	retryer := NewRetryer(opts...)
+	if retryer.config.Logger == nil {
+		if config := ConfigOf(client); config != nil {
+			retryer.config.Logger = config.GetLogger()
+		}
+	}
 	return &RetryableClient{
 		client:  client,
-		retryer: NewRetryer(opts...),
+		retryer: retryer,
 	}
 }
 
@@ -0,0 +1,450 @@
+package gollmx
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onlyhyde/gollm-x/internal/vecmath"
+)
+
+// SemanticCacheConfig configures a SemanticCacheClient
+type SemanticCacheConfig struct {
+	EmbedModel      string             // Embedding model; empty uses the embedder's default
+	Threshold       float64            // Minimum cosine similarity for a hit
+	ModelThresholds map[string]float64 // Per-model overrides of Threshold
+	Capacity        int                // Maximum entries before LRU eviction
+	TTL             time.Duration      // Entry lifetime; 0 means entries do not expire
+}
+
+// DefaultSemanticCacheConfig returns the default semantic cache configuration
+func DefaultSemanticCacheConfig() *SemanticCacheConfig {
+	return &SemanticCacheConfig{
+		Threshold: 0.95,
+		Capacity:  1000,
+	}
+}
+
+// SemanticCacheOption is a function that configures semantic caching
+type SemanticCacheOption func(*SemanticCacheConfig)
+
+// WithSemanticEmbedModel sets the model used to embed questions
+func WithSemanticEmbedModel(model string) SemanticCacheOption {
+	return func(c *SemanticCacheConfig) {
+		c.EmbedModel = model
+	}
+}
+
+// WithSemanticThreshold sets the minimum cosine similarity for a hit
+func WithSemanticThreshold(threshold float64) SemanticCacheOption {
+	return func(c *SemanticCacheConfig) {
+		c.Threshold = threshold
+	}
+}
+
+// WithSemanticModelThreshold sets the similarity threshold for one model
+func WithSemanticModelThreshold(model string, threshold float64) SemanticCacheOption {
+	return func(c *SemanticCacheConfig) {
+		if c.ModelThresholds == nil {
+			c.ModelThresholds = make(map[string]float64)
+		}
+		c.ModelThresholds[model] = threshold
+	}
+}
+
+// WithSemanticCapacity sets the maximum number of cached answers
+func WithSemanticCapacity(n int) SemanticCacheOption {
+	return func(c *SemanticCacheConfig) {
+		c.Capacity = n
+	}
+}
+
+// WithSemanticTTL sets how long cached answers are served
+func WithSemanticTTL(ttl time.Duration) SemanticCacheOption {
+	return func(c *SemanticCacheConfig) {
+		c.TTL = ttl
+	}
+}
+
+// SemanticCacheStats reports semantic cache activity
+type SemanticCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Skipped   int64 `json:"skipped"` // Requests that could not be looked up
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+// semanticEntry is a cached answer and the embedding of its question
+type semanticEntry struct {
+	scope     string
+	vector    []float64 // Normalized to unit length
+	response  []byte
+	expiresAt time.Time // Zero if the entry does not expire
+}
+
+// =============================================================================
+// Semantic Cache Client Wrapper
+// =============================================================================
+
+// SemanticCacheClient wraps an LLM client and answers chat requests whose
+// last user message is similar to one answered before.
+//
+// Questions are embedded with the embedder and compared by cosine
+// similarity. Only answers for the same provider, model, system prompt and
+// request options (tools, response format, sampling parameters, documents and
+// extras) are considered. Only single-turn requests are cached: requests with
+// earlier user, assistant or tool messages bypass the cache, since their
+// answer depends on the conversation. So do requests whose last message is
+// not a plain text user message and requests for which embedding fails.
+// Completions and embeddings are not cached.
+type SemanticCacheClient struct {
+	client   LLM
+	embedder LLM
+	config   *SemanticCacheConfig
+
+	mu     sync.Mutex
+	order  *list.List                            // Front is the most recently used
+	scopes map[string]map[*list.Element]struct{} // Entries by scope
+	stats  SemanticCacheStats
+}
+
+// WithSemanticCache wraps an LLM client with a semantic cache. The embedder
+// must support FeatureEmbedding; a nil embedder uses client.
+func WithSemanticCache(client, embedder LLM, opts ...SemanticCacheOption) (*SemanticCacheClient, error) {
+	if embedder == nil {
+		embedder = client
+	}
+	if !embedder.HasFeature(FeatureEmbedding) {
+		return nil, NewAPIError(ErrorTypeInvalidRequest, embedder.ID(), "semantic cache requires an embedding provider")
+	}
+
+	config := DefaultSemanticCacheConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Capacity <= 0 {
+		config.Capacity = DefaultSemanticCacheConfig().Capacity
+	}
+
+	return &SemanticCacheClient{
+		client:   client,
+		embedder: embedder,
+		config:   config,
+		order:    list.New(),
+		scopes:   make(map[string]map[*list.Element]struct{}),
+	}, nil
+}
+
+// ID returns the provider identifier
+func (c *SemanticCacheClient) ID() string {
+	return c.client.ID()
+}
+
+// Name returns the provider name
+func (c *SemanticCacheClient) Name() string {
+	return c.client.Name()
+}
+
+// Version returns the client version
+func (c *SemanticCacheClient) Version() string {
+	return c.client.Version()
+}
+
+// BaseURL returns the API base URL
+func (c *SemanticCacheClient) BaseURL() string {
+	return c.client.BaseURL()
+}
+
+// Models returns available models
+func (c *SemanticCacheClient) Models() []Model {
+	return c.client.Models()
+}
+
+// GetModel returns a specific model
+func (c *SemanticCacheClient) GetModel(id string) (*Model, error) {
+	return c.client.GetModel(id)
+}
+
+// Chat performs a chat completion, answering similar questions from the cache
+func (c *SemanticCacheClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	scope, vector, ok := c.prepare(ctx, req)
+	if !ok {
+		return c.client.Chat(ctx, req)
+	}
+
+	if resp, ok := c.lookup(scope, vector, c.threshold(req.Model)); ok {
+		return resp, nil
+	}
+
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.store(scope, vector, resp)
+	return resp, nil
+}
+
+// ChatStream performs a streaming chat completion. Cached answers are
+// replayed as a stream; fresh answers are cached once the stream completes.
+func (c *SemanticCacheClient) ChatStream(ctx context.Context, req *ChatRequest) (*StreamReader, error) {
+	scope, vector, ok := c.prepare(ctx, req)
+	if !ok {
+		return c.client.ChatStream(ctx, req)
+	}
+
+	if resp, ok := c.lookup(scope, vector, c.threshold(req.Model)); ok {
+		return replayStream(resp), nil
+	}
+
+	stream, err := c.client.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return collectStream(ctx, stream, func(resp *ChatResponse) {
+		c.store(scope, vector, resp)
+	}), nil
+}
+
+// Complete performs a text completion. Completions are not cached.
+func (c *SemanticCacheClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	return c.client.Complete(ctx, req)
+}
+
+// Embed generates embeddings. Embeddings are not cached.
+func (c *SemanticCacheClient) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	return c.client.Embed(ctx, req)
+}
+
+// HasFeature checks if a feature is supported
+func (c *SemanticCacheClient) HasFeature(feature Feature) bool {
+	return c.client.HasFeature(feature)
+}
+
+// Features returns all supported features
+func (c *SemanticCacheClient) Features() []Feature {
+	return c.client.Features()
+}
+
+// SetOption sets a provider-specific option
+func (c *SemanticCacheClient) SetOption(key string, value interface{}) error {
+	return c.client.SetOption(key, value)
+}
+
+// GetOption gets a provider-specific option
+func (c *SemanticCacheClient) GetOption(key string) (interface{}, bool) {
+	return c.client.GetOption(key)
+}
+
+// Unwrap returns the underlying LLM client
+func (c *SemanticCacheClient) Unwrap() LLM {
+	return c.client
+}
+
+// Stats returns a snapshot of cache activity
+func (c *SemanticCacheClient) Stats() SemanticCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// Clear removes every cached answer. Stats are kept.
+func (c *SemanticCacheClient) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.scopes = make(map[string]map[*list.Element]struct{})
+}
+
+// prepare returns the scope and normalized question embedding for req, or
+// false if req cannot be looked up
+func (c *SemanticCacheClient) prepare(ctx context.Context, req *ChatRequest) (string, []float64, bool) {
+	question, system, ok := semanticQuestion(req.Messages)
+	if ok {
+		scope, err := semanticScope(c.client.ID(), req, system)
+		if err == nil {
+			resp, err := c.embedder.Embed(ctx, &EmbedRequest{Model: c.config.EmbedModel, Input: []string{question}})
+			if err == nil && len(resp.Embeddings) > 0 {
+				if vector, ok := vecmath.Normalize(resp.Embeddings[0].Vector); ok {
+					return scope, vector, true
+				}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.stats.Skipped++
+	c.mu.Unlock()
+	return "", nil, false
+}
+
+func (c *SemanticCacheClient) threshold(model string) float64 {
+	if threshold, ok := c.config.ModelThresholds[model]; ok {
+		return threshold
+	}
+	return c.config.Threshold
+}
+
+// lookup returns the answer to the most similar question in scope if its
+// similarity reaches threshold
+func (c *SemanticCacheClient) lookup(scope string, vector []float64, threshold float64) (*ChatResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var best *list.Element
+	bestScore := math.Inf(-1)
+
+	for elem := range c.scopes[scope] {
+		entry := elem.Value.(*semanticEntry)
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			c.remove(elem)
+			continue
+		}
+		// Vectors of different lengths never match. The dot product of
+		// normalized vectors is their cosine similarity.
+		if len(vector) != len(entry.vector) {
+			continue
+		}
+		if score := vecmath.Dot(vector, entry.vector); score > bestScore {
+			best, bestScore = elem, score
+		}
+	}
+
+	if best == nil || bestScore < threshold {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	var resp ChatResponse
+	if err := json.Unmarshal(best.Value.(*semanticEntry).response, &resp); err != nil {
+		c.remove(best)
+		c.stats.Misses++
+		return nil, false
+	}
+	c.order.MoveToFront(best)
+	c.stats.Hits++
+	resp.CacheHit = true
+	return &resp, true
+}
+
+// store caches resp, evicting the least recently used answers if full
+func (c *SemanticCacheClient) store(scope string, vector []float64, resp *ChatResponse) {
+	stored := *resp
+	stored.Raw = nil
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return
+	}
+
+	entry := &semanticEntry{scope: scope, vector: vector, response: data}
+	if c.config.TTL > 0 {
+		entry.expiresAt = time.Now().Add(c.config.TTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem := c.order.PushFront(entry)
+	if c.scopes[scope] == nil {
+		c.scopes[scope] = make(map[*list.Element]struct{})
+	}
+	c.scopes[scope][elem] = struct{}{}
+
+	for c.order.Len() > c.config.Capacity {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *SemanticCacheClient) remove(elem *list.Element) {
+	scope := elem.Value.(*semanticEntry).scope
+	c.order.Remove(elem)
+	delete(c.scopes[scope], elem)
+	if len(c.scopes[scope]) == 0 {
+		delete(c.scopes, scope)
+	}
+}
+
+// semanticQuestion returns the text of the last message and the system
+// prompt, or false if the last message is not a plain text user message or
+// is not the only non-system message
+func semanticQuestion(messages []Message) (question, system string, ok bool) {
+	if len(messages) == 0 || messages[len(messages)-1].Role != RoleUser {
+		return "", "", false
+	}
+	question, ok = plainText(messages[len(messages)-1].Content)
+	if !ok || strings.TrimSpace(question) == "" {
+		return "", "", false
+	}
+
+	var prompts []string
+	for _, msg := range messages[:len(messages)-1] {
+		if msg.Role != RoleSystem {
+			return "", "", false
+		}
+		text, ok := plainText(msg.Content)
+		if !ok {
+			return "", "", false
+		}
+		prompts = append(prompts, text)
+	}
+	return question, strings.Join(prompts, "\n"), true
+}
+
+// plainText returns message content as text, or false if it has non-text parts
+func plainText(content interface{}) (string, bool) {
+	switch v := content.(type) {
+	case string:
+		return v, true
+	case []ContentPart:
+		var sb strings.Builder
+		for _, part := range v {
+			if part.Type != "text" {
+				return "", false
+			}
+			sb.WriteString(part.Text)
+		}
+		return sb.String(), true
+	default:
+		return "", false
+	}
+}
+
+// semanticScope identifies the answers a question may be matched against:
+// those for the same provider, model, system prompt and request options
+func semanticScope(provider string, req *ChatRequest, system string) (string, error) {
+	data, err := json.Marshal(struct {
+		Provider       string                 `json:"provider"`
+		Model          string                 `json:"model"`
+		System         string                 `json:"system"`
+		MaxTokens      int                    `json:"max_tokens,omitempty"`
+		Temperature    *float64               `json:"temperature,omitempty"`
+		TopP           *float64               `json:"top_p,omitempty"`
+		Stop           []string               `json:"stop,omitempty"`
+		Tools          []Tool                 `json:"tools,omitempty"`
+		ToolChoice     interface{}            `json:"tool_choice,omitempty"`
+		ResponseFormat *ResponseFormat        `json:"response_format,omitempty"`
+		Documents      []Document             `json:"documents,omitempty"`
+		Extra          map[string]interface{} `json:"extra,omitempty"`
+	}{
+		provider, req.Model, system, req.MaxTokens, req.Temperature, req.TopP, req.Stop,
+		req.Tools, req.ToolChoice, req.ResponseFormat, req.Documents, req.Extra,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Ensure SemanticCacheClient implements LLM interface
+var _ LLM = (*SemanticCacheClient)(nil)
@@ -0,0 +1,228 @@
+package gollmx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// semanticLLM answers every chat with the same response and embeds texts
+// with scripted vectors
+type semanticLLM struct {
+	*cacheLLM
+	vectors map[string][]float64
+}
+
+func (m *semanticLLM) HasFeature(feature Feature) bool {
+	return feature == FeatureEmbedding
+}
+
+func (m *semanticLLM) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	var embeddings []Embedding
+	for i, text := range req.Input {
+		embeddings = append(embeddings, Embedding{Index: i, Vector: m.vectors[text]})
+	}
+	return &EmbedResponse{Embeddings: embeddings}, nil
+}
+
+func newSemanticLLM() *semanticLLM {
+	return &semanticLLM{
+		cacheLLM: newCacheLLM(),
+		vectors: map[string][]float64{
+			"How do I reset my password?":     {1, 0, 0},
+			"How can I reset my password?":    {0.99, 0.1, 0},
+			"What are your opening hours?":    {0, 1, 0},
+			"Where can I change my password?": {0.8, 0.6, 0},
+		},
+	}
+}
+
+func question(model, system, content string) *ChatRequest {
+	req := &ChatRequest{Model: model}
+	if system != "" {
+		req.Messages = append(req.Messages, Message{Role: RoleSystem, Content: system})
+	}
+	req.Messages = append(req.Messages, Message{Role: RoleUser, Content: content})
+	return req
+}
+
+func TestSemanticCacheHit(t *testing.T) {
+	inner := newSemanticLLM()
+	client, err := WithSemanticCache(inner, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	client.Chat(ctx, question("model", "", "How do I reset my password?"))
+	resp, err := client.Chat(ctx, question("model", "", "How can I reset my password?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.CacheHit || resp.GetContent() != "Hello" {
+		t.Errorf("expected a paraphrase to hit the cache, got %+v", resp)
+	}
+
+	// Below the default threshold
+	client.Chat(ctx, question("model", "", "Where can I change my password?"))
+	client.Chat(ctx, question("model", "", "What are your opening hours?"))
+
+	if inner.count() != 3 {
+		t.Errorf("expected 3 upstream calls, got %d", inner.count())
+	}
+	stats := client.Stats()
+	if stats.Hits != 1 || stats.Misses != 3 || stats.Entries != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheScope(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil)
+	ctx := context.Background()
+
+	client.Chat(ctx, question("model", "Be terse.", "How do I reset my password?"))
+	client.Chat(ctx, question("other", "Be terse.", "How do I reset my password?"))
+	client.Chat(ctx, question("model", "Be verbose.", "How do I reset my password?"))
+	if inner.count() != 3 {
+		t.Errorf("expected answers to be scoped by model and system prompt, got %d calls", inner.count())
+	}
+
+	resp, _ := client.Chat(ctx, question("model", "Be terse.", "How do I reset my password?"))
+	if !resp.CacheHit {
+		t.Error("expected a hit in the same scope")
+	}
+}
+
+func TestSemanticCacheMultiTurn(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil)
+	ctx := context.Background()
+
+	client.Chat(ctx, question("model", "", "How do I reset my password?"))
+
+	// A follow-up depends on the conversation, even if it embeds alike
+	req := question("model", "", "How do I reset my password?")
+	req.Messages = append([]Message{
+		{Role: RoleUser, Content: "I use the mobile app."},
+		{Role: RoleAssistant, Content: "Noted."},
+	}, req.Messages...)
+
+	resp, _ := client.Chat(ctx, req)
+	if resp.CacheHit {
+		t.Error("expected a multi-turn request to bypass the cache")
+	}
+	if stats := client.Stats(); stats.Skipped != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheRequestOptions(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil)
+	ctx := context.Background()
+
+	client.Chat(ctx, question("model", "", "How do I reset my password?"))
+
+	withTools := question("model", "", "How do I reset my password?")
+	withTools.Tools = []Tool{{Type: "function", Function: Function{Name: "reset_password"}}}
+	withFormat := question("model", "", "How do I reset my password?")
+	withFormat.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	temperature := 1.5
+	withTemperature := question("model", "", "How do I reset my password?")
+	withTemperature.Temperature = &temperature
+
+	for _, req := range []*ChatRequest{withTools, withFormat, withTemperature} {
+		if resp, _ := client.Chat(ctx, req); resp.CacheHit {
+			t.Errorf("expected a miss for request options %+v", req)
+		}
+	}
+
+	// The same options share answers
+	resp, _ := client.Chat(ctx, withTools)
+	if !resp.CacheHit {
+		t.Error("expected a hit for the same tools")
+	}
+}
+
+func TestSemanticCacheModelThreshold(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil, WithSemanticModelThreshold("lenient", 0.75))
+	ctx := context.Background()
+
+	for _, model := range []string{"model", "lenient"} {
+		client.Chat(ctx, question(model, "", "How do I reset my password?"))
+		client.Chat(ctx, question(model, "", "Where can I change my password?"))
+	}
+	if stats := client.Stats(); stats.Hits != 1 {
+		t.Errorf("expected only the lenient model to hit, got %+v", stats)
+	}
+}
+
+func TestSemanticCacheEviction(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil, WithSemanticCapacity(1), WithSemanticTTL(time.Hour))
+	ctx := context.Background()
+
+	client.Chat(ctx, question("model", "", "How do I reset my password?"))
+	client.Chat(ctx, question("model", "", "What are your opening hours?"))
+	resp, _ := client.Chat(ctx, question("model", "", "How do I reset my password?"))
+	if resp.CacheHit {
+		t.Error("expected the least recently used answer to be evicted")
+	}
+	if stats := client.Stats(); stats.Evictions != 2 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheSkipped(t *testing.T) {
+	inner := newSemanticLLM()
+	client, _ := WithSemanticCache(inner, nil)
+	ctx := context.Background()
+
+	// Last message is not from the user
+	req := question("model", "", "How do I reset my password?")
+	req.Messages = append(req.Messages, Message{Role: RoleAssistant, Content: "Hi"})
+	client.Chat(ctx, req)
+
+	// No embedding for the question
+	client.Chat(ctx, question("model", "", "unknown"))
+
+	if stats := client.Stats(); stats.Skipped != 2 || stats.Entries != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheChatStream(t *testing.T) {
+	inner := newSemanticLLM()
+	inner.chunks = []StreamChunk{
+		{Content: "Use the "},
+		{Content: "reset link.", FinishReason: "stop"},
+	}
+	client, _ := WithSemanticCache(inner, nil)
+	ctx := context.Background()
+
+	stream, err := client.ChatStream(ctx, question("model", "", "How do I reset my password?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream.Collect()
+
+	stream, err = client.ChatStream(ctx, question("model", "", "How can I reset my password?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.CacheHit || resp.GetContent() != "Use the reset link." {
+		t.Errorf("expected the streamed answer to be replayed, got %+v", resp)
+	}
+}
+
+func TestWithSemanticCacheRequiresEmbedding(t *testing.T) {
+	if _, err := WithSemanticCache(newCacheLLM(), nil); err == nil {
+		t.Error("expected an error for an embedder without embedding support")
+	}
+}
@@ -100,10 +100,27 @@ type ChatRequest struct {
 	// Response format
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 
+	// Grounding documents the model may cite (not all providers support)
+	Documents []Document `json:"documents,omitempty"`
+
 	// Provider-specific options (passed through)
 	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
+// Document is a grounding document supplied with a request or cited in a response
+type Document struct {
+	ID   string                 `json:"id,omitempty"`
+	Data map[string]interface{} `json:"data"` // Free-form fields, e.g. "title", "text"
+}
+
+// Citation links a span of generated text to the sources that support it
+type Citation struct {
+	Start   int      `json:"start"`             // Start offset in the generated text
+	End     int      `json:"end"`               // End offset in the generated text
+	Text    string   `json:"text"`              // The cited span
+	Sources []string `json:"sources,omitempty"` // IDs of supporting documents or tool outputs
+}
+
 // ResponseFormat specifies the format of the response
 type ResponseFormat struct {
 	Type       string          `json:"type"` // "text", "json_object", "json_schema"
@@ -154,6 +171,13 @@ type ChatResponse struct {
 	Choices   []Choice `json:"choices"`
 	Usage     Usage    `json:"usage"`
 
+	// Grounding information (not all providers support)
+	Citations []Citation `json:"citations,omitempty"`
+	Documents []Document `json:"documents,omitempty"` // Documents referenced by Citations
+
+	// Set when the response was served from a cache
+	CacheHit bool `json:"cache_hit,omitempty"`
+
 	// Provider-specific data
 	Raw interface{} `json:"raw,omitempty"`
 }
@@ -170,6 +194,11 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// Server-side timings, reported by local runtimes such as Ollama
+	LoadDuration       time.Duration `json:"load_duration,omitempty"`        // Time spent loading the model
+	PromptEvalDuration time.Duration `json:"prompt_eval_duration,omitempty"` // Time spent processing the prompt
+	EvalDuration       time.Duration `json:"eval_duration,omitempty"`        // Time spent generating the completion
 }
 
 // GetContent returns the text content of the first choice
@@ -229,11 +258,26 @@ func (r *StreamReader) Err() error {
 	return r.err
 }
 
+// Drain discards the rest of the stream until it ends, so the provider
+// goroutine sending to it can exit and release its response body. Middleware
+// that stops forwarding a stream, e.g. when its context is cancelled, must
+// drain it.
+func (r *StreamReader) Drain() {
+	if r.closed {
+		return
+	}
+	for range r.ch {
+	}
+	r.closed = true
+}
+
 // Collect reads all chunks and returns the complete response
 func (r *StreamReader) Collect() (*ChatResponse, error) {
 	var response ChatResponse
 	var content string
 	var toolCalls []ToolCall
+	var citations []Citation
+	var documents []Document
 
 	for {
 		chunk, ok := r.Next()
@@ -246,6 +290,8 @@ func (r *StreamReader) Collect() (*ChatResponse, error) {
 		if len(chunk.ToolCalls) > 0 {
 			toolCalls = append(toolCalls, chunk.ToolCalls...)
 		}
+		citations = append(citations, chunk.Citations...)
+		documents = append(documents, chunk.Documents...)
 		response.ID = chunk.ID
 		response.Model = chunk.Model
 		response.Provider = chunk.Provider
@@ -256,13 +302,22 @@ func (r *StreamReader) Collect() (*ChatResponse, error) {
 				FinishReason: chunk.FinishReason,
 			}}
 		}
-		response.Usage = chunk.Usage
+		// Providers report usage on different chunks; keep the latest
+		if chunk.Usage != (Usage{}) {
+			response.Usage = chunk.Usage
+		}
+		if chunk.CacheHit {
+			response.CacheHit = true
+		}
 	}
 
 	if r.err != nil {
 		return nil, r.err
 	}
 
+	response.Citations = citations
+	response.Documents = documents
+
 	if len(response.Choices) == 0 {
 		response.Choices = []Choice{{
 			Index:   0,
@@ -280,8 +335,11 @@ type StreamChunk struct {
 	Model        string     `json:"model"`
 	Content      string     `json:"content"`       // Delta content
 	ToolCalls    []ToolCall `json:"tool_calls"`    // Delta tool calls
+	Citations    []Citation `json:"citations,omitempty"`
+	Documents    []Document `json:"documents,omitempty"` // Documents first cited in this chunk
 	FinishReason string     `json:"finish_reason"`
 	Usage        Usage      `json:"usage"`
+	CacheHit     bool       `json:"cache_hit,omitempty"` // Replayed from a cache
 	Error        error      `json:"error,omitempty"`
 }
 
@@ -332,11 +390,41 @@ func (r *CompletionResponse) GetText() string {
 // Embedding Types
 // =============================================================================
 
+// EmbedInputType describes how embeddings will be used, letting providers
+// optimize for it
+type EmbedInputType string
+
+const (
+	EmbedInputSearchDocument EmbedInputType = "search_document" // Documents to be searched
+	EmbedInputSearchQuery    EmbedInputType = "search_query"    // Queries against documents
+	EmbedInputClassification EmbedInputType = "classification"
+	EmbedInputClustering     EmbedInputType = "clustering"
+	EmbedInputSimilarity     EmbedInputType = "similarity" // Semantic similarity between texts
+)
+
+// EmbedEncoding is the numeric format of returned embeddings
+type EmbedEncoding string
+
+const (
+	EmbedEncodingFloat   EmbedEncoding = "float"   // Embedding.Vector (default)
+	EmbedEncodingBase64  EmbedEncoding = "base64"  // Sent as base64 and decoded into Embedding.Vector
+	EmbedEncodingInt8    EmbedEncoding = "int8"    // Embedding.Int8
+	EmbedEncodingUint8   EmbedEncoding = "uint8"   // Embedding.Uint8
+	EmbedEncodingBinary  EmbedEncoding = "binary"  // Embedding.Int8, packed 8 dimensions per value
+	EmbedEncodingUbinary EmbedEncoding = "ubinary" // Embedding.Uint8, packed 8 dimensions per value
+)
+
 // EmbedRequest represents an embedding request
 type EmbedRequest struct {
 	Model string   `json:"model"`
 	Input []string `json:"input"` // Text(s) to embed
 
+	// Optional; providers that do not support a setting ignore InputType and
+	// reject Dimensions or EncodingFormat
+	InputType      EmbedInputType `json:"input_type,omitempty"`
+	Dimensions     int            `json:"dimensions,omitempty"` // Truncate embeddings to this size
+	EncodingFormat EmbedEncoding  `json:"encoding_format,omitempty"`
+
 	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
@@ -346,13 +434,68 @@ type EmbedResponse struct {
 	Model      string       `json:"model"`
 	Embeddings []Embedding  `json:"embeddings"`
 	Usage      Usage        `json:"usage"`
+	CacheHit   bool         `json:"cache_hit,omitempty"` // Served from a cache
 	Raw        interface{}  `json:"raw,omitempty"`
 }
 
-// Embedding represents a single embedding vector
+// Embedding represents a single embedding vector. Quantized encodings are
+// returned in Int8 or Uint8 instead of Vector.
 type Embedding struct {
 	Index  int       `json:"index"`
 	Vector []float64 `json:"vector"`
+	Int8   []int8    `json:"int8,omitempty"`
+	Uint8  []uint8   `json:"uint8,omitempty"`
+}
+
+// NewEmbedding stores values in the Embedding field matching encoding.
+// Providers return quantized embeddings as numbers in the integer range.
+func NewEmbedding(index int, encoding EmbedEncoding, values []float64) Embedding {
+	emb := Embedding{Index: index}
+	switch encoding {
+	case EmbedEncodingInt8, EmbedEncodingBinary:
+		emb.Int8 = make([]int8, len(values))
+		for i, v := range values {
+			emb.Int8[i] = int8(v)
+		}
+	case EmbedEncodingUint8, EmbedEncodingUbinary:
+		emb.Uint8 = make([]uint8, len(values))
+		for i, v := range values {
+			emb.Uint8[i] = uint8(v)
+		}
+	default:
+		emb.Vector = values
+	}
+	return emb
+}
+
+// =============================================================================
+// Rerank Types
+// =============================================================================
+
+// RerankRequest represents a request to order documents by relevance to a query
+type RerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"` // Number of results to return (0 = all)
+
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+// RerankResponse represents a rerank response, ordered by descending score
+type RerankResponse struct {
+	Provider string         `json:"provider"`
+	Model    string         `json:"model"`
+	Results  []RerankResult `json:"results"`
+	Usage    Usage          `json:"usage"`
+	Raw      interface{}    `json:"raw,omitempty"`
+}
+
+// RerankResult represents a single reranked document
+type RerankResult struct {
+	Index    int     `json:"index"`    // Position in RerankRequest.Documents
+	Document string  `json:"document"` // The document text
+	Score    float64 `json:"score"`    // Relevance score (higher is more relevant)
 }
 
 // =============================================================================
@@ -372,6 +515,7 @@ const (
 	ErrorTypeContentFilter ErrorType = "content_filter"
 	ErrorTypeModelNotFound ErrorType = "model_not_found"
 	ErrorTypeQuota         ErrorType = "quota_exceeded"
+	ErrorTypeUnsupportedFeature ErrorType = "unsupported_feature" // Model lacks a feature the request needs
 	ErrorTypeUnknown       ErrorType = "unknown"
 )
 
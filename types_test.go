@@ -153,6 +153,20 @@ func TestImageURLContent(t *testing.T) {
 	}
 }
 
+func TestNewEmbedding(t *testing.T) {
+	values := []float64{-3, 0, 127}
+
+	if emb := NewEmbedding(2, "", values); emb.Index != 2 || len(emb.Vector) != 3 || emb.Int8 != nil {
+		t.Errorf("expected a float vector, got %+v", emb)
+	}
+	if emb := NewEmbedding(0, EmbedEncodingInt8, values); emb.Vector != nil || emb.Int8[0] != -3 || emb.Int8[2] != 127 {
+		t.Errorf("expected int8 values, got %+v", emb)
+	}
+	if emb := NewEmbedding(0, EmbedEncodingUbinary, []float64{255}); emb.Uint8[0] != 255 {
+		t.Errorf("expected uint8 values, got %+v", emb)
+	}
+}
+
 func TestRoleConstants(t *testing.T) {
 	if RoleSystem != "system" {
 		t.Errorf("expected RoleSystem 'system', got '%s'", RoleSystem)
@@ -198,6 +212,7 @@ func TestErrorTypes(t *testing.T) {
 		ErrorTypeContentFilter,
 		ErrorTypeModelNotFound,
 		ErrorTypeQuota,
+		ErrorTypeUnsupportedFeature,
 		ErrorTypeUnknown,
 	}
 
@@ -0,0 +1,410 @@
+package vector
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// HNSW defaults
+const (
+	DefaultM              = 16
+	DefaultEfConstruction = 200
+	DefaultEfSearch       = 64
+)
+
+// HNSWOption is a function that configures an HNSW index
+type HNSWOption func(*HNSW)
+
+// WithM sets the number of neighbors per node on upper layers; layer 0 keeps
+// twice as many. Higher values improve recall at the cost of memory.
+func WithM(m int) HNSWOption {
+	return func(h *HNSW) {
+		h.m = m
+	}
+}
+
+// WithEfConstruction sets the candidate list size used while inserting.
+// Higher values build a better graph more slowly.
+func WithEfConstruction(ef int) HNSWOption {
+	return func(h *HNSW) {
+		h.efConstruction = ef
+	}
+}
+
+// WithEfSearch sets the candidate list size used while searching. Higher
+// values improve recall at the cost of speed. Searches use at least k.
+func WithEfSearch(ef int) HNSWOption {
+	return func(h *HNSW) {
+		h.efSearch = ef
+	}
+}
+
+// WithSeed seeds the random level assignment, making builds reproducible
+func WithSeed(seed int64) HNSWOption {
+	return func(h *HNSW) {
+		h.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// HNSW is an approximate index based on Hierarchical Navigable Small World
+// graphs. Deleted items are excluded from results but stay in the graph to
+// keep it connected.
+type HNSW struct {
+	mu             sync.RWMutex
+	metric         Metric
+	m              int
+	efConstruction int
+	efSearch       int
+	rng            *rand.Rand
+
+	dims     int
+	nodes    []*hnswNode
+	byID     map[string]int // Live nodes by item ID
+	entry    int            // Entry point, or -1 if empty
+	maxLevel int
+}
+
+type hnswNode struct {
+	item      Item    // Vector prepared for the metric
+	level     int     // Highest layer the node is on
+	neighbors [][]int // Neighbor nodes per layer
+	deleted   bool
+}
+
+// NewHNSW creates an empty HNSW index
+func NewHNSW(metric Metric, opts ...HNSWOption) *HNSW {
+	h := &HNSW{
+		metric:         metric,
+		m:              DefaultM,
+		efConstruction: DefaultEfConstruction,
+		efSearch:       DefaultEfSearch,
+		byID:           make(map[string]int),
+		entry:          -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.m < 2 {
+		h.m = 2
+	}
+	if h.rng == nil {
+		h.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return h
+}
+
+// Add inserts items, replacing items with the same ID
+func (h *HNSW) Add(items ...Item) error {
+	if err := h.metric.validate(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dims := h.dims
+	for _, item := range items {
+		if dims == 0 {
+			dims = len(item.Vector)
+		}
+		if len(item.Vector) == 0 || len(item.Vector) != dims {
+			return fmt.Errorf("%w: item %q has %d dimensions, want %d", ErrDimensionMismatch, item.ID, len(item.Vector), dims)
+		}
+	}
+	h.dims = dims
+
+	for _, item := range items {
+		item.Vector = h.metric.prepare(item.Vector)
+		h.insert(item)
+	}
+	return nil
+}
+
+func (h *HNSW) insert(item Item) {
+	if old, ok := h.byID[item.ID]; ok {
+		h.nodes[old].deleted = true
+	}
+
+	level := int(-math.Log(1-h.rng.Float64()) / math.Log(float64(h.m)))
+	id := len(h.nodes)
+	node := &hnswNode{item: item, level: level, neighbors: make([][]int, level+1)}
+	h.nodes = append(h.nodes, node)
+	h.byID[item.ID] = id
+
+	if h.entry < 0 {
+		h.entry, h.maxLevel = id, level
+		return
+	}
+
+	ep := h.entry
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.searchLayer(item.Vector, []int{ep}, 1, l, nil)[0].id
+	}
+
+	eps := []int{ep}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		found := h.searchLayer(item.Vector, eps, h.efConstruction, l, nil)
+
+		neighbors := make([]int, 0, h.m)
+		for _, c := range found {
+			if len(neighbors) == h.m {
+				break
+			}
+			neighbors = append(neighbors, c.id)
+		}
+		node.neighbors[l] = neighbors
+
+		for _, n := range neighbors {
+			h.connect(n, id, l)
+		}
+
+		eps = eps[:0]
+		for _, c := range found {
+			eps = append(eps, c.id)
+		}
+	}
+
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = id, level
+	}
+}
+
+// connect adds to as a neighbor of from on layer l, keeping only the closest
+// neighbors if from has too many
+func (h *HNSW) connect(from, to, l int) {
+	node := h.nodes[from]
+	node.neighbors[l] = append(node.neighbors[l], to)
+
+	maxConn := h.m
+	if l == 0 {
+		maxConn = 2 * h.m
+	}
+	if len(node.neighbors[l]) <= maxConn {
+		return
+	}
+
+	kept := &candidates{}
+	for _, n := range node.neighbors[l] {
+		kept.items = append(kept.items, candidate{id: n, distance: h.metric.distance(node.item.Vector, h.nodes[n].item.Vector)})
+	}
+	sorted := kept.sorted()
+	node.neighbors[l] = node.neighbors[l][:0]
+	for _, c := range sorted[:maxConn] {
+		node.neighbors[l] = append(node.neighbors[l], c.id)
+	}
+}
+
+// searchLayer returns up to ef nodes on layer l closest to query, nearest
+// first. Only nodes accepted by accept are returned, but every node is used
+// to navigate the graph. A nil accept accepts every node.
+func (h *HNSW) searchLayer(query []float32, eps []int, ef, l int, accept func(*hnswNode) bool) []candidate {
+	visited := make(map[int]bool, ef*4)
+	toVisit := &candidates{}
+	found := &candidates{max: true}
+
+	for _, ep := range eps {
+		if visited[ep] {
+			continue
+		}
+		visited[ep] = true
+		c := candidate{id: ep, distance: h.metric.distance(query, h.nodes[ep].item.Vector)}
+		heap.Push(toVisit, c)
+		if accept == nil || accept(h.nodes[ep]) {
+			heap.Push(found, c)
+		}
+	}
+	for found.Len() > ef {
+		heap.Pop(found)
+	}
+
+	for toVisit.Len() > 0 {
+		current := heap.Pop(toVisit).(candidate)
+		if found.Len() >= ef && current.distance > found.items[0].distance {
+			break
+		}
+
+		for _, n := range h.nodes[current.id].neighbors[l] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+
+			d := h.metric.distance(query, h.nodes[n].item.Vector)
+			if found.Len() >= ef && d >= found.items[0].distance {
+				continue
+			}
+			heap.Push(toVisit, candidate{id: n, distance: d})
+			if accept == nil || accept(h.nodes[n]) {
+				heap.Push(found, candidate{id: n, distance: d})
+				if found.Len() > ef {
+					heap.Pop(found)
+				}
+			}
+		}
+	}
+
+	return found.sorted()
+}
+
+// Delete removes items by ID
+func (h *HNSW) Delete(ids ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, id := range ids {
+		if pos, ok := h.byID[id]; ok {
+			h.nodes[pos].deleted = true
+			delete(h.byID, id)
+		}
+	}
+}
+
+// Search returns up to k items closest to query that pass filter. Results
+// are approximate; raise WithEfSearch for better recall.
+func (h *HNSW) Search(query []float32, k int, filter Filter) ([]Result, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if k <= 0 || h.entry < 0 {
+		return nil, nil
+	}
+	if len(query) != h.dims {
+		return nil, fmt.Errorf("%w: query has %d dimensions, want %d", ErrDimensionMismatch, len(query), h.dims)
+	}
+	query = h.metric.prepare(query)
+
+	ep := h.entry
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.searchLayer(query, []int{ep}, 1, l, nil)[0].id
+	}
+
+	accept := func(node *hnswNode) bool {
+		return !node.deleted && (filter == nil || filter(node.item.Metadata))
+	}
+	found := h.searchLayer(query, []int{ep}, max(h.efSearch, k), 0, accept)
+	if len(found) > k {
+		found = found[:k]
+	}
+
+	results := make([]Result, len(found))
+	for i, c := range found {
+		item := h.nodes[c.id].item
+		results[i] = Result{ID: item.ID, Score: h.metric.score(c.distance), Metadata: item.Metadata}
+	}
+	return results, nil
+}
+
+// Len returns the number of items, excluding deleted ones
+func (h *HNSW) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.byID)
+}
+
+// hnswFile is the serialized form of an HNSW index, including its graph
+type hnswFile struct {
+	Type           string         `json:"type"`
+	Metric         Metric         `json:"metric"`
+	M              int            `json:"m"`
+	EfConstruction int            `json:"ef_construction"`
+	EfSearch       int            `json:"ef_search"`
+	Entry          int            `json:"entry"`
+	MaxLevel       int            `json:"max_level"`
+	Nodes          []hnswNodeFile `json:"nodes"`
+}
+
+type hnswNodeFile struct {
+	Item      Item    `json:"item"`
+	Level     int     `json:"level"`
+	Neighbors [][]int `json:"neighbors"`
+	Deleted   bool    `json:"deleted,omitempty"`
+}
+
+// Save writes the index and its graph to w as JSON
+func (h *HNSW) Save(w io.Writer) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	file := hnswFile{
+		Type:           "hnsw",
+		Metric:         h.metric,
+		M:              h.m,
+		EfConstruction: h.efConstruction,
+		EfSearch:       h.efSearch,
+		Entry:          h.entry,
+		MaxLevel:       h.maxLevel,
+		Nodes:          make([]hnswNodeFile, len(h.nodes)),
+	}
+	for i, node := range h.nodes {
+		file.Nodes[i] = hnswNodeFile{Item: node.item, Level: node.level, Neighbors: node.neighbors, Deleted: node.deleted}
+	}
+	return json.NewEncoder(w).Encode(file)
+}
+
+// LoadHNSW reads an index written by HNSW.Save without rebuilding the graph.
+// Options override the saved search settings. Numeric metadata values are
+// restored as float64.
+func LoadHNSW(r io.Reader, opts ...HNSWOption) (*HNSW, error) {
+	var file hnswFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("vector: failed to decode index: %w", err)
+	}
+	if file.Type != "hnsw" {
+		return nil, fmt.Errorf("vector: expected an hnsw index, got %q", file.Type)
+	}
+	if err := file.Metric.validate(); err != nil {
+		return nil, err
+	}
+
+	saved := []HNSWOption{WithM(file.M), WithEfConstruction(file.EfConstruction), WithEfSearch(file.EfSearch)}
+	h := NewHNSW(file.Metric, append(saved, opts...)...)
+	h.entry, h.maxLevel = file.Entry, file.MaxLevel
+
+	for i, n := range file.Nodes {
+		if len(n.Neighbors) != n.Level+1 {
+			return nil, fmt.Errorf("vector: node %d has %d layers, want %d", i, len(n.Neighbors), n.Level+1)
+		}
+		for _, layer := range n.Neighbors {
+			for _, neighbor := range layer {
+				if neighbor < 0 || neighbor >= len(file.Nodes) {
+					return nil, fmt.Errorf("vector: node %d has invalid neighbor %d", i, neighbor)
+				}
+			}
+		}
+		if h.dims == 0 {
+			h.dims = len(n.Item.Vector)
+		}
+		if len(n.Item.Vector) != h.dims {
+			return nil, fmt.Errorf("%w: node %d", ErrDimensionMismatch, i)
+		}
+
+		h.nodes = append(h.nodes, &hnswNode{item: n.Item, level: n.Level, neighbors: n.Neighbors, deleted: n.Deleted})
+		if !n.Deleted {
+			h.byID[n.Item.ID] = i
+		}
+	}
+	if h.entry >= len(h.nodes) || (h.entry < 0) != (len(h.nodes) == 0) {
+		return nil, fmt.Errorf("vector: invalid entry point %d", h.entry)
+	}
+	return h, nil
+}
+
+// LoadHNSWFile reads an HNSW index saved with SaveFile
+func LoadHNSWFile(path string, opts ...HNSWOption) (*HNSW, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadHNSW(file, opts...)
+}
+
+// Ensure HNSW implements Index
+var _ Index = (*HNSW)(nil)
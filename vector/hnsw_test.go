@@ -0,0 +1,136 @@
+package vector
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func randomItems(rng *rand.Rand, n, dims int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		v := make([]float32, dims)
+		for j := range v {
+			v[j] = rng.Float32()*2 - 1
+		}
+		group := "even"
+		if i%2 == 1 {
+			group = "odd"
+		}
+		items[i] = Item{ID: fmt.Sprintf("item-%d", i), Vector: v, Metadata: map[string]interface{}{"group": group}}
+	}
+	return items
+}
+
+// recall returns the fraction of exact results found by the approximate ones
+func recall(exact, approx []Result) float64 {
+	found := make(map[string]bool, len(approx))
+	for _, r := range approx {
+		found[r.ID] = true
+	}
+	hits := 0
+	for _, r := range exact {
+		if found[r.ID] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(exact))
+}
+
+func TestHNSWRecall(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	items := randomItems(rng, 1000, 16)
+
+	flat := NewFlat(MetricCosine)
+	flat.Add(items...)
+	index := NewHNSW(MetricCosine, WithSeed(1))
+	if err := index.Add(items...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if index.Len() != 1000 {
+		t.Fatalf("expected 1000 items, got %d", index.Len())
+	}
+
+	for _, filter := range []Filter{nil, Match(map[string]interface{}{"group": "odd"})} {
+		var total float64
+		queries := randomItems(rng, 50, 16)
+		for _, q := range queries {
+			exact, _ := flat.Search(q.Vector, 10, filter)
+			approx, err := index.Search(q.Vector, 10, filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, r := range approx {
+				if filter != nil && r.Metadata["group"] != "odd" {
+					t.Fatalf("expected only filtered results, got %+v", r)
+				}
+			}
+			total += recall(exact, approx)
+		}
+		if avg := total / float64(len(queries)); avg < 0.9 {
+			t.Errorf("expected recall of at least 0.9, got %.2f", avg)
+		}
+	}
+}
+
+func TestHNSWDeleteAndReplace(t *testing.T) {
+	index := NewHNSW(MetricCosine, WithSeed(1))
+	index.Add(testItems()...)
+
+	index.Delete("east")
+	if index.Len() != 3 {
+		t.Errorf("expected 3 items after delete, got %d", index.Len())
+	}
+	results, _ := index.Search([]float32{1, 0}, 4, nil)
+	for _, r := range results {
+		if r.ID == "east" {
+			t.Error("expected deleted item to be excluded from results")
+		}
+	}
+
+	index.Add(Item{ID: "west", Vector: []float32{1, 0.01}})
+	if index.Len() != 3 {
+		t.Errorf("expected replacing an item to keep 3 items, got %d", index.Len())
+	}
+	results, _ = index.Search([]float32{1, 0}, 4, nil)
+	if len(results) != 3 || results[0].ID != "west" {
+		t.Errorf("expected the replaced vector first among 3 results, got %v", resultIDs(results))
+	}
+}
+
+func TestHNSWSaveLoad(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	index := NewHNSW(MetricL2, WithSeed(2), WithM(8))
+	index.Add(randomItems(rng, 200, 8)...)
+	index.Delete("item-0")
+
+	var buf bytes.Buffer
+	if err := index.Save(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := LoadHNSW(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Len() != index.Len() {
+		t.Fatalf("expected %d items, got %d", index.Len(), loaded.Len())
+	}
+
+	query := randomItems(rng, 1, 8)[0].Vector
+	want, _ := index.Search(query, 5, nil)
+	got, _ := loaded.Search(query, 5, nil)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("expected identical results after loading, got %v and %v", resultIDs(want), resultIDs(got))
+			break
+		}
+	}
+
+	if _, err := LoadHNSW(bytes.NewBufferString(`{"type":"flat"}`)); err == nil {
+		t.Error("expected an error loading a flat index as hnsw")
+	}
+}
@@ -0,0 +1,340 @@
+package vector
+
+import (
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+// ErrDimensionMismatch is returned when a vector's length differs from the
+// vectors already in an index
+var ErrDimensionMismatch = errors.New("vector: dimension mismatch")
+
+// Item is a vector stored in an index
+type Item struct {
+	ID       string                 `json:"id"`
+	Vector   []float32              `json:"vector"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Result is a search match. Score is the similarity for MetricCosine and
+// MetricDot, and the Euclidean distance for MetricL2. Results are ordered
+// closest first.
+type Result struct {
+	ID       string                 `json:"id"`
+	Score    float64                `json:"score"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Filter reports whether an item with the given metadata may be returned
+type Filter func(metadata map[string]interface{}) bool
+
+// Match returns a Filter that accepts items whose metadata has every given
+// key and value. Slices and maps are compared element by element.
+//
+// Values must have the same type to match. Metadata of an index restored
+// with LoadFlat or LoadHNSW has the types JSON decodes to: numbers are
+// float64, slices are []interface{} and maps are map[string]interface{}, so
+// filter with float64(2) rather than 2.
+func Match(fields map[string]interface{}) Filter {
+	return func(metadata map[string]interface{}) bool {
+		for key, want := range fields {
+			if got, ok := metadata[key]; !ok || !equal(got, want) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// equal compares metadata values with == where their type allows it, since
+// == panics on slices and maps
+func equal(a, b interface{}) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta == nil || ta.Comparable() && ta.Kind() != reflect.Struct && ta.Kind() != reflect.Array {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Index is an in-memory vector index. Implementations are safe for
+// concurrent use.
+type Index interface {
+	// Add inserts items, replacing items with the same ID
+	Add(items ...Item) error
+	// Delete removes items by ID; unknown IDs are ignored
+	Delete(ids ...string)
+	// Search returns up to k items closest to query that pass filter. A nil
+	// filter accepts every item.
+	Search(query []float32, k int, filter Filter) ([]Result, error)
+	// Len returns the number of items
+	Len() int
+	// Save writes the index to w; see SaveFile
+	Save(w io.Writer) error
+}
+
+// FromEmbeddings converts embeddings to items, matching each embedding to its
+// input by Embedding.Index. ids and metadata are indexed by input; a nil ids
+// uses the input index as the ID, and metadata may be nil.
+func FromEmbeddings(embeddings []gollmx.Embedding, ids []string, metadata []map[string]interface{}) ([]Item, error) {
+	items := make([]Item, len(embeddings))
+	for i, emb := range embeddings {
+		if len(emb.Vector) == 0 {
+			return nil, fmt.Errorf("vector: embedding %d has no float vector", emb.Index)
+		}
+		item := Item{ID: strconv.Itoa(emb.Index), Vector: ToFloat32(emb.Vector)}
+		if ids != nil {
+			if emb.Index < 0 || emb.Index >= len(ids) {
+				return nil, fmt.Errorf("vector: no ID for embedding %d", emb.Index)
+			}
+			item.ID = ids[emb.Index]
+		}
+		if emb.Index >= 0 && emb.Index < len(metadata) {
+			item.Metadata = metadata[emb.Index]
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// SaveFile writes index to path atomically
+func SaveFile(path string, index Index) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".vector-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := index.Save(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// =============================================================================
+// Flat Index
+// =============================================================================
+
+// Flat is an exact index that compares the query with every item
+type Flat struct {
+	mu     sync.RWMutex
+	metric Metric
+	dims   int
+	items  []Item         // Vectors prepared for the metric
+	byID   map[string]int // Position in items
+}
+
+// NewFlat creates an empty flat index
+func NewFlat(metric Metric) *Flat {
+	return &Flat{metric: metric, byID: make(map[string]int)}
+}
+
+// Add inserts items, replacing items with the same ID
+func (f *Flat) Add(items ...Item) error {
+	if err := f.metric.validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dims := f.dims
+	for _, item := range items {
+		if dims == 0 {
+			dims = len(item.Vector)
+		}
+		if len(item.Vector) == 0 || len(item.Vector) != dims {
+			return fmt.Errorf("%w: item %q has %d dimensions, want %d", ErrDimensionMismatch, item.ID, len(item.Vector), dims)
+		}
+	}
+	f.dims = dims
+
+	for _, item := range items {
+		item.Vector = f.metric.prepare(item.Vector)
+		if pos, ok := f.byID[item.ID]; ok {
+			f.items[pos] = item
+			continue
+		}
+		f.byID[item.ID] = len(f.items)
+		f.items = append(f.items, item)
+	}
+	return nil
+}
+
+// Delete removes items by ID
+func (f *Flat) Delete(ids ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, id := range ids {
+		pos, ok := f.byID[id]
+		if !ok {
+			continue
+		}
+		last := len(f.items) - 1
+		f.items[pos] = f.items[last]
+		f.byID[f.items[pos].ID] = pos
+		f.items = f.items[:last]
+		delete(f.byID, id)
+	}
+}
+
+// Search returns up to k items closest to query that pass filter
+func (f *Flat) Search(query []float32, k int, filter Filter) ([]Result, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if k <= 0 || len(f.items) == 0 {
+		return nil, nil
+	}
+	if len(query) != f.dims {
+		return nil, fmt.Errorf("%w: query has %d dimensions, want %d", ErrDimensionMismatch, len(query), f.dims)
+	}
+	query = f.metric.prepare(query)
+
+	// Keep the k closest in a max-heap so the farthest is replaced first
+	best := &candidates{max: true}
+	for i := range f.items {
+		item := &f.items[i]
+		if filter != nil && !filter(item.Metadata) {
+			continue
+		}
+		d := f.metric.distance(query, item.Vector)
+		if best.Len() < k {
+			heap.Push(best, candidate{id: i, distance: d})
+		} else if d < best.items[0].distance {
+			best.items[0] = candidate{id: i, distance: d}
+			heap.Fix(best, 0)
+		}
+	}
+
+	results := make([]Result, best.Len())
+	for i := len(results) - 1; i >= 0; i-- {
+		c := heap.Pop(best).(candidate)
+		item := f.items[c.id]
+		results[i] = Result{ID: item.ID, Score: f.metric.score(c.distance), Metadata: item.Metadata}
+	}
+	return results, nil
+}
+
+// Get returns the item with the given ID. For MetricCosine the vector is
+// normalized.
+func (f *Flat) Get(id string) (Item, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	pos, ok := f.byID[id]
+	if !ok {
+		return Item{}, false
+	}
+	return f.items[pos], true
+}
+
+// Len returns the number of items
+func (f *Flat) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.items)
+}
+
+// flatFile is the serialized form of a Flat index
+type flatFile struct {
+	Type   string `json:"type"`
+	Metric Metric `json:"metric"`
+	Items  []Item `json:"items"`
+}
+
+// Save writes the index to w as JSON
+func (f *Flat) Save(w io.Writer) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return json.NewEncoder(w).Encode(flatFile{Type: "flat", Metric: f.metric, Items: f.items})
+}
+
+// LoadFlat reads an index written by Flat.Save. Numeric metadata values are
+// restored as float64.
+func LoadFlat(r io.Reader) (*Flat, error) {
+	var file flatFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("vector: failed to decode index: %w", err)
+	}
+	if file.Type != "flat" {
+		return nil, fmt.Errorf("vector: expected a flat index, got %q", file.Type)
+	}
+	f := NewFlat(file.Metric)
+	if err := f.Add(file.Items...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// LoadFlatFile reads a flat index saved with SaveFile
+func LoadFlatFile(path string) (*Flat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadFlat(file)
+}
+
+// =============================================================================
+// Candidate Heap
+// =============================================================================
+
+// candidate is an item position and its distance to the query
+type candidate struct {
+	id       int
+	distance float64
+}
+
+// candidates is a heap of candidates, nearest first unless max is set
+type candidates struct {
+	items []candidate
+	max   bool
+}
+
+func (h *candidates) Len() int { return len(h.items) }
+
+func (h *candidates) Less(i, j int) bool {
+	if h.max {
+		return h.items[i].distance > h.items[j].distance
+	}
+	return h.items[i].distance < h.items[j].distance
+}
+
+func (h *candidates) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *candidates) Push(x interface{}) { h.items = append(h.items, x.(candidate)) }
+
+func (h *candidates) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// sorted returns the candidates nearest first
+func (h *candidates) sorted() []candidate {
+	out := append([]candidate(nil), h.items...)
+	sort.Slice(out, func(i, j int) bool { return out[i].distance < out[j].distance })
+	return out
+}
+
+// Ensure Flat implements Index
+var _ Index = (*Flat)(nil)
@@ -0,0 +1,200 @@
+package vector
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	gollmx "github.com/onlyhyde/gollm-x"
+)
+
+func testItems() []Item {
+	return []Item{
+		{ID: "east", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"lang": "en"}},
+		{ID: "north", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"lang": "fr"}},
+		{ID: "northeast", Vector: []float32{1, 1}, Metadata: map[string]interface{}{"lang": "en"}},
+		{ID: "west", Vector: []float32{-1, 0}, Metadata: map[string]interface{}{"lang": "fr"}},
+	}
+}
+
+func resultIDs(results []Result) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestFlatSearch(t *testing.T) {
+	index := NewFlat(MetricCosine)
+	if err := index.Add(testItems()...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, err := index.Search([]float32{2, 0.1}, 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := resultIDs(results)
+	if len(ids) != 2 || ids[0] != "east" || ids[1] != "northeast" {
+		t.Errorf("expected [east northeast], got %v", ids)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected scores in descending order, got %v", results)
+	}
+}
+
+func TestFlatMetrics(t *testing.T) {
+	for _, tt := range []struct {
+		metric Metric
+		want   string
+	}{
+		{MetricDot, "far"},
+		{MetricL2, "near"},
+	} {
+		index := NewFlat(tt.metric)
+		index.Add(Item{ID: "near", Vector: []float32{1, 0}}, Item{ID: "far", Vector: []float32{10, 0}})
+
+		results, err := index.Search([]float32{1, 0}, 1, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if results[0].ID != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.metric, tt.want, results[0].ID)
+		}
+	}
+}
+
+func TestFlatFilter(t *testing.T) {
+	index := NewFlat(MetricCosine)
+	index.Add(testItems()...)
+
+	results, err := index.Search([]float32{1, 0}, 10, Match(map[string]interface{}{"lang": "fr"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := resultIDs(results)
+	if len(ids) != 2 || ids[0] != "north" || ids[1] != "west" {
+		t.Errorf("expected [north west], got %v", ids)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	metadata := map[string]interface{}{
+		"lang":  "en",
+		"tags":  []string{"faq", "billing"},
+		"owner": map[string]interface{}{"team": "support"},
+		"page":  float64(2),
+	}
+
+	tests := []struct {
+		fields map[string]interface{}
+		want   bool
+	}{
+		{map[string]interface{}{"tags": []string{"faq", "billing"}}, true},
+		{map[string]interface{}{"tags": []string{"faq"}}, false},
+		{map[string]interface{}{"owner": map[string]interface{}{"team": "support"}}, true},
+		{map[string]interface{}{"lang": "en", "page": float64(2)}, true},
+		{map[string]interface{}{"page": 2}, false}, // Types must match
+		{map[string]interface{}{"lang": []string{"en"}}, false},
+		{map[string]interface{}{"missing": nil}, false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.fields)(metadata); got != tt.want {
+			t.Errorf("Match(%v): expected %v, got %v", tt.fields, tt.want, got)
+		}
+	}
+}
+
+func TestFlatAddReplaceAndDelete(t *testing.T) {
+	index := NewFlat(MetricCosine)
+	index.Add(testItems()...)
+
+	index.Add(Item{ID: "west", Vector: []float32{1, 0.01}})
+	if index.Len() != 4 {
+		t.Errorf("expected replacing an item to keep 4 items, got %d", index.Len())
+	}
+
+	index.Delete("east", "missing")
+	if index.Len() != 3 {
+		t.Errorf("expected 3 items after delete, got %d", index.Len())
+	}
+	if _, ok := index.Get("east"); ok {
+		t.Error("expected deleted item to be gone")
+	}
+
+	results, _ := index.Search([]float32{1, 0}, 1, nil)
+	if results[0].ID != "west" {
+		t.Errorf("expected the replaced vector to be searched, got %s", results[0].ID)
+	}
+}
+
+func TestFlatDimensionMismatch(t *testing.T) {
+	index := NewFlat(MetricCosine)
+	index.Add(testItems()...)
+
+	if err := index.Add(Item{ID: "bad", Vector: []float32{1, 2, 3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch on add, got %v", err)
+	}
+	if _, err := index.Search([]float32{1}, 1, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch on search, got %v", err)
+	}
+	if err := NewFlat("manhattan").Add(testItems()...); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
+
+func TestFlatSaveLoad(t *testing.T) {
+	index := NewFlat(MetricL2)
+	index.Add(testItems()...)
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := SaveFile(path, index); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := LoadFlatFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Len() != 4 {
+		t.Fatalf("expected 4 items, got %d", loaded.Len())
+	}
+
+	results, _ := loaded.Search([]float32{0, 2}, 1, nil)
+	if results[0].ID != "north" || results[0].Metadata["lang"] != "fr" {
+		t.Errorf("expected north with metadata, got %+v", results[0])
+	}
+
+	var buf bytes.Buffer
+	NewHNSW(MetricL2).Save(&buf)
+	if _, err := LoadFlat(&buf); err == nil {
+		t.Error("expected an error loading an hnsw index as flat")
+	}
+}
+
+func TestFromEmbeddings(t *testing.T) {
+	embeddings := []gollmx.Embedding{
+		{Index: 1, Vector: []float64{0, 1}},
+		{Index: 0, Vector: []float64{1, 0}},
+	}
+	ids := []string{"doc-a", "doc-b"}
+	metadata := []map[string]interface{}{{"n": 0}, {"n": 1}}
+
+	items, err := FromEmbeddings(embeddings, ids, metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].ID != "doc-b" || items[0].Metadata["n"] != 1 || items[0].Vector[1] != 1 {
+		t.Errorf("expected embedding 1 to map to doc-b, got %+v", items[0])
+	}
+
+	items, _ = FromEmbeddings(embeddings, nil, nil)
+	if items[0].ID != "1" || items[1].ID != "0" {
+		t.Errorf("expected input indices as IDs, got %s and %s", items[0].ID, items[1].ID)
+	}
+
+	if _, err := FromEmbeddings([]gollmx.Embedding{{Index: 0}}, nil, nil); err == nil {
+		t.Error("expected an error for an embedding without a float vector")
+	}
+}
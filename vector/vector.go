@@ -0,0 +1,143 @@
+// Package vector provides similarity functions and in-memory vector indexes
+// for embeddings.
+//
+//	resp, err := client.Embed(ctx, &gollmx.EmbedRequest{Input: texts})
+//	items, err := vector.FromEmbeddings(resp.Embeddings, ids, nil)
+//
+//	index := vector.NewFlat(vector.MetricCosine)
+//	index.Add(items...)
+//	results, err := index.Search(vector.ToFloat32(query), 5, nil)
+//
+// Flat searches exhaustively and is exact; HNSW trades a little recall for
+// much faster searches over large corpora. Both can be saved to disk.
+package vector
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/onlyhyde/gollm-x/internal/vecmath"
+)
+
+// Float is a floating point element type
+type Float interface {
+	~float32 | ~float64
+}
+
+// Dot returns the dot product of a and b. It panics if their lengths differ.
+func Dot[T Float](a, b []T) float64 {
+	checkLengths(len(a), len(b))
+	return vecmath.Dot(a, b)
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if either is a zero
+// vector. It panics if their lengths differ.
+func Cosine[T Float](a, b []T) float64 {
+	checkLengths(len(a), len(b))
+	return vecmath.Cosine(a, b)
+}
+
+// L2 returns the Euclidean distance between a and b. It panics if their
+// lengths differ.
+func L2[T Float](a, b []T) float64 {
+	return math.Sqrt(SquaredL2(a, b))
+}
+
+// SquaredL2 returns the squared Euclidean distance between a and b, which
+// orders vectors like L2 without the square root. It panics if their lengths
+// differ.
+func SquaredL2[T Float](a, b []T) float64 {
+	checkLengths(len(a), len(b))
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return sum
+}
+
+// Norm returns the Euclidean length of v
+func Norm[T Float](v []T) float64 {
+	return vecmath.Norm(v)
+}
+
+// Normalize returns a copy of v scaled to unit length. A zero vector is
+// returned unchanged.
+func Normalize[T Float](v []T) []T {
+	out, _ := vecmath.Normalize(v)
+	return out
+}
+
+// ToFloat32 converts v to float32, e.g. to index an Embedding.Vector
+func ToFloat32(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(x)
+	}
+	return out
+}
+
+// ToFloat64 converts v to float64
+func ToFloat64(v []float32) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return out
+}
+
+func checkLengths(a, b int) {
+	if a != b {
+		panic(fmt.Sprintf("vector: length mismatch (%d != %d)", a, b))
+	}
+}
+
+// Metric is the similarity measure used by an index
+type Metric string
+
+const (
+	MetricCosine Metric = "cosine" // Cosine similarity; higher is closer
+	MetricDot    Metric = "dot"    // Dot product; higher is closer
+	MetricL2     Metric = "l2"     // Euclidean distance; lower is closer
+)
+
+// distance returns a value that is lower for closer vectors. For
+// MetricCosine the vectors are normalized when indexed, so the dot product is
+// the cosine similarity.
+func (m Metric) distance(a, b []float32) float64 {
+	switch m {
+	case MetricL2:
+		return SquaredL2(a, b)
+	default:
+		return -Dot(a, b)
+	}
+}
+
+// score converts a distance to the score reported in a Result
+func (m Metric) score(distance float64) float64 {
+	switch m {
+	case MetricL2:
+		return math.Sqrt(distance)
+	default:
+		return -distance
+	}
+}
+
+// prepare returns the vector to store or search with for the metric
+func (m Metric) prepare(v []float32) []float32 {
+	if m == MetricCosine {
+		return Normalize(v)
+	}
+	out := make([]float32, len(v))
+	copy(out, v)
+	return out
+}
+
+func (m Metric) validate() error {
+	switch m {
+	case MetricCosine, MetricDot, MetricL2:
+		return nil
+	default:
+		return fmt.Errorf("vector: unknown metric %q", m)
+	}
+}
@@ -0,0 +1,52 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestSimilarity(t *testing.T) {
+	a := []float64{1, 2, 3}
+	b := []float64{4, 5, 6}
+
+	if got := Dot(a, b); got != 32 {
+		t.Errorf("expected dot 32, got %v", got)
+	}
+	if got := Cosine(a, b); !approx(got, 32/(math.Sqrt(14)*math.Sqrt(77))) {
+		t.Errorf("unexpected cosine %v", got)
+	}
+	if got := L2(a, b); !approx(got, math.Sqrt(27)) {
+		t.Errorf("expected l2 %v, got %v", math.Sqrt(27), got)
+	}
+	if got := Cosine([]float32{0, 0}, []float32{1, 1}); got != 0 {
+		t.Errorf("expected cosine 0 for a zero vector, got %v", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := Normalize([]float32{3, 4})
+	if !approx(float64(v[0]), 0.6) || !approx(float64(v[1]), 0.8) {
+		t.Errorf("expected [0.6 0.8], got %v", v)
+	}
+	if !approx(Norm(v), 1) {
+		t.Errorf("expected unit length, got %v", Norm(v))
+	}
+
+	zero := Normalize([]float64{0, 0})
+	if zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("expected a zero vector to stay zero, got %v", zero)
+	}
+}
+
+func TestLengthMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for mismatched lengths")
+		}
+	}()
+	Dot([]float64{1}, []float64{1, 2})
+}